@@ -5,11 +5,16 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"github.com/toutaio/toutago-inertia/pkg/typegen"
 )
 
 func main() {
 	output := flag.String("output", "types/inertia.d.ts", "Output TypeScript file path")
 	pkg := flag.String("package", "", "Go package path to scan")
+	check := flag.Bool("check", false,
+		"Verify the output file is up to date instead of writing it, exiting 1 when it is stale "+
+			"(package scanning is not implemented yet, so this compares against the placeholder output)")
 	flag.Parse()
 
 	if *pkg == "" {
@@ -21,13 +26,6 @@ func main() {
 	fmt.Printf("Scanning package: %s\n", *pkg)
 	fmt.Printf("Output file: %s\n", *output)
 
-	// Create output directory if it doesn't exist
-	dir := filepath.Dir(*output)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		fmt.Fprintf(os.Stderr, "Error creating output directory: %v\n", err)
-		os.Exit(1)
-	}
-
 	// TODO: Implement package scanning and type generation
 	// For now, write a placeholder
 	content := `// Auto-generated TypeScript types from Go structs
@@ -37,6 +35,22 @@ func main() {
 // TODO: Implement automatic type generation
 `
 
+	if *check {
+		if err := typegen.VerifyFile(*output, content); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("TypeScript types are up to date.")
+		return
+	}
+
+	// Create output directory if it doesn't exist
+	dir := filepath.Dir(*output)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating output directory: %v\n", err)
+		os.Exit(1)
+	}
+
 	if err := os.WriteFile(*output, []byte(content), 0600); err != nil {
 		fmt.Fprintf(os.Stderr, "Error writing output file: %v\n", err)
 		os.Exit(1)
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"encoding/json"
 	"net/http"
 
 	"github.com/toutaio/toutago-inertia/pkg/inertia"
@@ -22,14 +21,11 @@ func main() {
 
 	mux := http.NewServeMux()
 
-	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		page, _ := mgr.Render("Home", map[string]interface{}{
+	mux.Handle("/", mgr.Handler("Home", func(_ *http.Request) (inertia.Props, error) {
+		return inertia.Props{
 			"greeting": "Welcome!",
-		}, r.URL.Path)
-
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(page)
-	})
+		}, nil
+	}))
 
 	handler := mgr.Middleware()(mux)
 	http.ListenAndServe(":3000", handler)
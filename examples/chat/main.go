@@ -47,18 +47,15 @@ func main() {
 	mux := http.NewServeMux()
 
 	// Routes
-	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+	mux.Handle("/", mgr.Handler("Chat", func(_ *http.Request) (inertia.Props, error) {
 		messagesMu.RLock()
 		msgs := messages
 		messagesMu.RUnlock()
 
-		page, _ := mgr.Render("Chat", map[string]interface{}{
+		return inertia.Props{
 			"messages": msgs,
-		}, r.URL.Path)
-
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(page)
-	})
+		}, nil
+	}))
 
 	mux.HandleFunc("/messages", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
 	"net/http"
 	"time"
@@ -102,24 +101,17 @@ func main() {
 
 		// POST - create user
 		var input struct {
-			Name  string `json:"name"`
-			Email string `json:"email"`
+			Name  string `json:"name" validate:"required"`
+			Email string `json:"email" validate:"required,email"`
 		}
 
-		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		errors, err := inertia.Bind(r, &input)
+		if err != nil {
 			ictx.Error(400, "Invalid input")
 			return
 		}
 
-		errors := inertia.ValidationErrors{}
-		if input.Name == "" {
-			errors["name"] = []string{"Name is required"}
-		}
-		if input.Email == "" {
-			errors["email"] = []string{"Email is required"}
-		}
-
-		if len(errors) > 0 {
+		if errors.Any() {
 			ictx.WithErrors(errors).Render("Users/Create", map[string]interface{}{
 				"oldInput": input,
 			})
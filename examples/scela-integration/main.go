@@ -19,9 +19,13 @@ func main() {
 	defer bus.Close()
 
 	// Create Inertia instance
-	irt := inertia.New()
-	irt.SetVersion("1.0.0")
-	irt.SetRootTemplate("app.html")
+	irt, err := inertia.New(inertia.Config{
+		RootView: "app.html",
+		Version:  "1.0.0",
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	// Create WebSocket hub for real-time updates
 	hub := realtime.NewHub()
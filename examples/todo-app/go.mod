@@ -1,6 +1,6 @@
 module github.com/toutaio/toutago-inertia/examples/todo-app
 
-go 1.21
+go 1.24.0
 
 require (
 	github.com/toutaio/toutago-cosan-router v0.1.0
@@ -1,9 +1,9 @@
 package handlers
 
 import (
-	"net/http"
-
+	"github.com/toutaio/toutago-cosan-router/pkg/cosan"
 	"github.com/toutaio/toutago-inertia/pkg/inertia"
+	inertiacosan "github.com/toutaio/toutago-inertia/pkg/inertia/cosan"
 )
 
 type AdminDashboardProps struct {
@@ -13,14 +13,14 @@ type AdminDashboardProps struct {
 	} `json:"stats"`
 }
 
-func AdminDashboard(i *inertia.Inertia) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		ctx := inertia.NewContext(w, r, i)
+func AdminDashboard(adapter *inertia.Inertia) cosan.HandlerFunc {
+	return func(c cosan.Context) error {
+		ctx := inertiacosan.New(c, adapter)
 
 		props := AdminDashboardProps{}
 		props.Stats.Users = 42  // Mock data
 		props.Stats.Todos = 128 // Mock data
 
-		ctx.Render("admin/Dashboard", props)
+		return ctx.Inertia("admin/Dashboard", inertia.Props{"stats": props.Stats})
 	}
 }
@@ -1,10 +1,17 @@
 package handlers
 
 import (
-	"github.com/toutaio/toutago-cosan-router"
-	"github.com/toutaio/toutago-inertia"
+	"net/http"
+	"strconv"
+
+	"github.com/toutaio/toutago-cosan-router/pkg/cosan"
+	"github.com/toutaio/toutago-inertia/pkg/inertia"
+	inertiacosan "github.com/toutaio/toutago-inertia/pkg/inertia/cosan"
 )
 
+// userCookie holds the ID of the logged in user (mock authentication)
+const userCookie = "user_id"
+
 // LoginPageProps defines props for the login page
 type LoginPageProps struct {
 	Flash map[string]string `json:"flash,omitempty"`
@@ -12,10 +19,10 @@ type LoginPageProps struct {
 
 // HandleLoginShow shows the login page
 func HandleLoginShow(adapter *inertia.Inertia) cosan.HandlerFunc {
-	return func(ctx *cosan.Context) error {
-		return ctx.Inertia("Auth/Login", inertia.Props{
-			"flash": ctx.Session().Flash(),
-		})
+	return func(c cosan.Context) error {
+		ctx := inertiacosan.New(c, adapter)
+		ctx.InertiaContext().WithSessionFlash()
+		return ctx.Inertia("Auth/Login", inertia.Props{})
 	}
 }
 
@@ -27,9 +34,10 @@ type LoginInput struct {
 
 // HandleLoginSubmit handles login form submission
 func HandleLoginSubmit(adapter *inertia.Inertia) cosan.HandlerFunc {
-	return func(ctx *cosan.Context) error {
+	return func(c cosan.Context) error {
+		ctx := inertiacosan.New(c, adapter)
 		var input LoginInput
-		if err := ctx.BindJSON(&input); err != nil {
+		if err := c.Bind(&input); err != nil {
 			return ctx.InertiaValidationErrors(map[string]string{
 				"email": "Invalid input",
 			})
@@ -57,8 +65,16 @@ func HandleLoginSubmit(adapter *inertia.Inertia) cosan.HandlerFunc {
 		}
 
 		// Set session
-		ctx.Session().Set("user_id", 1)
-		ctx.Session().Flash("success", "Logged in successfully!")
+		http.SetCookie(c.Response(), &http.Cookie{
+			Name:     userCookie,
+			Value:    strconv.Itoa(1),
+			Path:     "/",
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
+		if err := flash(c, adapter, "success", "Logged in successfully!"); err != nil {
+			return err
+		}
 
 		return ctx.InertiaRedirect("/")
 	}
@@ -66,9 +82,12 @@ func HandleLoginSubmit(adapter *inertia.Inertia) cosan.HandlerFunc {
 
 // HandleLogout handles logout
 func HandleLogout(adapter *inertia.Inertia) cosan.HandlerFunc {
-	return func(ctx *cosan.Context) error {
-		ctx.Session().Delete("user_id")
-		ctx.Session().Flash("success", "Logged out successfully!")
+	return func(c cosan.Context) error {
+		ctx := inertiacosan.New(c, adapter)
+		http.SetCookie(c.Response(), &http.Cookie{Name: userCookie, Path: "/", MaxAge: -1})
+		if err := flash(c, adapter, "success", "Logged out successfully!"); err != nil {
+			return err
+		}
 		return ctx.InertiaRedirect("/login")
 	}
 }
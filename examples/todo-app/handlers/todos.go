@@ -1,9 +1,13 @@
 package handlers
 
 import (
-	"github.com/toutaio/toutago-cosan-router"
-	"github.com/toutaio/toutago-inertia"
+	"net/http"
+	"strconv"
+
+	"github.com/toutaio/toutago-cosan-router/pkg/cosan"
 	"github.com/toutaio/toutago-inertia/examples/todo-app/models"
+	"github.com/toutaio/toutago-inertia/pkg/inertia"
+	inertiacosan "github.com/toutaio/toutago-inertia/pkg/inertia/cosan"
 )
 
 // HomePageProps defines props for the home page
@@ -21,10 +25,11 @@ type User struct {
 
 // HandleHome handles the home page
 func HandleHome(adapter *inertia.Inertia) cosan.HandlerFunc {
-	return func(ctx *cosan.Context) error {
+	return func(c cosan.Context) error {
+		ctx := inertiacosan.New(c, adapter)
 		return ctx.Inertia("Home", inertia.Props{
 			"greeting": "Welcome to Toutago + Inertia!",
-			"user":     getCurrentUser(ctx),
+			"user":     getCurrentUser(c),
 		})
 	}
 }
@@ -38,18 +43,22 @@ type TodosListPageProps struct {
 
 // HandleTodosList handles the todos list page
 func HandleTodosList(adapter *inertia.Inertia) cosan.HandlerFunc {
-	return func(ctx *cosan.Context) error {
+	return func(c cosan.Context) error {
+		ctx := inertiacosan.New(c, adapter)
 		filter := models.TodosFilter{
-			Status: ctx.Query("status", "all"),
-			Search: ctx.Query("search", ""),
+			Status: c.Query("status"),
+			Search: c.Query("search"),
+		}
+		if filter.Status == "" {
+			filter.Status = "all"
 		}
 
 		todos := models.GetAll(filter)
 
+		ctx.InertiaContext().WithSessionFlash()
 		return ctx.Inertia("Todos/Index", inertia.Props{
 			"todos":  todos,
 			"filter": filter,
-			"flash":  ctx.Session().Flash(),
 		})
 	}
 }
@@ -62,9 +71,10 @@ type TodosCreateInput struct {
 
 // HandleTodosCreate handles creating a new todo
 func HandleTodosCreate(adapter *inertia.Inertia) cosan.HandlerFunc {
-	return func(ctx *cosan.Context) error {
+	return func(c cosan.Context) error {
+		ctx := inertiacosan.New(c, adapter)
 		var input TodosCreateInput
-		if err := ctx.BindJSON(&input); err != nil {
+		if err := c.Bind(&input); err != nil {
 			return ctx.InertiaValidationErrors(map[string]string{
 				"title": "Invalid input",
 			})
@@ -85,10 +95,12 @@ func HandleTodosCreate(adapter *inertia.Inertia) cosan.HandlerFunc {
 		})
 
 		if todo == nil {
-			return ctx.InertiaError(500, "Failed to create todo")
+			return ctx.InertiaError(http.StatusInternalServerError, "Failed to create todo")
 		}
 
-		ctx.Session().Flash("success", "Todo created successfully!")
+		if err := flash(c, adapter, "success", "Todo created successfully!"); err != nil {
+			return err
+		}
 		return ctx.InertiaRedirect("/todos")
 	}
 }
@@ -102,11 +114,12 @@ type TodosUpdateInput struct {
 
 // HandleTodosUpdate handles updating a todo
 func HandleTodosUpdate(adapter *inertia.Inertia) cosan.HandlerFunc {
-	return func(ctx *cosan.Context) error {
-		id := ctx.ParamInt("id")
+	return func(c cosan.Context) error {
+		ctx := inertiacosan.New(c, adapter)
+		id, _ := strconv.Atoi(c.Param("id"))
 
 		var input TodosUpdateInput
-		if err := ctx.BindJSON(&input); err != nil {
+		if err := c.Bind(&input); err != nil {
 			return ctx.InertiaValidationErrors(map[string]string{
 				"title": "Invalid input",
 			})
@@ -119,24 +132,29 @@ func HandleTodosUpdate(adapter *inertia.Inertia) cosan.HandlerFunc {
 		})
 
 		if todo == nil {
-			return ctx.InertiaError(404, "Todo not found")
+			return ctx.InertiaError(http.StatusNotFound, "Todo not found")
 		}
 
-		ctx.Session().Flash("success", "Todo updated successfully!")
+		if err := flash(c, adapter, "success", "Todo updated successfully!"); err != nil {
+			return err
+		}
 		return ctx.InertiaRedirect("/todos")
 	}
 }
 
 // HandleTodosDelete handles deleting a todo
 func HandleTodosDelete(adapter *inertia.Inertia) cosan.HandlerFunc {
-	return func(ctx *cosan.Context) error {
-		id := ctx.ParamInt("id")
+	return func(c cosan.Context) error {
+		ctx := inertiacosan.New(c, adapter)
+		id, _ := strconv.Atoi(c.Param("id"))
 
 		if !models.Delete(id) {
-			return ctx.InertiaError(404, "Todo not found")
+			return ctx.InertiaError(http.StatusNotFound, "Todo not found")
 		}
 
-		ctx.Session().Flash("success", "Todo deleted successfully!")
+		if err := flash(c, adapter, "success", "Todo deleted successfully!"); err != nil {
+			return err
+		}
 		return ctx.InertiaRedirect("/todos")
 	}
 }
@@ -149,32 +167,45 @@ type TodosEditPageProps struct {
 
 // HandleTodosEdit handles the todo edit page
 func HandleTodosEdit(adapter *inertia.Inertia) cosan.HandlerFunc {
-	return func(ctx *cosan.Context) error {
-		id := ctx.ParamInt("id")
+	return func(c cosan.Context) error {
+		ctx := inertiacosan.New(c, adapter)
+		id, _ := strconv.Atoi(c.Param("id"))
 		todo := models.GetByID(id)
 
 		if todo == nil {
-			ctx.Session().Flash("error", "Todo not found")
+			if err := flash(c, adapter, "error", "Todo not found"); err != nil {
+				return err
+			}
 			return ctx.InertiaRedirect("/todos")
 		}
 
+		ctx.InertiaContext().WithSessionFlash()
 		return ctx.Inertia("Todos/Edit", inertia.Props{
-			"todo":  todo,
-			"flash": ctx.Session().Flash(),
+			"todo": todo,
 		})
 	}
 }
 
+// flash stores a flash message for the next page, which exposes it as the
+// "flash" prop (see InertiaContext.WithSessionFlash)
+func flash(c cosan.Context, adapter *inertia.Inertia, kind, message string) error {
+	return adapter.SessionStore().Flash(c.Response(), c.Request(), "flash", map[string]string{kind: message})
+}
+
 // Helper to get current user (mock implementation)
-func getCurrentUser(ctx *cosan.Context) *User {
+func getCurrentUser(c cosan.Context) *User {
 	// In real app, get from session
-	userID := ctx.Session().Get("user_id")
-	if userID == nil {
+	cookie, err := c.Request().Cookie(userCookie)
+	if err != nil {
+		return nil
+	}
+	userID, err := strconv.Atoi(cookie.Value)
+	if err != nil {
 		return nil
 	}
 
 	return &User{
-		ID:    userID.(int),
+		ID:    userID,
 		Name:  "John Doe",
 		Email: "john@example.com",
 	}
@@ -5,48 +5,52 @@ import (
 	"net/http"
 	"time"
 
-	"github.com/toutaio/toutago-cosan-router"
-	"github.com/toutaio/toutago-inertia"
+	"github.com/toutaio/toutago-cosan-router/pkg/cosan"
 	"github.com/toutaio/toutago-inertia/examples/todo-app/handlers"
 	"github.com/toutaio/toutago-inertia/examples/todo-app/models"
+	"github.com/toutaio/toutago-inertia/pkg/inertia"
 )
 
 func main() {
 	// Initialize Inertia
-	inertiaAdapter := inertia.New(inertia.Config{
-		RootView:     "app",
-		Version:      "1.0.0",
-		SSREnabled:   true,
-		SSRURL:       "http://localhost:13714",
-		AssetURL:     "/build",
-		ManifestPath: "public/build/manifest.json",
+	mgr, err := inertia.New(inertia.Config{
+		RootView: "templates/app.html",
+		Version:  "1.0.0",
+		AssetURL: "/build",
 	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Flash messages and validation errors survive redirects through the session store
+	mgr.SetSessionStore(inertia.NewMemorySessionStore())
 
 	// Create router
 	router := cosan.New()
 
-	// Apply Inertia middleware
-	router.Use(inertia.Middleware(inertiaAdapter))
-
-	// Static files
-	router.Static("/build", "./public/build")
-	router.Static("/assets", "./public/assets")
-
 	// Routes
-	router.GET("/", handlers.HandleHome(inertiaAdapter))
-	router.GET("/todos", handlers.HandleTodosList(inertiaAdapter))
-	router.POST("/todos", handlers.HandleTodosCreate(inertiaAdapter))
-	router.PUT("/todos/:id", handlers.HandleTodosUpdate(inertiaAdapter))
-	router.DELETE("/todos/:id", handlers.HandleTodosDelete(inertiaAdapter))
-	router.GET("/todos/:id/edit", handlers.HandleTodosEdit(inertiaAdapter))
+	router.GET("/", handlers.HandleHome(mgr))
+	router.GET("/todos", handlers.HandleTodosList(mgr))
+	router.POST("/todos", handlers.HandleTodosCreate(mgr))
+	router.PUT("/todos/:id", handlers.HandleTodosUpdate(mgr))
+	router.DELETE("/todos/:id", handlers.HandleTodosDelete(mgr))
+	router.GET("/todos/:id/edit", handlers.HandleTodosEdit(mgr))
 
 	// Auth routes
-	router.GET("/login", handlers.HandleLoginShow(inertiaAdapter))
-	router.POST("/login", handlers.HandleLoginSubmit(inertiaAdapter))
-	router.POST("/logout", handlers.HandleLogout(inertiaAdapter))
+	router.GET("/login", handlers.HandleLoginShow(mgr))
+	router.POST("/login", handlers.HandleLoginSubmit(mgr))
+	router.POST("/logout", handlers.HandleLogout(mgr))
 
 	// Admin routes (demonstrating nested layouts)
-	router.GET("/admin/dashboard", handlers.AdminDashboard(inertiaAdapter))
+	router.GET("/admin/dashboard", handlers.AdminDashboard(mgr))
+
+	// Static files
+	mux := http.NewServeMux()
+	mux.Handle("/build/", http.StripPrefix("/build/", http.FileServer(http.Dir("./public/build"))))
+	mux.Handle("/assets/", http.StripPrefix("/assets/", http.FileServer(http.Dir("./public/assets"))))
+
+	// Apply Inertia middleware
+	mux.Handle("/", mgr.Middleware()(router))
 
 	// Initialize sample data
 	models.InitSampleTodos()
@@ -54,7 +58,7 @@ func main() {
 	// Start server
 	srv := &http.Server{
 		Addr:         ":3000",
-		Handler:      router,
+		Handler:      mux,
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 	}
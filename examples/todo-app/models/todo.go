@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"sync"
 	"time"
 )
@@ -52,6 +53,8 @@ func GetAll(filter TodosFilter) []*Todo {
 	todosmu.RLock()
 	defer todosmu.RUnlock()
 
+	search := strings.ToLower(strings.TrimSpace(filter.Search))
+
 	var result []*Todo
 	for _, todo := range todos {
 		// Apply status filter
@@ -62,10 +65,8 @@ func GetAll(filter TodosFilter) []*Todo {
 			continue
 		}
 
-		// Apply search filter
-		if filter.Search != "" {
-			// Simple case-insensitive search
-			// In production, use proper search library
+		// Apply search filter (case-insensitive substring match)
+		if search != "" && !todo.matches(search) {
 			continue
 		}
 
@@ -75,6 +76,12 @@ func GetAll(filter TodosFilter) []*Todo {
 	return result
 }
 
+// matches reports whether the title or description contains the lowercased search term
+func (t *Todo) matches(search string) bool {
+	return strings.Contains(strings.ToLower(t.Title), search) ||
+		strings.Contains(strings.ToLower(t.Description), search)
+}
+
 // GetByID returns a todo by ID
 func GetByID(id int) *Todo {
 	todosmu.RLock()
@@ -0,0 +1,33 @@
+package models
+
+import "testing"
+
+func TestGetAllSearch(t *testing.T) {
+	todosmu.Lock()
+	todos = make(map[int]*Todo)
+	nextID = 1
+	todosmu.Unlock()
+
+	InitSampleTodos()
+
+	tests := []struct {
+		name   string
+		filter TodosFilter
+		want   int
+	}{
+		{"no search returns all", TodosFilter{}, 3},
+		{"matches title case-insensitively", TodosFilter{Search: "LEARN"}, 1},
+		{"matches description", TodosFilter{Search: "production server"}, 1},
+		{"matches title or description", TodosFilter{Search: "toutago"}, 2},
+		{"no matches", TodosFilter{Search: "groceries"}, 0},
+		{"combines with status filter", TodosFilter{Status: "completed", Search: "toutago"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := len(GetAll(tt.filter)); got != tt.want {
+				t.Errorf("GetAll(%+v) returned %d todos, want %d", tt.filter, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package inertia_test
 
 import (
+	"bytes"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -199,3 +201,49 @@ func BenchmarkHTMXPartial(b *testing.B) {
 		}
 	}
 }
+
+// streamMarshal is an alternative JSON marshaler built on json.Encoder, standing in
+// for a third-party encoder such as jsoniter.
+func streamMarshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// BenchmarkRenderCustomMarshaler benchmarks rendering with a pluggable JSON marshaler.
+func BenchmarkRenderCustomMarshaler(b *testing.B) {
+	config := inertia.Config{
+		RootView: "app.html",
+		Version:  "1.0.0",
+	}
+
+	mgr, err := inertia.New(config)
+	if err != nil {
+		b.Fatal(err)
+	}
+	mgr.SetJSONMarshaler(streamMarshal)
+
+	req := httptest.NewRequest("GET", "/users", http.NoBody)
+	req.Header.Set("X-Inertia", "true")
+
+	props := map[string]interface{}{
+		"users": []map[string]string{
+			{"name": "John", "email": "john@example.com"},
+			{"name": "Jane", "email": "jane@example.com"},
+		},
+		"total": 2,
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		w := httptest.NewRecorder()
+		ctx := NewMockContext(w, req)
+		ic := inertia.NewContext(ctx, mgr)
+
+		if err := ic.Render("Users/Index", props); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
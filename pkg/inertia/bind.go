@@ -0,0 +1,334 @@
+package inertia
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/mail"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// defaultMaxMemory is the maximum number of bytes of a multipart body kept in memory.
+const defaultMaxMemory = 32 << 20 // 32 MB
+
+// Bind decodes the request body into dst and validates it using `validate` struct tags.
+//
+// JSON bodies are decoded with encoding/json. Form-encoded and multipart bodies are
+// mapped onto struct fields using the `form` tag, falling back to the `json` tag name.
+// The returned ValidationErrors are keyed by the field's JSON name and are nil when
+// the input is valid. A non-nil error is returned when the body cannot be decoded.
+//
+// Supported rules: required, min, max, len, email, oneof.
+//
+// Multipart bodies keep up to 32 MB in memory; use Inertia.Bind to apply
+// Config.MaxMemory instead.
+func Bind(r *http.Request, dst interface{}) (ValidationErrors, error) {
+	return bind(r, dst, defaultMaxMemory)
+}
+
+// Bind is like the package-level Bind, but keeps at most Config.MaxMemory bytes
+// of a multipart body in memory.
+func (i *Inertia) Bind(r *http.Request, dst interface{}) (ValidationErrors, error) {
+	return bind(r, dst, i.maxMemory())
+}
+
+// bind decodes and validates the request body, keeping at most maxMemory bytes
+// of a multipart body in memory.
+func bind(r *http.Request, dst interface{}, maxMemory int64) (ValidationErrors, error) {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return nil, errors.New("inertia: Bind destination must be a non-nil pointer to a struct")
+	}
+
+	if err := decodeBody(r, dst, maxMemory); err != nil {
+		return nil, err
+	}
+
+	return validateStruct(rv.Elem()), nil
+}
+
+// Validator is implemented by request structs that validate themselves after binding.
+type Validator interface {
+	Validate() ValidationErrors
+}
+
+// ValidateAndBind binds the request body into dst and validates it with both its
+// `validate` struct tags and its Validate method.
+//
+// It returns true when the input is valid so the handler can proceed. Otherwise the
+// errors are attached to the context, the response is sent (Back() for invalid input,
+// a 400 error page for an undecodable body) and false is returned.
+//
+//	var input CreateTodoRequest
+//	if !ic.ValidateAndBind(&input) {
+//		return nil
+//	}
+func (ic *InertiaContext) ValidateAndBind(dst Validator) bool {
+	errs, err := ic.mgr.Bind(ic.ctx.Request(), dst)
+	if err != nil {
+		_ = ic.Error(http.StatusBadRequest, "Invalid request body")
+		return false
+	}
+
+	for field, messages := range dst.Validate() {
+		if errs == nil {
+			errs = NewValidationErrors()
+		}
+		for _, message := range messages {
+			errs.Add(field, message)
+		}
+	}
+
+	if !errs.Any() {
+		return true
+	}
+
+	ic.WithErrors(errs)
+	_ = ic.Back()
+	return false
+}
+
+// decodeBody decodes the request body based on its Content-Type.
+func decodeBody(r *http.Request, dst interface{}, maxMemory int64) error {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	switch mediaType {
+	case "multipart/form-data":
+		if err := r.ParseMultipartForm(maxMemory); err != nil {
+			return fmt.Errorf("inertia: failed to parse multipart form: %w", err)
+		}
+		return decodeForm(r.MultipartForm.Value, dst)
+	case "application/x-www-form-urlencoded":
+		if err := r.ParseForm(); err != nil {
+			return fmt.Errorf("inertia: failed to parse form: %w", err)
+		}
+		return decodeForm(r.PostForm, dst)
+	default:
+		if r.Body == nil || r.Body == http.NoBody {
+			return nil
+		}
+		if err := json.NewDecoder(r.Body).Decode(dst); err != nil {
+			return fmt.Errorf("inertia: failed to decode JSON body: %w", err)
+		}
+		return nil
+	}
+}
+
+// decodeForm assigns form values to the exported fields of the struct pointed to by dst.
+func decodeForm(values map[string][]string, dst interface{}) error {
+	rv := reflect.ValueOf(dst).Elem()
+	rt := rv.Type()
+
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := formFieldName(field)
+		if name == "-" {
+			continue
+		}
+
+		vals, ok := values[name]
+		if !ok || len(vals) == 0 {
+			continue
+		}
+
+		if err := setFieldValue(rv.Field(i), vals); err != nil {
+			return fmt.Errorf("inertia: invalid value for field %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// formFieldName returns the form key for a struct field.
+func formFieldName(field reflect.StructField) string {
+	if tag := field.Tag.Get("form"); tag != "" {
+		return strings.Split(tag, ",")[0]
+	}
+	return jsonFieldName(field)
+}
+
+// jsonFieldName returns the JSON key for a struct field.
+func jsonFieldName(field reflect.StructField) string {
+	if tag := field.Tag.Get("json"); tag != "" {
+		if name := strings.Split(tag, ",")[0]; name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// setFieldValue converts string form values into the field's type.
+func setFieldValue(v reflect.Value, vals []string) error {
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String {
+		v.Set(reflect.ValueOf(append([]string(nil), vals...)))
+		return nil
+	}
+
+	raw := vals[0]
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		if raw == "" || raw == "on" {
+			v.SetBool(raw == "on")
+			return nil
+		}
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field type %s", v.Type())
+	}
+
+	return nil
+}
+
+// validateStruct runs the `validate` tag rules for every exported field.
+func validateStruct(v reflect.Value) ValidationErrors {
+	var errs ValidationErrors
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag := field.Tag.Get("validate")
+		if tag == "" || tag == "-" {
+			continue
+		}
+
+		name := jsonFieldName(field)
+		for _, rule := range strings.Split(tag, ",") {
+			if message := checkRule(name, v.Field(i), rule); message != "" {
+				if errs == nil {
+					errs = NewValidationErrors()
+				}
+				errs.Add(name, message)
+				break
+			}
+		}
+	}
+
+	return errs
+}
+
+// checkRule validates a single rule and returns an error message when it fails.
+//
+//nolint:gocyclo // A flat switch over the supported rules is the clearest form.
+func checkRule(name string, v reflect.Value, rule string) string {
+	ruleName, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+
+	if ruleName == "required" {
+		if v.IsZero() {
+			return fmt.Sprintf("The %s field is required.", name)
+		}
+		return ""
+	}
+
+	// Other rules only apply when a value is present.
+	if v.IsZero() {
+		return ""
+	}
+
+	switch ruleName {
+	case "min", "max", "len":
+		limit, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return ""
+		}
+		return checkSize(name, v, ruleName, limit)
+	case "email":
+		if v.Kind() == reflect.String {
+			if _, err := mail.ParseAddress(v.String()); err != nil {
+				return fmt.Sprintf("The %s must be a valid email address.", name)
+			}
+		}
+	case "oneof":
+		value := fmt.Sprint(v.Interface())
+		for _, option := range strings.Fields(param) {
+			if option == value {
+				return ""
+			}
+		}
+		return fmt.Sprintf("The selected %s is invalid.", name)
+	}
+
+	return ""
+}
+
+// checkSize validates min/max/len against string length, collection size, or numeric value.
+func checkSize(name string, v reflect.Value, rule string, limit float64) string {
+	var size float64
+
+	switch v.Kind() {
+	case reflect.String:
+		size = float64(len([]rune(v.String())))
+	case reflect.Slice, reflect.Map, reflect.Array:
+		size = float64(v.Len())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		size = float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		size = float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		size = v.Float()
+	default:
+		return ""
+	}
+
+	if (rule == "min" && size < limit) || (rule == "max" && size > limit) || (rule == "len" && size != limit) {
+		return sizeMessage(name, rule, strconv.FormatFloat(limit, 'f', -1, 64), v.Kind())
+	}
+
+	return ""
+}
+
+// sizeMessage returns the failure message for a min, max or len rule.
+func sizeMessage(name, rule, limit string, kind reflect.Kind) string {
+	unit := ""
+	switch kind {
+	case reflect.String:
+		unit = " characters"
+	case reflect.Slice, reflect.Map, reflect.Array:
+		unit = " items"
+	}
+
+	switch rule {
+	case "min":
+		return fmt.Sprintf("The %s must be at least %s%s.", name, limit, unit)
+	case "max":
+		return fmt.Sprintf("The %s may not be greater than %s%s.", name, limit, unit)
+	default:
+		return fmt.Sprintf("The %s must be exactly %s%s.", name, limit, unit)
+	}
+}
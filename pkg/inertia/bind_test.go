@@ -0,0 +1,234 @@
+package inertia_test
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/toutaio/toutago-inertia/pkg/inertia"
+)
+
+type createTodoInput struct {
+	Title       string `json:"title" validate:"required,min=3"`
+	Description string `json:"description" validate:"max=20"`
+	Email       string `json:"email" validate:"omitempty,email"`
+	Priority    int    `json:"priority" validate:"max=5"`
+}
+
+func TestBind_JSON(t *testing.T) {
+	t.Run("valid input", func(t *testing.T) {
+		body := `{"title":"Buy milk","description":"2 liters","priority":3}`
+		req := httptest.NewRequest("POST", "/todos", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		var input createTodoInput
+		errs, err := inertia.Bind(req, &input)
+		require.NoError(t, err)
+
+		assert.False(t, errs.Any())
+		assert.Equal(t, "Buy milk", input.Title)
+		assert.Equal(t, 3, input.Priority)
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		body := `{"title":"ab","email":"not-an-email","priority":9}`
+		req := httptest.NewRequest("POST", "/todos", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		var input createTodoInput
+		errs, err := inertia.Bind(req, &input)
+		require.NoError(t, err)
+
+		assert.Equal(t, "The title must be at least 3 characters.", errs.First("title"))
+		assert.Equal(t, "The email must be a valid email address.", errs.First("email"))
+		assert.Equal(t, "The priority may not be greater than 5.", errs.First("priority"))
+		assert.False(t, errs.Has("description"))
+	})
+
+	t.Run("required field missing", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/todos", strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		var input createTodoInput
+		errs, err := inertia.Bind(req, &input)
+		require.NoError(t, err)
+
+		assert.Equal(t, "The title field is required.", errs.First("title"))
+	})
+
+	t.Run("malformed JSON", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/todos", strings.NewReader(`{"title":`))
+		req.Header.Set("Content-Type", "application/json")
+
+		var input createTodoInput
+		_, err := inertia.Bind(req, &input)
+		assert.Error(t, err)
+	})
+}
+
+func TestBind_Form(t *testing.T) {
+	form := url.Values{}
+	form.Set("title", "Walk the dog")
+	form.Set("priority", "2")
+
+	req := httptest.NewRequest("POST", "/todos", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var input createTodoInput
+	errs, err := inertia.Bind(req, &input)
+	require.NoError(t, err)
+
+	assert.False(t, errs.Any())
+	assert.Equal(t, "Walk the dog", input.Title)
+	assert.Equal(t, 2, input.Priority)
+}
+
+func TestBind_Multipart(t *testing.T) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	require.NoError(t, mw.WriteField("title", "no"))
+	require.NoError(t, mw.WriteField("priority", "1"))
+	require.NoError(t, mw.Close())
+
+	req := httptest.NewRequest("POST", "/todos", &buf)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	var input createTodoInput
+	errs, err := inertia.Bind(req, &input)
+	require.NoError(t, err)
+
+	assert.Equal(t, "no", input.Title)
+	assert.Equal(t, 1, input.Priority)
+	assert.True(t, errs.Has("title"))
+}
+
+func TestInertia_Bind_MaxMemory(t *testing.T) {
+	newRequest := func(t *testing.T) *http.Request {
+		var buf bytes.Buffer
+		mw := multipart.NewWriter(&buf)
+		require.NoError(t, mw.WriteField("title", "Upload"))
+		fw, err := mw.CreateFormFile("attachment", "notes.txt")
+		require.NoError(t, err)
+		_, err = fw.Write([]byte("larger than the memory limit"))
+		require.NoError(t, err)
+		require.NoError(t, mw.Close())
+
+		req := httptest.NewRequest("POST", "/todos", &buf)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		return req
+	}
+
+	// Files beyond the memory limit are stored in temporary files
+	storedOnDisk := func(t *testing.T, req *http.Request) bool {
+		f, err := req.MultipartForm.File["attachment"][0].Open()
+		require.NoError(t, err)
+		defer f.Close()
+		_, onDisk := f.(*os.File)
+		return onDisk
+	}
+
+	tests := []struct {
+		name      string
+		maxMemory int64
+		onDisk    bool
+	}{
+		{name: "default limit", maxMemory: 0, onDisk: false},
+		{name: "configured limit", maxMemory: 8, onDisk: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mgr, err := inertia.New(inertia.Config{RootView: "app.html", MaxMemory: tt.maxMemory})
+			require.NoError(t, err)
+
+			req := newRequest(t)
+			defer func() { _ = req.MultipartForm.RemoveAll() }()
+
+			var input createTodoInput
+			_, err = mgr.Bind(req, &input)
+			require.NoError(t, err)
+
+			assert.Equal(t, "Upload", input.Title)
+			assert.Equal(t, tt.onDisk, storedOnDisk(t, req))
+		})
+	}
+}
+
+func TestBind_InvalidDestination(t *testing.T) {
+	req := httptest.NewRequest("POST", "/todos", http.NoBody)
+
+	var input createTodoInput
+	_, err := inertia.Bind(req, input)
+	assert.Error(t, err)
+}
+
+type registerInput struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+	Confirm  string `json:"password_confirmation"`
+}
+
+func (in *registerInput) Validate() inertia.ValidationErrors {
+	errs := inertia.NewValidationErrors()
+	if in.Password != in.Confirm {
+		errs.Add("password_confirmation", "The password confirmation does not match.")
+	}
+	return errs
+}
+
+func TestInertiaContext_ValidateAndBind(t *testing.T) {
+	config := inertia.Config{
+		RootView: "app.html",
+		Version:  "1.0.0",
+	}
+
+	mgr, err := inertia.New(config)
+	require.NoError(t, err)
+
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest("POST", "/register", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Inertia", "true")
+		req.Header.Set("Referer", "/register")
+		return req
+	}
+
+	t.Run("valid input proceeds", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := newRequest(`{"email":"jane@example.com","password":"secret","password_confirmation":"secret"}`)
+		ic := inertia.NewContext(NewMockContext(w, req), mgr)
+
+		var input registerInput
+		assert.True(t, ic.ValidateAndBind(&input))
+		assert.Equal(t, "jane@example.com", input.Email)
+		assert.Empty(t, w.Header().Get("X-Inertia-Location"))
+	})
+
+	t.Run("invalid input redirects back", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := newRequest(`{"email":"jane@example.com","password":"secret","password_confirmation":"other"}`)
+		ic := inertia.NewContext(NewMockContext(w, req), mgr)
+
+		var input registerInput
+		assert.False(t, ic.ValidateAndBind(&input))
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, "/register", w.Header().Get("X-Inertia-Location"))
+	})
+
+	t.Run("malformed body returns bad request", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := newRequest(`{"email":`)
+		ic := inertia.NewContext(NewMockContext(w, req), mgr)
+
+		var input registerInput
+		assert.False(t, ic.ValidateAndBind(&input))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,68 @@
+package inertia
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// defaultCompressionThreshold is the minimum payload size worth compressing.
+const defaultCompressionThreshold = 1024
+
+// compressionThreshold returns the configured compression threshold.
+func (i *Inertia) compressionThreshold() int {
+	if i.config.CompressionThreshold > 0 {
+		return i.config.CompressionThreshold
+	}
+	return defaultCompressionThreshold
+}
+
+// writeBody writes a response body, gzip-compressing it when Config.Compression is
+// enabled, the client accepts gzip and the payload is above the threshold.
+func (i *Inertia) writeBody(w http.ResponseWriter, r *http.Request, body []byte) error {
+	if i.config.Compression {
+		w.Header().Add("Vary", "Accept-Encoding")
+	}
+
+	if !i.config.Compression ||
+		len(body) < i.compressionThreshold() ||
+		w.Header().Get("Content-Encoding") != "" ||
+		!acceptsGzip(r) {
+		_, err := w.Write(body)
+		return err
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(body); err != nil {
+		return fmt.Errorf("inertia: failed to compress response: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("inertia: failed to compress response: %w", err)
+	}
+
+	w.Header().Set("Content-Encoding", "gzip")
+	w.Header().Del("Content-Length")
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// acceptsGzip reports whether the request's Accept-Encoding allows gzip.
+func acceptsGzip(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		coding = strings.TrimSpace(coding)
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			weight, err := strconv.ParseFloat(q, 64)
+			return err == nil && weight > 0
+		}
+		return true
+	}
+	return false
+}
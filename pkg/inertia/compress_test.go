@@ -0,0 +1,71 @@
+package inertia_test
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/toutaio/toutago-inertia/pkg/inertia"
+)
+
+func TestRender_Compression(t *testing.T) {
+	config := inertia.Config{
+		RootView:    "app.html",
+		Version:     "1.0.0",
+		Compression: true,
+	}
+
+	mgr, err := inertia.New(config)
+	require.NoError(t, err)
+
+	render := func(acceptEncoding string, props map[string]interface{}) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/posts", http.NoBody)
+		req.Header.Set("X-Inertia", "true")
+		if acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		w := httptest.NewRecorder()
+		ictx := inertia.NewContext(NewMockContext(w, req), mgr)
+		require.NoError(t, ictx.Render("Posts/Index", props))
+		return w
+	}
+
+	largeProps := map[string]interface{}{
+		"body": strings.Repeat("lorem ipsum dolor sit amet ", 200),
+	}
+
+	t.Run("gzips large payloads", func(t *testing.T) {
+		w := render("gzip, deflate", largeProps)
+
+		assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+		assert.Contains(t, w.Header().Values("Vary"), "Accept-Encoding")
+
+		gz, err := gzip.NewReader(w.Body)
+		require.NoError(t, err)
+		defer gz.Close()
+
+		var page inertia.Page
+		require.NoError(t, json.NewDecoder(gz).Decode(&page))
+		assert.Equal(t, "Posts/Index", page.Component)
+		assert.Equal(t, largeProps["body"], page.Props["body"])
+	})
+
+	t.Run("skips small payloads", func(t *testing.T) {
+		w := render("gzip", map[string]interface{}{"title": "Hello"})
+
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Contains(t, w.Body.String(), "Hello")
+	})
+
+	t.Run("skips clients without gzip", func(t *testing.T) {
+		for _, acceptEncoding := range []string{"", "br", "gzip;q=0"} {
+			w := render(acceptEncoding, largeProps)
+			assert.Empty(t, w.Header().Get("Content-Encoding"), acceptEncoding)
+		}
+	})
+}
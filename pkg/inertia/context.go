@@ -1,8 +1,11 @@
 package inertia
 
 import (
-	"encoding/json"
+	"fmt"
 	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
 )
 
 // ContextInterface defines the minimal interface that any router context must implement.
@@ -23,6 +26,17 @@ type InertiaContext struct {
 	sharedFuncs   map[string]SharedDataFunc
 	pendingErrors ValidationErrors
 	pendingFlash  Flash
+	headTags      []string
+	rootView      string
+	oldInput      map[string]interface{}
+	propErrors    map[string]string
+	nonce         string
+	serverOnly    map[string]bool
+	resetScroll   interface{}
+	etag          bool
+	sessionFlash  bool
+	sessionPulled bool // one-shot session data was added to the props
+	timings       []timingMetric
 }
 
 // NewContext creates a new Inertia context wrapper.
@@ -47,7 +61,10 @@ func (ic *InertiaContext) ShareFunc(key string, fn SharedDataFunc) *InertiaConte
 	return ic
 }
 
-// WithErrors adds validation errors to the next render.
+// WithErrors adds validation errors to the next render. When the request names an
+// error bag (see GetErrorBag), the errors are nested under it, e.g.
+// {"errors": {"createUser": {"email": [...]}}}, so several forms on a page keep
+// their own errors.
 func (ic *InertiaContext) WithErrors(errors ValidationErrors) *InertiaContext {
 	ic.pendingErrors = errors
 	return ic
@@ -62,23 +79,262 @@ func (ic *InertiaContext) WithFlash(flash Flash) *InertiaContext {
 // Render renders an Inertia page with context-specific data.
 func (ic *InertiaContext) Render(component string, props map[string]interface{}) error {
 	req := ic.ctx.Request()
-	res := ic.ctx.Response()
 
-	only := GetPartialOnly(req)
-	only = ic.appendAlwaysProps(only)
+	page, requested, err := ic.preparePage(component, props)
+	if err != nil {
+		return err
+	}
+
+	cacheable := ic.etagCacheable(req)
+	ic.attachPendingData(page)
+	ic.attachDebugInfo(page, requested)
+	ic.mgr.decoratePage(page, req)
+
+	if ic.mgr.wantsHTML(req) {
+		return ic.renderHTML(page, http.StatusOK)
+	}
+
+	return ic.writePage(ic.clientPage(page), cacheable)
+}
+
+// preparePage merges shared data into props, evaluates lazy props and builds the
+// page. It also returns the props requested by a partial reload, if any.
+func (ic *InertiaContext) preparePage(component string, props map[string]interface{}) (*Page, []string, error) {
+	req := ic.ctx.Request()
+	if props == nil {
+		props = make(map[string]interface{})
+	}
+
+	requested := partialOnlyFor(req, component)
+	except := partialExceptFor(req, component)
+	only := ic.appendAlwaysProps(requested)
 
+	if err := ic.assembleProps(req, props, only, except); err != nil {
+		return nil, nil, err
+	}
+
+	done := ic.timePhase(timingShared)
+	page, err := ic.renderPage(component, props, ic.pageURL(req), only)
+	done()
+	if err != nil {
+		return nil, nil, err
+	}
+	ic.excludeProps(page.Props, except)
+	if err := ic.mgr.checkPropSize(component, page.Props); err != nil {
+		return nil, nil, err
+	}
+
+	return page, requested, nil
+}
+
+// assembleProps merges every prop source into the handler props. Each source
+// only fills keys that are still unset, so when sources collide the first in this
+// order wins:
+//
+//  1. handler props passed to Render
+//  2. context shared data: InertiaContext.Share, followed by the values the
+//     context shares itself (auth user, old input, session flash, first-load
+//     shared data, CSP nonce and route name)
+//  3. always props (Always)
+//  4. lazy props (Lazy, AlwaysLazy, Defer and variants) selected for the request;
+//     evaluators of keys already set are not invoked
+//  5. global shared data (Inertia.Share, Inertia.ShareFunc), merged last when
+//     the page is built
+//
+// The errors and flash set with WithErrors and WithFlash, and the errors of
+// failed lazy props, are not prop sources: attachPendingData writes them after
+// the page is built, replacing any prop with the same key.
+func (ic *InertiaContext) assembleProps(req *http.Request, props map[string]interface{}, only, except []string) error {
+	done := ic.timePhase(timingShared)
 	ic.mergeSharedData(props)
-	ic.evaluateLazyProps(props, only)
+	done()
+	ic.mergeAuthUser(props)
+	ic.mergeOldInput(props)
+	ic.mergeSessionFlash(props)
+	ic.mergeSharedOnceData(props, req)
+	ic.mergeNonce(props, req)
+	ic.mergeRouteName(props, req)
+	ic.mergeAlwaysProps(props)
+	if req.Method == http.MethodHead {
+		// HEAD responses have no body, so lazy props would be wasted work
+		return nil
+	}
+	defer ic.timePhase(timingLazy)()
+	return ic.evaluateLazyProps(req.Context(), props, only, except)
+}
+
+// setDefault sets props[key] unless the key is already set. Prop sources merge
+// through it, so sources merged earlier take precedence.
+func setDefault(props map[string]interface{}, key string, value interface{}) {
+	if _, exists := props[key]; !exists {
+		props[key] = value
+	}
+}
+
+// RenderOrJSON renders like Render for browsers and Inertia requests, but answers
+// API clients, which accept application/json without sending X-Inertia, with the
+// props as a plain JSON object instead of the page envelope. This lets one
+// endpoint serve both the SPA and API consumers.
+func (ic *InertiaContext) RenderOrJSON(component string, props Props) error {
+	req := ic.ctx.Request()
+	res := ic.ctx.Response()
+	res.Header().Add("Vary", "Accept")
 
-	page, err := ic.renderPage(component, props, req.URL.Path, only)
+	if !ic.mgr.wantsPropsJSON(req) {
+		return ic.Render(component, props)
+	}
+
+	page, _, err := ic.preparePage(component, props)
 	if err != nil {
 		return err
 	}
-
 	ic.attachPendingData(page)
+	ic.mgr.decoratePage(page, req)
+
+	body, err := ic.mgr.encodeJSON(ic.clientPage(page).Props)
+	if err != nil {
+		return fmt.Errorf("inertia: failed to encode props: %w", err)
+	}
+	body = append(body, '\n')
+
+	res.Header().Set("Content-Type", "application/json")
+	return ic.mgr.writeBody(res, req, body)
+}
+
+// RenderWith renders like Render, adding extraShared as shared data for this call
+// only. Keys in props take precedence over extraShared, which in turn takes
+// precedence over data shared with Share. The context's shared data is unchanged.
+func (ic *InertiaContext) RenderWith(component string, props Props, extraShared map[string]interface{}) error {
+	merged := make(map[string]interface{}, len(props)+len(extraShared))
+	for key, value := range extraShared {
+		merged[key] = value
+	}
+	for key, value := range props {
+		merged[key] = value
+	}
+	return ic.Render(component, merged)
+}
+
+// defaultListPropKey is the prop holding the items rendered by RenderList.
+const defaultListPropKey = "data"
+
+// RenderList renders a component whose props are naturally a list, passing items
+// (a slice or array) under Config.ListPropKey, "data" by default. Shared data,
+// lazy props and the other render options apply as with Render:
+//
+//	return ic.RenderList("Users/Index", users) // props: {"data": [...], "auth": ...}
+func (ic *InertiaContext) RenderList(component string, items interface{}) error {
+	if kind := reflect.ValueOf(items).Kind(); kind != reflect.Slice && kind != reflect.Array {
+		return fmt.Errorf("inertia: RenderList expects a slice or array, got %T", items)
+	}
 
+	key := ic.mgr.config.ListPropKey
+	if key == "" {
+		key = defaultListPropKey
+	}
+	return ic.Render(component, map[string]interface{}{key: items})
+}
+
+// writePage writes the page JSON response. When withETag is set, an ETag header is
+// added and a matching If-None-Match is answered with 304 Not Modified.
+func (ic *InertiaContext) writePage(page *Page, withETag bool) error {
+	done := ic.timePhase(timingEncode)
+	body, err := ic.mgr.encodeJSON(page)
+	done()
+	if err != nil {
+		return fmt.Errorf("inertia: failed to encode page: %w", err)
+	}
+	body = append(body, '\n')
+
+	req := ic.ctx.Request()
+	res := ic.ctx.Response()
 	res.Header().Set("Content-Type", "application/json")
-	return json.NewEncoder(res).Encode(page)
+	ic.writeServerTiming(res.Header())
+
+	if req.Method == http.MethodHead {
+		return nil
+	}
+
+	if withETag {
+		etag := computeETag(body)
+		res.Header().Set("ETag", etag)
+		if etagMatches(req.Header.Get("If-None-Match"), etag) {
+			res.WriteHeader(http.StatusNotModified)
+			return nil
+		}
+	}
+
+	return ic.mgr.writeBody(res, req, body)
+}
+
+// pageURL returns the URL for the rendered page. When a non-GET Inertia request
+// re-renders a form with validation errors, the page keeps the originating URL from
+// the Referer so the client swaps the component in place instead of navigating to
+// the form's action URL.
+func (ic *InertiaContext) pageURL(req *http.Request) string {
+	if req.Method == http.MethodGet || !ic.mgr.isInertiaRequest(req) || !ic.pendingErrors.Any() {
+		return ic.mgr.requestURL(req)
+	}
+
+	referer := req.Header.Get("Referer")
+	if referer == "" || !IsSameOrigin(req, referer) {
+		return ic.mgr.requestURL(req)
+	}
+
+	u, err := url.Parse(referer)
+	if err != nil || u.Path == "" {
+		return ic.mgr.requestURL(req)
+	}
+	return u.RequestURI()
+}
+
+// requestURL returns the page URL for req: its path and query string. With
+// Config.TrustProxyHeaders, a request carrying X-Forwarded-Host gets the absolute
+// public URL built from X-Forwarded-Proto and X-Forwarded-Host instead.
+func (i *Inertia) requestURL(req *http.Request) string {
+	uri := req.URL.RequestURI()
+	if !i.config.TrustProxyHeaders {
+		return uri
+	}
+
+	host := forwardedHeader(req, "X-Forwarded-Host")
+	if host == "" {
+		return uri
+	}
+	scheme := forwardedHeader(req, "X-Forwarded-Proto")
+	if scheme == "" {
+		scheme = "http"
+		if req.TLS != nil {
+			scheme = "https"
+		}
+	}
+	return scheme + "://" + host + uri
+}
+
+// forwardedHeader returns the first value of a proxy header, which proxies
+// append to as a comma-separated list.
+func forwardedHeader(req *http.Request, name string) string {
+	value, _, _ := strings.Cut(req.Header.Get(name), ",")
+	return strings.TrimSpace(value)
+}
+
+// partialOnlyFor returns the partial reload props for the component being rendered.
+// If the client requested a partial reload of a different component (e.g. the user
+// navigated elsewhere mid-flight), the partial request is ignored and nil is returned
+// so a full response is rendered, as required by the protocol.
+func partialOnlyFor(req *http.Request, component string) []string {
+	if partialComponent := GetPartialComponent(req); partialComponent != "" && partialComponent != component {
+		return nil
+	}
+	return GetPartialOnly(req)
+}
+
+// partialExceptFor returns the props excluded from a partial reload of component.
+func partialExceptFor(req *http.Request, component string) []string {
+	if partialComponent := GetPartialComponent(req); partialComponent != "" && partialComponent != component {
+		return nil
+	}
+	return GetPartialExcept(req)
 }
 
 // appendAlwaysProps adds "always" props to the only list for partial reloads.
@@ -125,9 +381,7 @@ func (ic *InertiaContext) appendAlwaysLazyProps(only []string) []string {
 // mergeSharedData merges context-specific shared data and lazy functions into props.
 func (ic *InertiaContext) mergeSharedData(props map[string]interface{}) {
 	for key, value := range ic.sharedData {
-		if _, exists := props[key]; !exists {
-			props[key] = value
-		}
+		setDefault(props, key, value)
 	}
 
 	for key, fn := range ic.sharedFuncs {
@@ -137,6 +391,17 @@ func (ic *InertiaContext) mergeSharedData(props map[string]interface{}) {
 	}
 }
 
+// mergeSharedOnceData merges first-request-only shared data into props on full page loads.
+func (ic *InertiaContext) mergeSharedOnceData(props map[string]interface{}, req *http.Request) {
+	if ic.mgr.isInertiaRequest(req) {
+		return
+	}
+
+	for key, value := range ic.mgr.sharedOnce {
+		setDefault(props, key, value)
+	}
+}
+
 // renderPage renders the page based on whether it's a partial or full reload.
 func (ic *InertiaContext) renderPage(
 	component string,
@@ -150,10 +415,18 @@ func (ic *InertiaContext) renderPage(
 	return ic.mgr.Render(component, props, path)
 }
 
+// scopedErrors nests errors under the error bag of the request, if any.
+func (ic *InertiaContext) scopedErrors(errors ValidationErrors) interface{} {
+	if bag := GetErrorBag(ic.ctx.Request()); bag != "" {
+		return map[string]ValidationErrors{bag: errors}
+	}
+	return errors
+}
+
 // attachPendingData attaches pending errors and flash messages to the page.
 func (ic *InertiaContext) attachPendingData(page *Page) {
 	if ic.pendingErrors != nil {
-		page.WithErrors(ic.pendingErrors)
+		page.Props[ic.mgr.ErrorsPropKey()] = ic.scopedErrors(ic.pendingErrors)
 		ic.pendingErrors = nil
 	}
 
@@ -161,6 +434,16 @@ func (ic *InertiaContext) attachPendingData(page *Page) {
 		page.WithFlash(ic.pendingFlash)
 		ic.pendingFlash = nil
 	}
+
+	if ic.propErrors != nil {
+		page.Props[propErrorsKey] = ic.propErrors
+		ic.propErrors = nil
+	}
+
+	if ic.resetScroll != nil {
+		page.ResetScroll = ic.resetScroll
+		ic.resetScroll = nil
+	}
 }
 
 // Redirect performs an internal redirect.
@@ -173,11 +456,34 @@ func (ic *InertiaContext) Location(url string) error {
 	return ic.mgr.Location(ic.ctx.Response(), ic.ctx.Request(), url)
 }
 
+// LocationInternal redirects with a regular Inertia visit for same-origin URLs
+// and an external redirect otherwise.
+func (ic *InertiaContext) LocationInternal(url string) error {
+	return ic.mgr.LocationInternal(ic.ctx.Response(), ic.ctx.Request(), url)
+}
+
 // Back redirects to the previous page.
 func (ic *InertiaContext) Back() error {
 	return ic.mgr.Back(ic.ctx.Response(), ic.ctx.Request())
 }
 
+// BackOr redirects to the previous page, or to fallback if there is no Referer.
+func (ic *InertiaContext) BackOr(fallback string) error {
+	return ic.mgr.BackOr(ic.ctx.Response(), ic.ctx.Request(), fallback)
+}
+
+// ResetScroll adds a resetScroll hint to the next render, asking the frontend to
+// reset the scroll position of the given regions, or of all regions when none
+// are given.
+func (ic *InertiaContext) ResetScroll(regions ...string) *InertiaContext {
+	if len(regions) == 0 {
+		ic.resetScroll = true
+	} else {
+		ic.resetScroll = regions
+	}
+	return ic
+}
+
 // WithError adds a single validation error for a field.
 func (ic *InertiaContext) WithError(field, message string) *InertiaContext {
 	if ic.pendingErrors == nil {
@@ -225,13 +531,23 @@ func (ic *InertiaContext) WithInfo(message string) *InertiaContext {
 
 // Error renders an error page.
 func (ic *InertiaContext) Error(status int, message string) error {
-	page, err := ic.mgr.Error(status, message, ic.ctx.Request().URL.Path, ic.ctx.Request())
+	page, err := ic.mgr.Error(status, message, ic.mgr.requestURL(ic.ctx.Request()), ic.ctx.Request())
 	if err != nil {
 		return err
 	}
 
+	if ic.mgr.wantsHTML(ic.ctx.Request()) {
+		return ic.renderHTML(page, status)
+	}
+
+	body, err := ic.mgr.encodeJSON(page)
+	if err != nil {
+		return fmt.Errorf("inertia: failed to encode page: %w", err)
+	}
+
 	res := ic.ctx.Response()
 	res.Header().Set("Content-Type", "application/json")
 	res.WriteHeader(status)
-	return json.NewEncoder(res).Encode(page)
+	_, err = res.Write(append(body, '\n'))
+	return err
 }
@@ -1,6 +1,7 @@
 package inertia_test
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -167,6 +168,78 @@ func TestInertiaContext_WithErrors(t *testing.T) {
 	assert.Contains(t, w.Body.String(), "Email is required")
 }
 
+func TestInertiaContext_ErrorsPropKey(t *testing.T) {
+	mgr, err := inertia.New(inertia.Config{
+		RootView:      "app.html",
+		Version:       "1.0.0",
+		ErrorsPropKey: "formErrors",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "formErrors", mgr.ErrorsPropKey())
+
+	errs := inertia.NewValidationErrors()
+	errs.Add("email", "Email is required")
+
+	render := func(t *testing.T, req *http.Request, configure func(*inertia.InertiaContext)) inertia.Page {
+		t.Helper()
+		req.Header.Set("X-Inertia", "true")
+		w := httptest.NewRecorder()
+		ictx := inertia.NewContext(NewMockContext(w, req), mgr)
+		configure(ictx)
+		require.NoError(t, ictx.Render("Users/Create", nil))
+
+		var page inertia.Page
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		return page
+	}
+
+	t.Run("WithErrors", func(t *testing.T) {
+		page := render(t, httptest.NewRequest("POST", "/users", http.NoBody), func(ictx *inertia.InertiaContext) {
+			ictx.WithErrors(errs)
+		})
+
+		assert.Equal(t, map[string]interface{}{"email": []interface{}{"Email is required"}}, page.Props["formErrors"])
+		assert.NotContains(t, page.Props, "errors")
+	})
+}
+
+func TestInertiaContext_ErrorBag(t *testing.T) {
+	mgr, err := inertia.New(inertia.Config{RootView: "app.html", Version: "1.0.0"})
+	require.NoError(t, err)
+
+	render := func(t *testing.T, errorBag string) inertia.Page {
+		t.Helper()
+		handler := mgr.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ictx := inertia.FromRequest(r).WithError("email", "Email is required")
+			require.NoError(t, ictx.Render("Users/Create", nil))
+		}))
+
+		req := httptest.NewRequest("POST", "/users", http.NoBody)
+		req.Header.Set("X-Inertia", "true")
+		if errorBag != "" {
+			req.Header.Set("X-Inertia-Error-Bag", errorBag)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		var page inertia.Page
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		return page
+	}
+
+	t.Run("bagged", func(t *testing.T) {
+		page := render(t, "createUser")
+		assert.Equal(t, map[string]interface{}{
+			"createUser": map[string]interface{}{"email": []interface{}{"Email is required"}},
+		}, page.Props["errors"])
+	})
+
+	t.Run("without a bag", func(t *testing.T) {
+		page := render(t, "")
+		assert.Equal(t, map[string]interface{}{"email": []interface{}{"Email is required"}}, page.Props["errors"])
+	})
+}
+
 func TestInertiaContext_WithFlash(t *testing.T) {
 	config := inertia.Config{
 		RootView: "app.html",
@@ -226,6 +299,271 @@ func TestInertiaContext_Share(t *testing.T) {
 	assert.Contains(t, w.Body.String(), "Alice")
 }
 
+func TestInertiaContext_RenderWith(t *testing.T) {
+	mgr, err := inertia.New(inertia.Config{
+		RootView: "app.html",
+		Version:  "1.0.0",
+	})
+	require.NoError(t, err)
+	mgr.Share("appName", "Test App")
+
+	req := httptest.NewRequest("GET", "/users", http.NoBody)
+	req.Header.Set("X-Inertia", "true")
+	w := httptest.NewRecorder()
+	ictx := inertia.NewContext(NewMockContext(w, req), mgr)
+
+	decode := func() map[string]interface{} {
+		var page inertia.Page
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		w.Body.Reset()
+		return page.Props
+	}
+
+	err = ictx.RenderWith("Users/Index",
+		inertia.Props{"title": "Users"},
+		map[string]interface{}{"title": "ignored", "banner": "Sale today", "appName": "Override"},
+	)
+	require.NoError(t, err)
+
+	props := decode()
+	assert.Equal(t, "Users", props["title"], "props take precedence over extra shared data")
+	assert.Equal(t, "Sale today", props["banner"])
+	assert.Equal(t, "Override", props["appName"])
+
+	require.NoError(t, ictx.Render("Users/Index", map[string]interface{}{}))
+
+	props = decode()
+	assert.NotContains(t, props, "banner", "extra shared data must not leak into later renders")
+	assert.Equal(t, "Test App", props["appName"])
+}
+
+func TestInertiaContext_ResetScroll(t *testing.T) {
+	mgr, err := inertia.New(inertia.Config{
+		RootView: "app.html",
+		Version:  "1.0.0",
+	})
+	require.NoError(t, err)
+
+	render := func(configure func(*inertia.InertiaContext)) map[string]interface{} {
+		req := httptest.NewRequest("GET", "/feed", http.NoBody)
+		req.Header.Set("X-Inertia", "true")
+		w := httptest.NewRecorder()
+		ic := inertia.NewContext(NewMockContext(w, req), mgr)
+		configure(ic)
+		require.NoError(t, ic.Render("Feed", map[string]interface{}{}))
+
+		var page map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		return page
+	}
+
+	t.Run("omitted by default", func(t *testing.T) {
+		assert.NotContains(t, render(func(*inertia.InertiaContext) {}), "resetScroll")
+	})
+
+	t.Run("all regions", func(t *testing.T) {
+		page := render(func(ic *inertia.InertiaContext) { ic.ResetScroll() })
+		assert.Equal(t, true, page["resetScroll"])
+	})
+
+	t.Run("specific regions", func(t *testing.T) {
+		page := render(func(ic *inertia.InertiaContext) { ic.ResetScroll("sidebar", "feed") })
+		assert.Equal(t, []interface{}{"sidebar", "feed"}, page["resetScroll"])
+	})
+}
+
+func TestInertiaContext_RenderOrJSON(t *testing.T) {
+	mgr, err := inertia.New(inertia.Config{
+		RootView: writeRootTemplate(t, "app.html", testRootTemplate),
+		Version:  "1.0.0",
+	})
+	require.NoError(t, err)
+	mgr.Share("appName", "Toutago")
+
+	render := func(t *testing.T, req *http.Request) *httptest.ResponseRecorder {
+		t.Helper()
+		w := httptest.NewRecorder()
+		ic := inertia.NewContext(NewMockContext(w, req), mgr)
+		ic.ServerOnly("secret")
+		require.NoError(t, ic.RenderOrJSON("Users/Index", inertia.Props{
+			"users":  []string{"alice", "bob"},
+			"secret": "s3cr3t",
+		}))
+		return w
+	}
+
+	t.Run("API client gets plain props", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/users", http.NoBody)
+		req.Header.Set("Accept", "application/json")
+		w := render(t, req)
+
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Header().Values("Vary"), "Accept")
+
+		var props map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &props))
+		assert.Equal(t, []interface{}{"alice", "bob"}, props["users"])
+		assert.Equal(t, "Toutago", props["appName"])
+		assert.NotContains(t, props, "component")
+		assert.NotContains(t, props, "secret")
+	})
+
+	t.Run("Inertia request gets the page envelope", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/users", http.NoBody)
+		req.Header.Set("X-Inertia", "true")
+		req.Header.Set("Accept", "text/html, application/xhtml+xml")
+		w := render(t, req)
+
+		var page inertia.Page
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		assert.Equal(t, "Users/Index", page.Component)
+		assert.Equal(t, "/users", page.URL)
+		assert.Contains(t, page.Props, "users")
+	})
+
+	t.Run("browser gets the HTML page", func(t *testing.T) {
+		w := render(t, newFullLoadRequest("/users"))
+
+		assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+		assert.Contains(t, w.Body.String(), `data-page="`)
+		assert.Contains(t, w.Body.String(), "Users/Index")
+	})
+}
+
+func TestInertiaContext_RenderList(t *testing.T) {
+	type user struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	users := []user{{ID: 1, Name: "Alice"}, {ID: 2, Name: "Bob"}}
+
+	render := func(t *testing.T, config inertia.Config, items interface{}) (map[string]interface{}, error) {
+		t.Helper()
+
+		mgr, err := inertia.New(config)
+		require.NoError(t, err)
+		mgr.Share("appName", "Toutago")
+
+		req := httptest.NewRequest("GET", "/users", http.NoBody)
+		req.Header.Set("X-Inertia", "true")
+		w := httptest.NewRecorder()
+		ic := inertia.NewContext(NewMockContext(w, req), mgr)
+		ic.Share("auth", "alice")
+		if err := ic.RenderList("Users/Index", items); err != nil {
+			return nil, err
+		}
+
+		var page inertia.Page
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		return page.Props, nil
+	}
+
+	t.Run("items under data with shared props", func(t *testing.T) {
+		props, err := render(t, inertia.Config{RootView: "app.html", Version: "1.0.0"}, users)
+		require.NoError(t, err)
+
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{"id": float64(1), "name": "Alice"},
+			map[string]interface{}{"id": float64(2), "name": "Bob"},
+		}, props["data"])
+		assert.Equal(t, "Toutago", props["appName"])
+		assert.Equal(t, "alice", props["auth"])
+	})
+
+	t.Run("custom key", func(t *testing.T) {
+		props, err := render(t, inertia.Config{RootView: "app.html", Version: "1.0.0", ListPropKey: "items"}, [2]string{"a", "b"})
+		require.NoError(t, err)
+
+		assert.Equal(t, []interface{}{"a", "b"}, props["items"])
+		assert.NotContains(t, props, "data")
+	})
+
+	t.Run("not a list", func(t *testing.T) {
+		_, err := render(t, inertia.Config{RootView: "app.html", Version: "1.0.0"}, map[string]int{"a": 1})
+		assert.Error(t, err)
+	})
+}
+
+func TestInertiaContext_PropPrecedence(t *testing.T) {
+	type sources struct {
+		handler, contextShared, always, lazy, globalShared bool
+	}
+
+	tests := []struct {
+		name    string
+		sources sources
+		want    string
+	}{
+		{name: "handler props win", sources: sources{true, true, true, true, true}, want: "handler"},
+		{name: "context shared over always", sources: sources{false, true, true, true, true}, want: "context shared"},
+		{name: "always over lazy", sources: sources{false, false, true, true, true}, want: "always"},
+		{name: "lazy over global shared", sources: sources{false, false, false, true, true}, want: "lazy"},
+		{name: "global shared last", sources: sources{false, false, false, false, true}, want: "global shared"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mgr, err := inertia.New(inertia.Config{RootView: "app.html", Version: "1.0.0"})
+			require.NoError(t, err)
+			if tt.sources.globalShared {
+				mgr.Share("title", "global shared")
+			}
+
+			req := httptest.NewRequest("GET", "/", http.NoBody)
+			req.Header.Set("X-Inertia", "true")
+			w := httptest.NewRecorder()
+			ic := inertia.NewContext(NewMockContext(w, req), mgr)
+
+			lazyCalled := false
+			if tt.sources.contextShared {
+				ic.Share("title", "context shared")
+			}
+			if tt.sources.always {
+				ic.Always("title", "always")
+			}
+			if tt.sources.lazy {
+				ic.Lazy("title", func() interface{} {
+					lazyCalled = true
+					return "lazy"
+				})
+			}
+			props := map[string]interface{}{}
+			if tt.sources.handler {
+				props["title"] = "handler"
+			}
+
+			require.NoError(t, ic.Render("Home", props))
+
+			var page inertia.Page
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+			assert.Equal(t, tt.want, page.Props["title"])
+			assert.Equal(t, tt.want == "lazy", lazyCalled, "lazy evaluator runs only when it wins")
+		})
+	}
+}
+
+func TestInertiaContext_PendingDataOverridesProps(t *testing.T) {
+	mgr, err := inertia.New(inertia.Config{RootView: "app.html", Version: "1.0.0"})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("GET", "/", http.NoBody)
+	req.Header.Set("X-Inertia", "true")
+	w := httptest.NewRecorder()
+	ic := inertia.NewContext(NewMockContext(w, req), mgr)
+
+	ic.WithFlash(inertia.Flash{"notice": "from flash"})
+	ic.WithErrors(inertia.ValidationErrors{"email": {"required"}})
+	require.NoError(t, ic.Render("Home", map[string]interface{}{
+		"notice": "from handler",
+		"errors": "from handler",
+	}))
+
+	var page inertia.Page
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	assert.Equal(t, "from flash", page.Props["notice"])
+	assert.Equal(t, map[string]interface{}{"email": []interface{}{"required"}}, page.Props["errors"])
+}
+
 func TestInertiaContext_RenderOnly(t *testing.T) {
 	config := inertia.Config{
 		RootView: "app.html",
@@ -328,3 +666,221 @@ func TestInertiaContext_WithInfo(t *testing.T) {
 	assert.Contains(t, w.Body.String(), "info")
 	assert.Contains(t, w.Body.String(), "Settings saved successfully")
 }
+
+func TestInertiaContext_ShareOnce(t *testing.T) {
+	config := inertia.Config{
+		RootView: "app.html",
+		Version:  "1.0.0",
+	}
+
+	mgr, err := inertia.New(config)
+	require.NoError(t, err)
+
+	mgr.ShareOnce("showOnboarding", true)
+
+	t.Run("included on full page load", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/dashboard", http.NoBody)
+		w := httptest.NewRecorder()
+
+		ic := inertia.NewContext(NewMockContext(w, req), mgr)
+		err := ic.Render("Dashboard", map[string]interface{}{})
+		require.NoError(t, err)
+
+		assert.Contains(t, w.Body.String(), "showOnboarding")
+	})
+
+	t.Run("omitted on Inertia navigation", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/dashboard", http.NoBody)
+		req.Header.Set("X-Inertia", "true")
+		w := httptest.NewRecorder()
+
+		ic := inertia.NewContext(NewMockContext(w, req), mgr)
+		err := ic.Render("Dashboard", map[string]interface{}{})
+		require.NoError(t, err)
+
+		assert.NotContains(t, w.Body.String(), "showOnboarding")
+	})
+
+	t.Run("omitted on XHR treated as Inertia", func(t *testing.T) {
+		xhrMgr, err := inertia.New(inertia.Config{RootView: "app.html", Version: "1.0.0", TreatXHRAsInertia: true})
+		require.NoError(t, err)
+		xhrMgr.ShareOnce("showOnboarding", true)
+
+		req := httptest.NewRequest("GET", "/dashboard", http.NoBody)
+		req.Header.Set("X-Requested-With", "XMLHttpRequest")
+		w := httptest.NewRecorder()
+
+		ic := inertia.NewContext(NewMockContext(w, req), xhrMgr)
+		require.NoError(t, ic.Render("Dashboard", map[string]interface{}{}))
+
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		assert.NotContains(t, w.Body.String(), "showOnboarding")
+	})
+}
+
+func TestInertiaContext_PartialComponentMismatch(t *testing.T) {
+	config := inertia.Config{
+		RootView: "app.html",
+		Version:  "1.0.0",
+	}
+
+	mgr, err := inertia.New(config)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("GET", "/users", http.NoBody)
+	req.Header.Set("X-Inertia", "true")
+	req.Header.Set("X-Inertia-Partial-Data", "users")
+	req.Header.Set("X-Inertia-Partial-Component", "Users/Index")
+	w := httptest.NewRecorder()
+
+	var capturedReq *http.Request
+	handler := mgr.Middleware()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		capturedReq = r
+	}))
+	handler.ServeHTTP(w, req)
+
+	w = httptest.NewRecorder()
+	ictx := inertia.NewContext(NewMockContext(w, capturedReq), mgr)
+
+	// A different component is rendered, so the partial request must be ignored
+	err = ictx.Render("Dashboard/Index", map[string]interface{}{
+		"users": []string{"Alice"},
+		"stats": map[string]int{"total": 1},
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, w.Body.String(), "Dashboard/Index")
+	assert.Contains(t, w.Body.String(), "users")
+	assert.Contains(t, w.Body.String(), "stats")
+}
+
+func TestInertiaContext_ErrorReRenderKeepsRefererURL(t *testing.T) {
+	config := inertia.Config{
+		RootView: "app.html",
+		Version:  "1.0.0",
+	}
+
+	mgr, err := inertia.New(config)
+	require.NoError(t, err)
+
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest("POST", "http://example.com/todos", http.NoBody)
+		req.Header.Set("X-Inertia", "true")
+		req.Header.Set("Referer", "http://example.com/todos/create?draft=1")
+		return req
+	}
+
+	t.Run("re-render with errors keeps the originating URL", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ictx := inertia.NewContext(NewMockContext(w, newRequest()), mgr)
+
+		err := ictx.WithError("title", "The title field is required.").
+			Render("Todos/Create", map[string]interface{}{})
+		require.NoError(t, err)
+
+		var page inertia.Page
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		assert.Equal(t, "Todos/Create", page.Component)
+		assert.Equal(t, "/todos/create?draft=1", page.URL)
+		assert.NotNil(t, page.Props["errors"])
+	})
+
+	t.Run("render without errors uses the request URL", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ictx := inertia.NewContext(NewMockContext(w, newRequest()), mgr)
+
+		require.NoError(t, ictx.Render("Todos/Show", map[string]interface{}{}))
+
+		var page inertia.Page
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		assert.Equal(t, "/todos", page.URL)
+	})
+}
+
+func TestInertiaContext_PageURL(t *testing.T) {
+	render := func(t *testing.T, config inertia.Config, req *http.Request) inertia.Page {
+		t.Helper()
+
+		mgr, err := inertia.New(config)
+		require.NoError(t, err)
+
+		req.Header.Set("X-Inertia", "true")
+		w := httptest.NewRecorder()
+		require.NoError(t, inertia.NewContext(NewMockContext(w, req), mgr).Render("Users/Index", nil))
+
+		var page inertia.Page
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		return page
+	}
+
+	t.Run("includes the query string", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/users?page=2&sort=name", http.NoBody)
+		page := render(t, inertia.Config{RootView: "app.html", Version: "1.0.0"}, req)
+
+		assert.Equal(t, "/users?page=2&sort=name", page.URL)
+	})
+
+	t.Run("ignores proxy headers by default", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/users?page=2", http.NoBody)
+		req.Header.Set("X-Forwarded-Host", "app.example.com")
+		req.Header.Set("X-Forwarded-Proto", "https")
+		page := render(t, inertia.Config{RootView: "app.html", Version: "1.0.0"}, req)
+
+		assert.Equal(t, "/users?page=2", page.URL)
+	})
+
+	t.Run("uses proxy headers when trusted", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/users?page=2", http.NoBody)
+		req.Header.Set("X-Forwarded-Host", "app.example.com, internal.local")
+		req.Header.Set("X-Forwarded-Proto", "https")
+		page := render(t, inertia.Config{RootView: "app.html", Version: "1.0.0", TrustProxyHeaders: true}, req)
+
+		assert.Equal(t, "https://app.example.com/users?page=2", page.URL)
+	})
+}
+
+func TestInertiaContext_PageURLKeepsFilters(t *testing.T) {
+	mgr, err := inertia.New(inertia.Config{
+		RootView: writeRootTemplate(t, "app.html", testRootTemplate),
+		Version:  "1.0.0",
+	})
+	require.NoError(t, err)
+
+	props := map[string]interface{}{"todos": []string{"write docs"}, "filters": "active"}
+
+	t.Run("inertia navigation", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/todos?status=active", http.NoBody)
+		req.Header.Set("X-Inertia", "true")
+		w := httptest.NewRecorder()
+		require.NoError(t, inertia.NewContext(NewMockContext(w, req), mgr).Render("Todos/Index", props))
+
+		var page inertia.Page
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		assert.Equal(t, "/todos?status=active", page.URL)
+	})
+
+	t.Run("partial reload", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/todos?status=active", http.NoBody)
+		req.Header.Set("X-Inertia", "true")
+		req.Header.Set("X-Inertia-Partial-Component", "Todos/Index")
+		req.Header.Set("X-Inertia-Partial-Data", "todos")
+		req.Header.Set("X-Inertia-Version", "1.0.0")
+		w := httptest.NewRecorder()
+		mgr.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.NoError(t, inertia.NewContext(NewMockContext(w, r), mgr).Render("Todos/Index", props))
+		})).ServeHTTP(w, req)
+
+		var page inertia.Page
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		assert.Equal(t, "/todos?status=active", page.URL)
+		assert.NotContains(t, page.Props, "filters")
+	})
+
+	t.Run("full page load", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ic := inertia.NewContext(NewMockContext(w, newFullLoadRequest("/todos?status=active")), mgr)
+		require.NoError(t, ic.Render("Todos/Index", props))
+
+		assert.Contains(t, w.Body.String(), `&#34;url&#34;:&#34;/todos?status=active&#34;`)
+	})
+}
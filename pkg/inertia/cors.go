@@ -0,0 +1,157 @@
+package inertia
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// corsAllowedHeaders returns the request headers sent by the Inertia client.
+func corsAllowedHeaders() []string {
+	return []string{
+		"Content-Type",
+		"X-Requested-With",
+		"X-Inertia",
+		"X-Inertia-Version",
+		"X-Inertia-Partial-Data",
+		"X-Inertia-Partial-Component",
+		"X-Inertia-Partial-Except",
+		"X-Inertia-Error-Bag",
+	}
+}
+
+// corsExposedHeaders returns the response headers the Inertia client reads.
+func corsExposedHeaders() []string {
+	return []string{
+		"X-Inertia",
+		"X-Inertia-Location",
+		"X-Inertia-Version",
+	}
+}
+
+// defaultCORSMethods returns the methods allowed when CORSOptions.AllowedMethods
+// is empty.
+func defaultCORSMethods() []string {
+	return []string{
+		http.MethodGet,
+		http.MethodPost,
+		http.MethodPut,
+		http.MethodPatch,
+		http.MethodDelete,
+	}
+}
+
+// CORSOptions configures CORSMiddleware.
+type CORSOptions struct {
+	// AllowedOrigins lists the origins allowed to make requests, e.g.
+	// "https://app.example.com". "*" allows any origin.
+	AllowedOrigins []string
+
+	// AllowOriginFunc, when set, is consulted for origins not in AllowedOrigins.
+	AllowOriginFunc func(origin string) bool
+
+	// AllowedMethods lists the methods allowed in preflight responses.
+	// Defaults to GET, POST, PUT, PATCH and DELETE.
+	AllowedMethods []string
+
+	// AllowedHeaders lists request headers allowed in addition to the ones sent
+	// by the Inertia client.
+	AllowedHeaders []string
+
+	// AllowCredentials allows cookies and authorization headers on cross-origin
+	// requests. The request origin is then echoed instead of "*".
+	AllowCredentials bool
+
+	// MaxAge is how long browsers may cache preflight responses. Zero omits the
+	// header.
+	MaxAge time.Duration
+}
+
+// allowsOrigin reports whether origin may make cross-origin requests.
+func (o CORSOptions) allowsOrigin(origin string) bool {
+	for _, allowed := range o.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return o.AllowOriginFunc != nil && o.AllowOriginFunc(origin)
+}
+
+// allowsAnyOrigin reports whether every origin is allowed with "*".
+func (o CORSOptions) allowsAnyOrigin() bool {
+	for _, allowed := range o.AllowedOrigins {
+		if allowed == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// CORSMiddleware returns a middleware that answers CORS requests for Inertia
+// endpoints served to a frontend on another origin. Allowed origins may send the
+// X-Inertia request headers and read X-Inertia-Location, which external
+// redirects depend on. Preflight OPTIONS requests are answered directly; those
+// from origins that are not allowed get 403 Forbidden. Other requests from
+// disallowed origins are passed on without CORS headers, so browsers block the
+// response.
+//
+// Place it outside Middleware so preflight requests are answered first:
+//
+//	handler := mgr.CORSMiddleware(inertia.CORSOptions{
+//		AllowedOrigins:   []string{"https://app.example.com"},
+//		AllowCredentials: true,
+//	})(mgr.Middleware()(mux))
+func (i *Inertia) CORSMiddleware(opts CORSOptions) func(http.Handler) http.Handler {
+	methods := opts.AllowedMethods
+	if len(methods) == 0 {
+		methods = defaultCORSMethods()
+	}
+	allowMethods := strings.Join(methods, ", ")
+	allowHeaders := strings.Join(append(corsAllowedHeaders(), opts.AllowedHeaders...), ", ")
+	exposeHeaders := strings.Join(corsExposedHeaders(), ", ")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+			w.Header().Add("Vary", "Origin")
+
+			if !opts.allowsOrigin(origin) {
+				if preflight {
+					w.WriteHeader(http.StatusForbidden)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if opts.allowsAnyOrigin() && !opts.AllowCredentials {
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+			} else {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+			}
+			if opts.AllowCredentials {
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			if !preflight {
+				w.Header().Set("Access-Control-Expose-Headers", exposeHeaders)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("Access-Control-Allow-Methods", allowMethods)
+			w.Header().Set("Access-Control-Allow-Headers", allowHeaders)
+			if opts.MaxAge > 0 {
+				w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(opts.MaxAge.Seconds())))
+			}
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package inertia_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/toutaio/toutago-inertia/pkg/inertia"
+)
+
+func TestCORSMiddleware(t *testing.T) {
+	i, err := inertia.New(inertia.Config{
+		RootView: "app.html",
+		Version:  "1.0.0",
+	})
+	require.NoError(t, err)
+
+	called := false
+	handler := i.CORSMiddleware(inertia.CORSOptions{
+		AllowedOrigins:   []string{"https://app.example.com"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	serve := func(method, origin string) *httptest.ResponseRecorder {
+		called = false
+		req := httptest.NewRequest(method, "/users", http.NoBody)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		if method == http.MethodOptions {
+			req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+			req.Header.Set("Access-Control-Request-Headers", "X-Inertia, X-Inertia-Version")
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("preflight from allowed origin", func(t *testing.T) {
+		w := serve(http.MethodOptions, "https://app.example.com")
+
+		assert.False(t, called, "preflight should not reach the handler")
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+		assert.Equal(t, "GET, POST, PUT, PATCH, DELETE", w.Header().Get("Access-Control-Allow-Methods"))
+		assert.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"))
+
+		allowed := w.Header().Get("Access-Control-Allow-Headers")
+		for _, header := range []string{
+			"X-Inertia", "X-Inertia-Version", "X-Inertia-Partial-Data",
+			"X-Inertia-Partial-Component", "X-Inertia-Partial-Except", "X-Inertia-Error-Bag",
+		} {
+			assert.Contains(t, allowed, header)
+		}
+		assert.Contains(t, w.Header().Values("Vary"), "Origin")
+	})
+
+	t.Run("request from allowed origin", func(t *testing.T) {
+		w := serve(http.MethodGet, "https://app.example.com")
+
+		assert.True(t, called)
+		assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Contains(t, w.Header().Get("Access-Control-Expose-Headers"), "X-Inertia-Location")
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Methods"))
+	})
+
+	t.Run("preflight from rejected origin", func(t *testing.T) {
+		w := serve(http.MethodOptions, "https://evil.example.com")
+
+		assert.False(t, called)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Headers"))
+	})
+
+	t.Run("request from rejected origin", func(t *testing.T) {
+		w := serve(http.MethodGet, "https://evil.example.com")
+
+		assert.True(t, called)
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Empty(t, w.Header().Get("Access-Control-Expose-Headers"))
+	})
+
+	t.Run("same-origin request", func(t *testing.T) {
+		w := serve(http.MethodGet, "")
+
+		assert.True(t, called)
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	})
+}
+
+func TestCORSMiddleware_AnyOrigin(t *testing.T) {
+	i, err := inertia.New(inertia.Config{
+		RootView: "app.html",
+		Version:  "1.0.0",
+	})
+	require.NoError(t, err)
+
+	handler := i.CORSMiddleware(inertia.CORSOptions{
+		AllowedOrigins: []string{"*"},
+		AllowedHeaders: []string{"X-CSRF-Token"},
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodOptions, "/", http.NoBody)
+	req.Header.Set("Origin", "https://any.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Contains(t, w.Header().Get("Access-Control-Allow-Headers"), "X-CSRF-Token")
+	assert.Empty(t, w.Header().Get("Access-Control-Max-Age"))
+}
@@ -0,0 +1,95 @@
+// Package cosan adapts Inertia to the Cosan router. Go cannot add methods to
+// Cosan's own context type, so handlers wrap it with New to get the ctx.Inertia
+// helpers:
+//
+//	func createTodo(c cosan.Context) error {
+//		ctx := inertiacosan.New(c, mgr)
+//		if title == "" {
+//			return ctx.InertiaValidationErrors(map[string]string{"title": "Title is required"})
+//		}
+//		return ctx.InertiaRedirect("/todos")
+//	}
+//
+// Import it under an alias such as inertiacosan to avoid clashing with the
+// router package.
+package cosan
+
+import (
+	"fmt"
+
+	"github.com/toutaio/toutago-inertia/pkg/inertia"
+)
+
+// sessionErrorsKey is the SessionStore key of the validation errors flashed by
+// InertiaValidationErrors.
+const sessionErrorsKey = "errors"
+
+// Context wraps a Cosan request context with Inertia helpers. The wrapped
+// context's own methods remain available.
+type Context struct {
+	inertia.ContextInterface
+	ic  *inertia.InertiaContext
+	mgr *inertia.Inertia
+}
+
+// New wraps a Cosan request context, or any router context implementing
+// inertia.ContextInterface, for rendering with mgr.
+func New(ctx inertia.ContextInterface, mgr *inertia.Inertia) *Context {
+	return &Context{
+		ContextInterface: ctx,
+		ic:               inertia.NewContext(ctx, mgr),
+		mgr:              mgr,
+	}
+}
+
+// InertiaContext returns the underlying InertiaContext, for helpers this
+// adapter does not expose such as Lazy or WithFlash.
+func (c *Context) InertiaContext() *inertia.InertiaContext {
+	return c.ic
+}
+
+// Inertia renders component with props. Validation errors flashed by
+// InertiaValidationErrors in the previous request are exposed as the errors
+// prop (see inertia.Config.ErrorsPropKey) unless props set it.
+func (c *Context) Inertia(component string, props inertia.Props) error {
+	if store := c.mgr.SessionStore(); store != nil {
+		if errors, ok := store.Pull(c.Response(), c.Request(), sessionErrorsKey); ok && errors != nil {
+			c.ic.Share(c.mgr.ErrorsPropKey(), errors)
+		}
+	}
+	return c.ic.Render(component, props)
+}
+
+// InertiaRedirect redirects to url. Inertia requests get 303 See Other so the
+// client follows it with a GET.
+func (c *Context) InertiaRedirect(url string) error {
+	return c.ic.Redirect(url)
+}
+
+// InertiaValidationErrors redirects back to the previous page with one error
+// message per field, which its next Inertia render exposes as the errors prop,
+// nested under the error bag of the request if any. The errors survive the
+// redirect through the SessionStore configured with Inertia.SetSessionStore;
+// without one they are dropped.
+func (c *Context) InertiaValidationErrors(errors map[string]string) error {
+	validation := inertia.NewValidationErrors()
+	for field, message := range errors {
+		validation.Add(field, message)
+	}
+
+	if store := c.mgr.SessionStore(); store != nil && validation.Any() {
+		var flashed interface{} = validation
+		if bag := inertia.GetErrorBag(c.Request()); bag != "" {
+			flashed = map[string]inertia.ValidationErrors{bag: validation}
+		}
+		if err := store.Flash(c.Response(), c.Request(), sessionErrorsKey, flashed); err != nil {
+			return fmt.Errorf("inertia: failed to flash errors: %w", err)
+		}
+	}
+	return c.ic.Back()
+}
+
+// InertiaError renders the error page with status and message.
+func (c *Context) InertiaError(status int, message string) error {
+	return c.ic.Error(status, message)
+}
@@ -0,0 +1,157 @@
+package cosan_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/toutaio/toutago-inertia/pkg/inertia"
+	"github.com/toutaio/toutago-inertia/pkg/inertia/cosan"
+)
+
+// mockCosanContext mimics the request context of the Cosan router.
+type mockCosanContext struct {
+	w      http.ResponseWriter
+	r      *http.Request
+	values map[string]interface{}
+}
+
+func newMockCosanContext(w http.ResponseWriter, r *http.Request) *mockCosanContext {
+	return &mockCosanContext{w: w, r: r, values: make(map[string]interface{})}
+}
+
+func (c *mockCosanContext) Request() *http.Request            { return c.r }
+func (c *mockCosanContext) Response() http.ResponseWriter     { return c.w }
+func (c *mockCosanContext) Set(key string, value interface{}) { c.values[key] = value }
+func (c *mockCosanContext) Get(key string) interface{}        { return c.values[key] }
+
+func newInertia(t *testing.T) *inertia.Inertia {
+	t.Helper()
+
+	mgr, err := inertia.New(inertia.Config{RootView: "app.html", Version: "1.0.0"})
+	require.NoError(t, err)
+	return mgr
+}
+
+func newInertiaRequest(method, target string) *http.Request {
+	req := httptest.NewRequest(method, target, http.NoBody)
+	req.Header.Set("X-Inertia", "true")
+	return req
+}
+
+func decodePage(t *testing.T, w *httptest.ResponseRecorder) inertia.Page {
+	t.Helper()
+
+	var page inertia.Page
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	return page
+}
+
+func TestContext_Inertia(t *testing.T) {
+	mgr := newInertia(t)
+
+	w := httptest.NewRecorder()
+	ctx := cosan.New(newMockCosanContext(w, newInertiaRequest("GET", "/todos")), mgr)
+	require.NoError(t, ctx.Inertia("Todos/Index", inertia.Props{"todos": []string{"write docs"}}))
+
+	page := decodePage(t, w)
+	assert.Equal(t, "Todos/Index", page.Component)
+	assert.Equal(t, []interface{}{"write docs"}, page.Props["todos"])
+}
+
+func TestContext_InertiaRedirect(t *testing.T) {
+	mgr := newInertia(t)
+
+	w := httptest.NewRecorder()
+	ctx := cosan.New(newMockCosanContext(w, newInertiaRequest("PUT", "/todos/1")), mgr)
+	require.NoError(t, ctx.InertiaRedirect("/todos"))
+
+	assert.Equal(t, http.StatusSeeOther, w.Code)
+	assert.Equal(t, "/todos", w.Header().Get("Location"))
+}
+
+func TestContext_InertiaValidationErrors(t *testing.T) {
+	mgr := newInertia(t)
+	mgr.SetSessionStore(inertia.NewMemorySessionStore())
+
+	req := newInertiaRequest("POST", "/todos")
+	req.Header.Set("Referer", "/todos/create")
+	w := httptest.NewRecorder()
+	ctx := cosan.New(newMockCosanContext(w, req), mgr)
+	require.NoError(t, ctx.InertiaValidationErrors(map[string]string{"title": "Title must be at least 3 characters"}))
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, "/todos/create", w.Header().Get("X-Inertia-Location"))
+
+	// The errors reach the page the client is redirected to
+	next := newInertiaRequest("GET", "/todos/create")
+	for _, cookie := range w.Result().Cookies() {
+		next.AddCookie(cookie)
+	}
+	w = httptest.NewRecorder()
+	require.NoError(t, cosan.New(newMockCosanContext(w, next), mgr).Inertia("Todos/Create", nil))
+
+	page := decodePage(t, w)
+	assert.Equal(t, map[string]interface{}{"title": []interface{}{"Title must be at least 3 characters"}}, page.Props["errors"])
+
+	// Flashed errors are only available once
+	w = httptest.NewRecorder()
+	require.NoError(t, cosan.New(newMockCosanContext(w, next), mgr).Inertia("Todos/Create", nil))
+	assert.NotContains(t, decodePage(t, w).Props, "errors")
+}
+
+func TestContext_InertiaValidationErrors_ErrorBagAndPropKey(t *testing.T) {
+	mgr, err := inertia.New(inertia.Config{RootView: "app.html", Version: "1.0.0", ErrorsPropKey: "formErrors"})
+	require.NoError(t, err)
+	mgr.SetSessionStore(inertia.NewMemorySessionStore())
+
+	req := newInertiaRequest("POST", "/todos")
+	req.Header.Set("Referer", "/todos/create")
+	req.Header.Set("X-Inertia-Error-Bag", "createTodo")
+	w := httptest.NewRecorder()
+	handler := mgr.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := cosan.New(newMockCosanContext(w, r), mgr)
+		require.NoError(t, ctx.InertiaValidationErrors(map[string]string{"title": "Title is required"}))
+	}))
+	handler.ServeHTTP(w, req)
+
+	next := newInertiaRequest("GET", "/todos/create")
+	for _, cookie := range w.Result().Cookies() {
+		next.AddCookie(cookie)
+	}
+	w = httptest.NewRecorder()
+	require.NoError(t, cosan.New(newMockCosanContext(w, next), mgr).Inertia("Todos/Create", nil))
+
+	page := decodePage(t, w)
+	assert.Equal(t, map[string]interface{}{
+		"createTodo": map[string]interface{}{"title": []interface{}{"Title is required"}},
+	}, page.Props["formErrors"])
+	assert.NotContains(t, page.Props, "errors")
+}
+
+func TestContext_InertiaError(t *testing.T) {
+	mgr := newInertia(t)
+
+	w := httptest.NewRecorder()
+	ctx := cosan.New(newMockCosanContext(w, newInertiaRequest("GET", "/todos/9")), mgr)
+	require.NoError(t, ctx.InertiaError(http.StatusNotFound, "Todo not found"))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	page := decodePage(t, w)
+	assert.Equal(t, "Error", page.Component)
+	assert.Equal(t, "Todo not found", page.Props["message"])
+}
+
+func TestContext_WrappedContext(t *testing.T) {
+	mgr := newInertia(t)
+	base := newMockCosanContext(httptest.NewRecorder(), newInertiaRequest("GET", "/"))
+
+	ctx := cosan.New(base, mgr)
+	ctx.Set("user", "ada")
+
+	assert.Equal(t, "ada", base.Get("user"))
+	assert.NotNil(t, ctx.InertiaContext())
+}
@@ -0,0 +1,57 @@
+package inertia
+
+import "sort"
+
+// debugPropKey is the prop injected when Config.Debug is enabled.
+const debugPropKey = "__inertia_debug"
+
+// DebugInfo describes how a page was rendered. It is injected as the
+// __inertia_debug prop when Config.Debug is enabled.
+type DebugInfo struct {
+	Component string   `json:"component"`
+	Version   string   `json:"version"`
+	Partial   bool     `json:"partial"`
+	Only      []string `json:"only,omitempty"`
+	Lazy      []string `json:"lazy"`
+	Defer     []string `json:"defer"`
+	Always    []string `json:"always"`
+}
+
+// attachDebugInfo adds the debug prop to the page when Config.Debug is enabled.
+func (ic *InertiaContext) attachDebugInfo(page *Page, only []string) {
+	if !ic.mgr.config.Debug {
+		return
+	}
+
+	info := DebugInfo{
+		Component: page.Component,
+		Version:   page.Version,
+		Partial:   len(only) > 0,
+		Only:      only,
+		Lazy:      []string{},
+		Defer:     []string{},
+		Always:    []string{},
+	}
+
+	for key, lazyProp := range ic.getLazyPropsFromContext() {
+		switch lazyProp.Group {
+		case "lazy":
+			info.Lazy = append(info.Lazy, key)
+		case "defer":
+			info.Defer = append(info.Defer, key)
+		case "always":
+			info.Always = append(info.Always, key)
+		}
+	}
+	if alwaysProps, ok := ic.ctx.Get("_inertia_always_props").(map[string]interface{}); ok {
+		for key := range alwaysProps {
+			info.Always = append(info.Always, key)
+		}
+	}
+
+	sort.Strings(info.Lazy)
+	sort.Strings(info.Defer)
+	sort.Strings(info.Always)
+
+	page.Props[debugPropKey] = info
+}
@@ -0,0 +1,79 @@
+package inertia_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/toutaio/toutago-inertia/pkg/inertia"
+)
+
+func TestInertiaContext_Debug(t *testing.T) {
+	render := func(t *testing.T, debug bool, partial bool) map[string]interface{} {
+		t.Helper()
+
+		mgr, err := inertia.New(inertia.Config{
+			RootView: "app.html",
+			Version:  "1.0.0",
+			Debug:    debug,
+		})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest("GET", "/users", http.NoBody)
+		req.Header.Set("X-Inertia", "true")
+		if partial {
+			req.Header.Set("X-Inertia-Partial-Data", "users")
+			req.Header.Set("X-Inertia-Partial-Component", "Users/Index")
+		}
+
+		var capturedReq *http.Request
+		handler := mgr.Middleware()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			capturedReq = r
+		}))
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		w := httptest.NewRecorder()
+		ictx := inertia.NewContext(NewMockContext(w, capturedReq), mgr)
+		ictx.Lazy("stats", func() interface{} { return 1 })
+		ictx.Defer("report", func() interface{} { return 2 })
+		ictx.Always("auth", "guest")
+
+		require.NoError(t, ictx.Render("Users/Index", map[string]interface{}{
+			"users": []string{"Alice"},
+		}))
+
+		var page inertia.Page
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		return page.Props
+	}
+
+	t.Run("debug prop describes the render", func(t *testing.T) {
+		props := render(t, true, false)
+
+		debug, ok := props["__inertia_debug"].(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, "Users/Index", debug["component"])
+		assert.Equal(t, "1.0.0", debug["version"])
+		assert.Equal(t, false, debug["partial"])
+		assert.Equal(t, []interface{}{"stats"}, debug["lazy"])
+		assert.Equal(t, []interface{}{"report"}, debug["defer"])
+		assert.Equal(t, []interface{}{"auth"}, debug["always"])
+	})
+
+	t.Run("debug prop reports partial reloads", func(t *testing.T) {
+		props := render(t, true, true)
+
+		debug, ok := props["__inertia_debug"].(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, true, debug["partial"])
+		assert.Equal(t, []interface{}{"users"}, debug["only"])
+	})
+
+	t.Run("debug prop absent when disabled", func(t *testing.T) {
+		props := render(t, false, false)
+		assert.NotContains(t, props, "__inertia_debug")
+	})
+}
@@ -0,0 +1,21 @@
+package inertia
+
+import "errors"
+
+// Errors returned for invalid configuration and render arguments. Use errors.Is
+// to check for them.
+var (
+	// ErrRootViewRequired is returned by Config.Validate when neither RootView
+	// nor TemplateFS with TemplateName is set.
+	ErrRootViewRequired = errors.New("inertia: RootView is required")
+
+	// ErrComponentRequired is returned when rendering without a component name.
+	ErrComponentRequired = errors.New("inertia: component name is required")
+
+	// ErrURLRequired is returned when rendering without a page URL.
+	ErrURLRequired = errors.New("inertia: URL is required")
+
+	// ErrSSRFailed is returned by RenderSSR when the SSR renderer fails. The page
+	// data itself was valid, so the returned fallback HTML boots on the client.
+	ErrSSRFailed = errors.New("inertia: server-side rendering failed")
+)
@@ -0,0 +1,54 @@
+package inertia
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// WithETag enables ETag validation for the next Render. The ETag is computed over the
+// serialized page, and a request whose If-None-Match matches receives a 304 without a body.
+//
+// ETags are only used for GET requests without lazy or deferred props, flash messages,
+// old input, validation errors or failed props, since those responses are not cacheable.
+func (ic *InertiaContext) WithETag() *InertiaContext {
+	ic.etag = true
+	return ic
+}
+
+// etagCacheable reports whether the current render may be answered with an ETag.
+// It must be called after the props are assembled, since session data pulled then
+// is only delivered once, and before pending flash and errors are attached to the
+// page.
+func (ic *InertiaContext) etagCacheable(req *http.Request) bool {
+	if !ic.etag || req.Method != http.MethodGet {
+		return false
+	}
+	if ic.pendingFlash != nil || ic.pendingErrors != nil || ic.propErrors != nil || ic.sessionPulled {
+		return false
+	}
+	for _, lazyProp := range ic.getLazyPropsFromContext() {
+		if lazyProp.Group == "lazy" || lazyProp.Group == "defer" {
+			return false
+		}
+	}
+	return true
+}
+
+// computeETag returns a strong ETag for a response body.
+func computeETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches reports whether an If-None-Match header matches etag.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+package inertia_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/toutaio/toutago-inertia/pkg/inertia"
+)
+
+func TestInertiaContext_WithETag(t *testing.T) {
+	config := inertia.Config{
+		RootView: "app.html",
+		Version:  "1.0.0",
+	}
+
+	mgr, err := inertia.New(config)
+	require.NoError(t, err)
+
+	props := func() map[string]interface{} {
+		return map[string]interface{}{"posts": []string{"Post 1", "Post 2"}}
+	}
+
+	render := func(ifNoneMatch string, setup func(*inertia.InertiaContext)) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/posts", http.NoBody)
+		req.Header.Set("X-Inertia", "true")
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		ictx := inertia.NewContext(NewMockContext(w, req), mgr).WithETag()
+		if setup != nil {
+			setup(ictx)
+		}
+		require.NoError(t, ictx.Render("Posts/Index", props()))
+		return w
+	}
+
+	first := render("", nil)
+	etag := first.Header().Get("ETag")
+
+	t.Run("miss returns 200 with ETag", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, first.Code)
+		assert.NotEmpty(t, etag)
+		assert.Contains(t, first.Body.String(), "Post 1")
+	})
+
+	t.Run("hit returns 304 without body", func(t *testing.T) {
+		w := render(etag, nil)
+
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Body.String())
+	})
+
+	t.Run("stale ETag returns 200", func(t *testing.T) {
+		w := render(`"stale"`, nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, etag, w.Header().Get("ETag"))
+	})
+
+	t.Run("lazy props disable ETags", func(t *testing.T) {
+		w := render(etag, func(ictx *inertia.InertiaContext) {
+			ictx.Defer("stats", func() interface{} { return 1 })
+		})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("ETag"))
+	})
+
+	t.Run("flash disables ETags", func(t *testing.T) {
+		w := render(etag, func(ictx *inertia.InertiaContext) {
+			ictx.WithSuccess("Saved")
+		})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("ETag"))
+	})
+
+	t.Run("failed props disable ETags", func(t *testing.T) {
+		w := render(etag, func(ictx *inertia.InertiaContext) {
+			ictx.AlwaysLazy("stats", func() interface{} { panic("boom") })
+		})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("ETag"))
+	})
+}
+
+func TestInertiaContext_WithETag_SessionFlash(t *testing.T) {
+	mgr, err := inertia.New(inertia.Config{RootView: "app.html", Version: "1.0.0"})
+	require.NoError(t, err)
+	store := inertia.NewMemorySessionStore()
+	mgr.SetSessionStore(store)
+
+	render := func(cookies []*http.Cookie, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/posts", http.NoBody)
+		req.Header.Set("X-Inertia", "true")
+		req.Header.Set("If-None-Match", ifNoneMatch)
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+		w := httptest.NewRecorder()
+		ictx := inertia.NewContext(NewMockContext(w, req), mgr).WithETag().WithSessionFlash()
+		require.NoError(t, ictx.Render("Posts/Index", map[string]interface{}{"posts": []string{"Post 1"}}))
+		return w
+	}
+
+	etag := render(nil, "").Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	flashed := httptest.NewRecorder()
+	require.NoError(t, store.Flash(flashed, httptest.NewRequest("POST", "/posts", http.NoBody), "flash",
+		map[string]interface{}{"success": "Saved"}))
+
+	// The flash is pulled from the session, so a 304 would lose it
+	w := render(flashed.Result().Cookies(), etag)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("ETag"))
+	assert.Contains(t, w.Body.String(), "Saved")
+}
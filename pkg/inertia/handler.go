@@ -0,0 +1,46 @@
+package inertia
+
+import "net/http"
+
+// Props is a map of page props.
+type Props map[string]interface{}
+
+// Handler returns an http.HandlerFunc that renders component with the props
+// returned by propsFunc, for routes on plain net/http without a router context.
+// Responses are negotiated like InertiaContext.Render: page JSON for Inertia
+// requests and the root template for full page loads. propsFunc may be nil.
+//
+// Behind Middleware it renders through the request's InertiaContext (see
+// FromRequest), so data set on it by earlier middleware, such as SetUser or
+// Share, is kept. If propsFunc returns an error, the error page is rendered with
+// status 500.
+// Wrap the handler with Middleware so versioning and partial reloads apply:
+//
+//	mux.Handle("/users", mgr.Middleware()(mgr.Handler("Users/Index", func(r *http.Request) (inertia.Props, error) {
+//		return inertia.Props{"users": users}, nil
+//	})))
+func (i *Inertia) Handler(component string, propsFunc func(*http.Request) (Props, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ic := i.contextFor(w, r)
+
+		props := Props{}
+		if propsFunc != nil {
+			p, err := propsFunc(r)
+			if err != nil {
+				i.logf("inertia: failed to load props for %s: %v", component, err)
+				if err := ic.Error(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError)); err != nil {
+					http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				}
+				return
+			}
+			if p != nil {
+				props = p
+			}
+		}
+
+		if err := ic.Render(component, props); err != nil {
+			i.logf("inertia: failed to render %s: %v", component, err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package inertia_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/toutaio/toutago-inertia/pkg/inertia"
+)
+
+func TestInertia_Handler(t *testing.T) {
+	mgr, err := inertia.New(inertia.Config{
+		RootView: writeRootTemplate(t, "app.html", testRootTemplate),
+		Version:  "1.0.0",
+	})
+	require.NoError(t, err)
+	mgr.Share("appName", "Test App")
+
+	handler := mgr.Middleware()(mgr.Handler("Users/Index", func(r *http.Request) (inertia.Props, error) {
+		return inertia.Props{"query": r.URL.Query().Get("q")}, nil
+	}))
+
+	t.Run("Inertia request receives page JSON", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/users?q=alice", http.NoBody)
+		req.Header.Set("X-Inertia", "true")
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+		var page inertia.Page
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		assert.Equal(t, "Users/Index", page.Component)
+		assert.Equal(t, "alice", page.Props["query"])
+		assert.Equal(t, "Test App", page.Props["appName"])
+	})
+
+	t.Run("full page load receives HTML", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, newFullLoadRequest("/users?q=bob"))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Body.String(), `&#34;component&#34;:&#34;Users/Index&#34;`)
+		assert.Contains(t, w.Body.String(), `&#34;query&#34;:&#34;bob&#34;`)
+	})
+
+	t.Run("nil props func", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/about", http.NoBody)
+		req.Header.Set("X-Inertia", "true")
+		w := httptest.NewRecorder()
+
+		mgr.Handler("About", nil).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"component":"About"`)
+	})
+
+	t.Run("props error renders the error page", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/users", http.NoBody)
+		req.Header.Set("X-Inertia", "true")
+		w := httptest.NewRecorder()
+
+		mgr.Handler("Users/Index", func(*http.Request) (inertia.Props, error) {
+			return nil, errors.New("database unavailable")
+		}).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), `"component":"Error"`)
+		assert.NotContains(t, w.Body.String(), "database unavailable")
+	})
+}
+
+func TestInertia_Handler_UsesRequestContext(t *testing.T) {
+	mgr, err := inertia.New(inertia.Config{RootView: "app.html", Version: "1.0.0"})
+	require.NoError(t, err)
+
+	auth := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			inertia.FromRequest(r).SetUser(map[string]string{"name": "alice"}).Share("team", "core")
+			next.ServeHTTP(w, r)
+		})
+	}
+	handler := mgr.Middleware()(auth(mgr.Handler("Dashboard", nil)))
+
+	req := httptest.NewRequest("GET", "/dashboard", http.NoBody)
+	req.Header.Set("X-Inertia", "true")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	var page inertia.Page
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	assert.Equal(t, "Dashboard", page.Component)
+	assert.Equal(t, map[string]interface{}{"user": map[string]interface{}{"name": "alice"}}, page.Props["auth"])
+	assert.Equal(t, "core", page.Props["team"])
+}
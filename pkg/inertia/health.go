@@ -0,0 +1,110 @@
+package inertia
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// HealthStatus reports the state of the Inertia subsystems, see Health.
+type HealthStatus struct {
+	Healthy bool                   `json:"healthy"`
+	SSR     SSRHealth              `json:"ssr"`
+	Checks  map[string]CheckHealth `json:"checks,omitempty"`
+}
+
+// SSRHealth reports the state of server-side rendering.
+type SSRHealth struct {
+	// Enabled reports whether Config.SSR is set and a renderer is attached.
+	Enabled bool `json:"enabled"`
+	// Healthy is false when the renderer failed its ping.
+	Healthy bool   `json:"healthy"`
+	Error   string `json:"error,omitempty"`
+}
+
+// CheckHealth is the result of a check added with AddHealthCheck.
+type CheckHealth struct {
+	Healthy bool        `json:"healthy"`
+	Details interface{} `json:"details,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+// HealthCheck reports the state of a subsystem, with optional details such as
+// client counts. A non-nil error marks it unhealthy.
+type HealthCheck func(ctx context.Context) (details interface{}, err error)
+
+// healthCheck is a named HealthCheck.
+type healthCheck struct {
+	name  string
+	check HealthCheck
+}
+
+// AddHealthCheck adds a check reported by Health under name, e.g. for the
+// realtime hub:
+//
+//	mgr.AddHealthCheck("hub", func(context.Context) (interface{}, error) {
+//		return map[string]int{"clients": hub.ClientCount()}, nil
+//	})
+func (i *Inertia) AddHealthCheck(name string, check HealthCheck) {
+	i.healthChecks = append(i.healthChecks, healthCheck{name: name, check: check})
+}
+
+// Health reports whether the Inertia subsystems are working. When SSR is enabled
+// and the renderer supports it (as ssr.Renderer does with its Ping method), the
+// renderer is pinged; a failing renderer or health check makes the status
+// unhealthy. Disabled SSR is not a failure.
+func (i *Inertia) Health(ctx context.Context) HealthStatus {
+	status := HealthStatus{
+		Healthy: true,
+		SSR:     SSRHealth{Enabled: i.config.SSR && i.ssrRenderer != nil, Healthy: true},
+	}
+
+	if status.SSR.Enabled {
+		if pinger, ok := i.ssrRenderer.(interface{ Ping(context.Context) error }); ok {
+			if err := pinger.Ping(ctx); err != nil {
+				status.SSR.Healthy = false
+				status.SSR.Error = err.Error()
+				status.Healthy = false
+			}
+		}
+	}
+
+	if len(i.healthChecks) > 0 {
+		status.Checks = make(map[string]CheckHealth, len(i.healthChecks))
+	}
+	for _, hc := range i.healthChecks {
+		details, err := hc.check(ctx)
+		result := CheckHealth{Healthy: err == nil, Details: details}
+		if err != nil {
+			result.Error = err.Error()
+			status.Healthy = false
+		}
+		status.Checks[hc.name] = result
+	}
+
+	return status
+}
+
+// HealthHandler returns a handler for health endpoints such as /healthz. It
+// writes the Health status as JSON with 200 OK, or 503 Service Unavailable when
+// unhealthy.
+func (i *Inertia) HealthHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		status := i.Health(r.Context())
+
+		body, err := json.Marshal(status)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		code := http.StatusOK
+		if !status.Healthy {
+			code = http.StatusServiceUnavailable
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(code)
+		_, _ = w.Write(append(body, '\n'))
+	}
+}
@@ -0,0 +1,102 @@
+package inertia_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/toutaio/toutago-inertia/pkg/inertia"
+	"github.com/toutaio/toutago-inertia/pkg/ssr"
+)
+
+func TestInertia_Health(t *testing.T) {
+	newRenderer := func(t *testing.T, bundle string) *ssr.Renderer {
+		t.Helper()
+
+		renderer, err := ssr.NewRenderer(&ssr.Config{PoolSize: 1})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = renderer.Close() })
+		require.NoError(t, renderer.LoadBundle(bundle))
+		return renderer
+	}
+
+	t.Run("SSR enabled and healthy", func(t *testing.T) {
+		mgr, err := inertia.New(inertia.Config{RootView: "app.html", SSR: true})
+		require.NoError(t, err)
+		mgr.SetSSRRenderer(newRenderer(t, `global.render = function(page) { return '<div></div>'; };`))
+
+		status := mgr.Health(context.Background())
+		assert.True(t, status.Healthy)
+		assert.Equal(t, inertia.SSRHealth{Enabled: true, Healthy: true}, status.SSR)
+	})
+
+	t.Run("SSR enabled and unresponsive", func(t *testing.T) {
+		mgr, err := inertia.New(inertia.Config{RootView: "app.html", SSR: true})
+		require.NoError(t, err)
+		renderer := newRenderer(t, `global.render = function(page) { return '<div></div>'; };`)
+		require.NoError(t, renderer.Close())
+		mgr.SetSSRRenderer(renderer)
+
+		status := mgr.Health(context.Background())
+		assert.False(t, status.Healthy)
+		assert.Equal(t, inertia.SSRHealth{Enabled: true, Healthy: false, Error: "renderer is closed"}, status.SSR)
+	})
+
+	t.Run("SSR disabled", func(t *testing.T) {
+		mgr, err := inertia.New(inertia.Config{RootView: "app.html"})
+		require.NoError(t, err)
+
+		status := mgr.Health(context.Background())
+		assert.True(t, status.Healthy)
+		assert.False(t, status.SSR.Enabled)
+		assert.Nil(t, status.Checks)
+	})
+
+	t.Run("health checks", func(t *testing.T) {
+		mgr, err := inertia.New(inertia.Config{RootView: "app.html"})
+		require.NoError(t, err)
+		mgr.AddHealthCheck("hub", func(context.Context) (interface{}, error) {
+			return map[string]int{"clients": 3}, nil
+		})
+		mgr.AddHealthCheck("bus", func(context.Context) (interface{}, error) {
+			return nil, errors.New("bus closed")
+		})
+
+		status := mgr.Health(context.Background())
+		assert.False(t, status.Healthy)
+		assert.Equal(t, inertia.CheckHealth{Healthy: true, Details: map[string]int{"clients": 3}}, status.Checks["hub"])
+		assert.Equal(t, inertia.CheckHealth{Healthy: false, Error: "bus closed"}, status.Checks["bus"])
+	})
+}
+
+func TestInertia_HealthHandler(t *testing.T) {
+	mgr, err := inertia.New(inertia.Config{RootView: "app.html"})
+	require.NoError(t, err)
+
+	serve := func() (*httptest.ResponseRecorder, inertia.HealthStatus) {
+		w := httptest.NewRecorder()
+		mgr.HealthHandler()(w, httptest.NewRequest("GET", "/healthz", http.NoBody))
+
+		var status inertia.HealthStatus
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+		return w, status
+	}
+
+	w, status := serve()
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.True(t, status.Healthy)
+
+	mgr.AddHealthCheck("db", func(context.Context) (interface{}, error) {
+		return nil, errors.New("connection refused")
+	})
+	w, status = serve()
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.False(t, status.Healthy)
+	assert.Equal(t, "connection refused", status.Checks["db"].Error)
+}
@@ -0,0 +1,28 @@
+package inertia
+
+import "net/http"
+
+// httpContext adapts a plain http.ResponseWriter and *http.Request to ContextInterface.
+type httpContext struct {
+	w      http.ResponseWriter
+	r      *http.Request
+	values map[string]interface{}
+}
+
+// newHTTPContext creates a ContextInterface for a net/http request.
+func newHTTPContext(w http.ResponseWriter, r *http.Request) *httpContext {
+	return &httpContext{
+		w:      w,
+		r:      r,
+		values: make(map[string]interface{}),
+	}
+}
+
+func (c *httpContext) Request() *http.Request        { return c.r }
+func (c *httpContext) Response() http.ResponseWriter { return c.w }
+func (c *httpContext) Set(key string, value interface{}) {
+	c.values[key] = value
+}
+func (c *httpContext) Get(key string) interface{} {
+	return c.values[key]
+}
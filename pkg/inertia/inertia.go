@@ -3,8 +3,13 @@ package inertia
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
 )
 
 // Response represents an Inertia.js page response.
@@ -27,6 +32,10 @@ type Page struct {
 	Props     map[string]interface{} `json:"props"`
 	URL       string                 `json:"url"`
 	Version   string                 `json:"version"`
+
+	// ResetScroll asks a cooperating frontend to reset scroll positions: true for
+	// all regions, or a list of region keys. It is omitted when unset.
+	ResetScroll interface{} `json:"resetScroll,omitempty"`
 }
 
 // NewPage creates a new Inertia page.
@@ -54,16 +63,100 @@ func (p *Page) MergeSharedData(shared map[string]interface{}) {
 
 // Config holds Inertia configuration.
 type Config struct {
-	RootView string // Path to root template
-	Version  string // Asset version
-	SSR      bool   // Enable server-side rendering
-	AssetURL string // Base URL for assets
+	RootView  string // Path to root template
+	Version   string // Asset version
+	SSR       bool   // Enable server-side rendering
+	AssetURL  string // Base URL for assets
+	MaxMemory int64  // Maximum bytes of a multipart body kept in memory (default 32 MB)
+	Debug     bool   // Inject a __inertia_debug prop describing each render (development only)
+
+	// TreatXHRAsInertia handles X-Requested-With: XMLHttpRequest requests like
+	// X-Inertia requests, answering them with page JSON.
+	TreatXHRAsInertia bool
+
+	// ErrorComponent is the component rendered by Error and RecoverMiddleware (default "Error").
+	ErrorComponent string
+
+	// ErrorsPropKey is the prop that carries validation errors set with
+	// WithErrors (default "errors").
+	ErrorsPropKey string
+
+	// StaticSharedDataOnError makes Error merge only static shared data, skipping
+	// ShareFunc values, which may fail or hang along with the request (e.g. when
+	// the database is down). Error pages then render regardless of shared functions.
+	StaticSharedDataOnError bool
+
+	// Compression gzips page JSON responses when the client accepts it.
+	// Payloads smaller than CompressionThreshold bytes (default 1024) are sent as is.
+	Compression          bool
+	CompressionThreshold int
+
+	// SSRForRequests limits SSR to full page loads for which it returns true
+	// (e.g. crawlers); other requests get the client-rendered shell. When nil,
+	// SSR runs for every full page load.
+	SSRForRequests func(*http.Request) bool
+
+	// StreamSSR sends the root template up to {{ .Inertia }} before server-side
+	// rendering, then streams the SSR body, for a faster time to first byte. It
+	// needs a renderer implementing StreamingSSRRenderer; SSR head tags are not
+	// sent, since the <head> has already been written.
+	StreamSSR bool
+
+	// RootViews registers additional root templates by layout name (e.g. "admin")
+	// for use with InertiaContext.RootView. RootView remains the default.
+	RootViews map[string]string
+
+	// VersionFunc computes the asset version per request (e.g. from a build manifest).
+	// When set, it takes precedence over Version.
+	VersionFunc func() string
+
+	// GenerateNonce generates a random CSP nonce for every full page load when no
+	// nonce was set with InertiaContext.Nonce. See InertiaContext.CSPNonce.
+	GenerateNonce bool
+
+	// VersionComparator decides whether the asset version sent by the client matches
+	// the server version. A mismatch forces a full reload. Defaults to exact equality.
+	VersionComparator func(client, server string) bool
+
+	// MaxPropBytes is the encoded size above which a page's props are reported,
+	// naming the largest prop, to catch accidentally huge payloads such as
+	// unpaginated lists. Oversized props are logged, or fail the render in strict
+	// mode (see SetStrictProps). Zero disables the check.
+	MaxPropBytes int
+
+	// ListPropKey is the prop under which RenderList passes its items (default "data").
+	ListPropKey string
+
+	// TrustProxyHeaders builds the page URL from X-Forwarded-Proto and
+	// X-Forwarded-Host, so it reflects the public URL behind a reverse proxy.
+	// Only enable it when a proxy sets these headers.
+	TrustProxyHeaders bool
+
+	// ServerTiming adds a Server-Timing header to rendered pages reporting the
+	// time spent merging shared data (inertia-shared), evaluating lazy props
+	// (inertia-lazy), server-side rendering (inertia-ssr) and encoding the page
+	// (inertia-encode), to find which phase makes a page slow.
+	ServerTiming bool
+
+	// ValidateRootView makes New parse RootView and RootViews up front, so a
+	// missing or invalid root template fails at startup instead of on the first
+	// full page load. Leave it off when templates are only available later.
+	ValidateRootView bool
+
+	// TemplateFS, when set, is the filesystem root templates are read from,
+	// e.g. an embed.FS, instead of the OS filesystem. RootView and RootViews
+	// are then paths within it.
+	TemplateFS fs.FS
+
+	// TemplateName is the default root template within TemplateFS, used in place
+	// of RootView, which may then be left empty.
+	TemplateName string
 }
 
 // Validate checks if the config is valid.
 func (c Config) Validate() error {
-	if c.RootView == "" {
-		return errors.New("inertia: RootView is required")
+	if c.RootView == "" && (c.TemplateFS == nil || c.TemplateName == "") {
+		return ErrRootViewRequired
 	}
 	return nil
 }
@@ -76,13 +169,40 @@ type SSRRenderer interface {
 	RenderToString(ctx context.Context, pageData map[string]interface{}) (string, error)
 }
 
+// NamedSSRRenderer is an SSRRenderer that holds several named bundles,
+// e.g. separate entry points for an admin app and a public app.
+type NamedSSRRenderer interface {
+	SSRRenderer
+	RenderToStringNamed(ctx context.Context, name string, pageData map[string]interface{}) (string, error)
+}
+
+// StreamingSSRRenderer is an SSRRenderer that can write the body HTML of a
+// page to w as it is rendered, see Config.StreamSSR.
+type StreamingSSRRenderer interface {
+	SSRRenderer
+	RenderStream(ctx context.Context, pageData map[string]interface{}, w io.Writer) error
+}
+
 // Inertia is the main Inertia instance.
 type Inertia struct {
 	config      Config
 	version     string
 	sharedData  map[string]interface{}
 	sharedFunc  map[string]SharedDataFunc
+	sharedOnce  map[string]interface{}
 	ssrRenderer SSRRenderer
+	ssrBundles  map[string]string
+	templates   templateCache
+	logger      Logger
+	strictProps bool
+	sessions    SessionStore
+	marshalJSON func(v interface{}) ([]byte, error)
+	manifest    *componentManifest
+
+	transformers  []PropTransformer
+	sharePolicies map[string]SharePolicy
+	healthChecks  []healthCheck
+	pageDecorator PageDecorator
 }
 
 // New creates a new Inertia instance.
@@ -96,12 +216,24 @@ func New(config Config) (*Inertia, error) {
 		version = "1" // Default version
 	}
 
-	return &Inertia{
+	i := &Inertia{
 		config:     config,
 		version:    version,
 		sharedData: make(map[string]interface{}),
 		sharedFunc: make(map[string]SharedDataFunc),
-	}, nil
+		sharedOnce: make(map[string]interface{}),
+		ssrBundles: make(map[string]string),
+
+		sharePolicies: make(map[string]SharePolicy),
+	}
+
+	if config.ValidateRootView {
+		if err := i.loadRootViews(); err != nil {
+			return nil, err
+		}
+	}
+
+	return i, nil
 }
 
 // Share adds a static shared value.
@@ -114,25 +246,125 @@ func (i *Inertia) ShareFunc(key string, fn SharedDataFunc) {
 	i.sharedFunc[key] = fn
 }
 
+// ShareOnce adds a shared value that is only included on full page loads.
+// Subsequent Inertia navigations (X-Inertia requests) omit it.
+func (i *Inertia) ShareOnce(key string, value interface{}) {
+	i.sharedOnce[key] = value
+}
+
+// SharePolicy controls when a shared value is sent.
+type SharePolicy int
+
+const (
+	// ShareAlways sends the shared value with every response, including partial
+	// reloads. This is the default.
+	ShareAlways SharePolicy = iota
+
+	// ShareInitialOnly sends the shared value on full page loads and regular
+	// navigations, but omits it from partial reloads that do not request it.
+	// Use it for large values such as navigation menus.
+	ShareInitialOnly
+)
+
+// SetSharePolicy sets when the shared value under key (from Share or ShareFunc)
+// is sent. Functions of omitted values are not evaluated.
+//
+//	mgr.ShareFunc("menu", buildMenu)
+//	mgr.SetSharePolicy("menu", inertia.ShareInitialOnly)
+func (i *Inertia) SetSharePolicy(key string, policy SharePolicy) {
+	if policy == ShareAlways {
+		delete(i.sharePolicies, key)
+		return
+	}
+	i.sharePolicies[key] = policy
+}
+
 // GetSharedData returns all shared data (static + evaluated functions).
 func (i *Inertia) GetSharedData() map[string]interface{} {
+	return i.collectSharedData(func(string) bool { return true })
+}
+
+// partialSharedData returns the shared data for a partial reload of only,
+// leaving out initial-only values that were not requested.
+func (i *Inertia) partialSharedData(only []string) map[string]interface{} {
+	return i.collectSharedData(func(key string) bool {
+		return i.sharePolicies[key] != ShareInitialOnly || slices.Contains(only, key)
+	})
+}
+
+// collectSharedData returns the shared values whose key passes include.
+func (i *Inertia) collectSharedData(include func(key string) bool) map[string]interface{} {
 	result := make(map[string]interface{})
 
 	// Add static shared data
 	for key, value := range i.sharedData {
-		result[key] = value
+		if include(key) {
+			result[key] = value
+		}
 	}
 
 	// Evaluate and add function-based shared data
 	for key, fn := range i.sharedFunc {
-		result[key] = fn()
+		if include(key) {
+			result[key] = fn()
+		}
 	}
 
 	return result
 }
 
+// SharedKeys returns the sorted keys of all shared data (static, function-based and
+// ShareOnce values) without evaluating any shared functions.
+func (i *Inertia) SharedKeys() []string {
+	seen := make(map[string]bool)
+	keys := make([]string, 0, len(i.sharedData)+len(i.sharedFunc)+len(i.sharedOnce))
+
+	for _, shared := range []map[string]interface{}{i.sharedData, i.sharedOnce} {
+		for key := range shared {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	for key := range i.sharedFunc {
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+// HasShared reports whether key has been shared, without evaluating shared functions.
+func (i *Inertia) HasShared(key string) bool {
+	if _, ok := i.sharedData[key]; ok {
+		return true
+	}
+	if _, ok := i.sharedFunc[key]; ok {
+		return true
+	}
+	_, ok := i.sharedOnce[key]
+	return ok
+}
+
+// versionMatches reports whether the client's asset version is compatible with
+// the server's, using Config.VersionComparator when set.
+func (i *Inertia) versionMatches(client, server string) bool {
+	if i.config.VersionComparator != nil {
+		return i.config.VersionComparator(client, server)
+	}
+	return client == server
+}
+
 // Version returns the current asset version.
+// If Config.VersionFunc is set, it is evaluated on every call.
 func (i *Inertia) Version() string {
+	if i.config.VersionFunc != nil {
+		return i.config.VersionFunc()
+	}
 	return i.version
 }
 
@@ -141,22 +373,60 @@ func (i *Inertia) SetVersion(version string) {
 	i.version = version
 }
 
+// SetStrictProps enables strict mode, in which Render returns an error when a
+// handler prop shadows a shared data key. Without strict mode shadowing is only
+// logged (see SetLogger) and the handler prop wins.
+func (i *Inertia) SetStrictProps(strict bool) {
+	i.strictProps = strict
+}
+
+// checkShadowedProps reports props that shadow static or function-based shared data.
+func (i *Inertia) checkShadowedProps(component string, props map[string]interface{}) error {
+	var shadowed []string
+	for key := range props {
+		if _, ok := i.sharedData[key]; ok {
+			shadowed = append(shadowed, key)
+		} else if _, ok := i.sharedFunc[key]; ok {
+			shadowed = append(shadowed, key)
+		}
+	}
+	if len(shadowed) == 0 {
+		return nil
+	}
+
+	sort.Strings(shadowed)
+	i.logf("inertia: props %v of %s shadow shared data", shadowed, component)
+
+	if i.strictProps {
+		return fmt.Errorf("inertia: props %v of %s shadow shared data", shadowed, component)
+	}
+	return nil
+}
+
 // Render creates an Inertia response.
 func (i *Inertia) Render(component string, props map[string]interface{}, url string) (*Page, error) {
 	if component == "" {
-		return nil, fmt.Errorf("inertia: component name is required")
+		return nil, ErrComponentRequired
 	}
 
 	if url == "" {
-		return nil, fmt.Errorf("inertia: URL is required")
+		return nil, ErrURLRequired
 	}
 
 	if props == nil {
 		props = make(map[string]interface{})
 	}
 
-	page := NewPage(component, props, url, i.version)
+	if err := i.checkShadowedProps(component, props); err != nil {
+		return nil, err
+	}
+
+	page := NewPage(component, props, url, i.Version())
 	page.MergeSharedData(i.GetSharedData())
+	resolveProps(page.Props)
+	if err := i.transformProps(page.Props); err != nil {
+		return nil, err
+	}
 
 	return page, nil
 }
@@ -164,11 +434,11 @@ func (i *Inertia) Render(component string, props map[string]interface{}, url str
 // RenderOnly creates an Inertia response with only specified props.
 func (i *Inertia) RenderOnly(component string, props map[string]interface{}, url string, only []string) (*Page, error) {
 	if component == "" {
-		return nil, fmt.Errorf("inertia: component name is required")
+		return nil, ErrComponentRequired
 	}
 
 	if url == "" {
-		return nil, fmt.Errorf("inertia: URL is required")
+		return nil, ErrURLRequired
 	}
 
 	if props == nil {
@@ -183,13 +453,51 @@ func (i *Inertia) RenderOnly(component string, props map[string]interface{}, url
 		}
 	}
 
-	page := NewPage(component, filteredProps, url, i.version)
-	// Shared data is always included
-	page.MergeSharedData(i.GetSharedData())
+	if err := i.checkShadowedProps(component, filteredProps); err != nil {
+		return nil, err
+	}
+
+	page := NewPage(component, filteredProps, url, i.Version())
+	// Shared data is included unless it is initial-only and not requested
+	page.MergeSharedData(i.partialSharedData(only))
+	resolveProps(page.Props)
+	if err := i.transformProps(page.Props); err != nil {
+		return nil, err
+	}
 
 	return page, nil
 }
 
+// SetJSONMarshaler replaces encoding/json for encoding page responses and the
+// data-page attribute, e.g. with a faster drop-in such as jsoniter's Marshal.
+// The marshaler must produce output compatible with encoding/json.
+func (i *Inertia) SetJSONMarshaler(marshal func(v interface{}) ([]byte, error)) {
+	i.marshalJSON = marshal
+}
+
+// encodeJSON encodes v with the configured JSON marshaler.
+func (i *Inertia) encodeJSON(v interface{}) ([]byte, error) {
+	if i.marshalJSON != nil {
+		return i.marshalJSON(v)
+	}
+	return json.Marshal(v)
+}
+
+// WarmupSSR prepares the SSR renderer ahead of the first requests, e.g. at
+// startup after loading the bundle, if the renderer supports it (as
+// ssr.Renderer does with its Warmup method). It returns any bundle error and is a
+// no-op without a renderer or warmup support.
+func (i *Inertia) WarmupSSR() error {
+	warmer, ok := i.ssrRenderer.(interface{ Warmup() error })
+	if !ok {
+		return nil
+	}
+	if err := warmer.Warmup(); err != nil {
+		return fmt.Errorf("inertia: SSR warmup failed: %w", err)
+	}
+	return nil
+}
+
 // SetSSRRenderer sets the SSR renderer for server-side rendering.
 func (i *Inertia) SetSSRRenderer(renderer SSRRenderer) {
 	i.ssrRenderer = renderer
@@ -197,18 +505,80 @@ func (i *Inertia) SetSSRRenderer(renderer SSRRenderer) {
 
 // RenderSSR renders a page using server-side rendering.
 // Returns empty string if no SSR renderer is configured.
-// Returns error if SSR rendering fails.
+// If the renderer fails, it returns an error wrapping ErrSSRFailed together with
+// a client-bootable fallback: an empty app element carrying the page in its
+// data-page attribute, so the client renders the page from scratch. Errors that
+// do not wrap ErrSSRFailed mean the page itself could not be encoded.
 func (i *Inertia) RenderSSR(ctx context.Context, page *Page) (string, error) {
+	return i.renderSSRWith(ctx, page, nil)
+}
+
+// renderSSRWith is RenderSSR with props that are handed to the SSR renderer
+// under the serverProps key, apart from the page, see ServerOnly.
+func (i *Inertia) renderSSRWith(ctx context.Context, page *Page, serverProps map[string]interface{}) (string, error) {
+	html, err := i.renderSSR(ctx, page, serverProps)
+	if err == nil {
+		return html, nil
+	}
+
+	fallback, encodeErr := i.pageElement(page)
+	if encodeErr != nil {
+		return "", encodeErr
+	}
+	return fallback, fmt.Errorf("%w: %w", ErrSSRFailed, err)
+}
+
+// renderSSR passes the page to the SSR renderer, using its mapped bundle if any.
+func (i *Inertia) renderSSR(ctx context.Context, page *Page, serverProps map[string]interface{}) (string, error) {
 	if i.ssrRenderer == nil {
 		return "", nil
 	}
 
-	pageData := map[string]interface{}{
+	pageData := ssrPageData(page, serverProps)
+
+	if name, ok := i.ssrBundleFor(page.Component); ok {
+		if named, ok := i.ssrRenderer.(NamedSSRRenderer); ok {
+			return named.RenderToStringNamed(ctx, name, pageData)
+		}
+	}
+
+	return i.ssrRenderer.RenderToString(ctx, pageData)
+}
+
+// ssrServerPropsKey is the page data key holding server-only props, matching
+// ssr.ServerPropsKey: the ssr package passes them to the bundle as a separate
+// argument, outside the page.
+const ssrServerPropsKey = "serverProps"
+
+// ssrPageData returns the page object passed to SSR bundles, with serverProps
+// under ssrServerPropsKey when there are any.
+func ssrPageData(page *Page, serverProps map[string]interface{}) map[string]interface{} {
+	data := map[string]interface{}{
 		"component": page.Component,
 		"props":     page.Props,
 		"url":       page.URL,
 		"version":   page.Version,
 	}
+	if len(serverProps) > 0 {
+		data[ssrServerPropsKey] = serverProps
+	}
+	return data
+}
 
-	return i.ssrRenderer.RenderToString(ctx, pageData)
+// MapSSRBundle renders components whose name starts with prefix using the named
+// SSR bundle (e.g. MapSSRBundle("Admin/", "admin")). The renderer must implement
+// NamedSSRRenderer. Components without a matching prefix use the default bundle.
+func (i *Inertia) MapSSRBundle(prefix, bundle string) {
+	i.ssrBundles[prefix] = bundle
+}
+
+// ssrBundleFor returns the bundle mapped to the longest prefix matching component.
+func (i *Inertia) ssrBundleFor(component string) (string, bool) {
+	bundle, matched := "", -1
+	for prefix, name := range i.ssrBundles {
+		if strings.HasPrefix(component, prefix) && len(prefix) > matched {
+			bundle, matched = name, len(prefix)
+		}
+	}
+	return bundle, matched >= 0
 }
@@ -104,7 +104,25 @@ func TestNew_InvalidConfig(t *testing.T) {
 	}
 
 	_, err := inertia.New(config)
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, inertia.ErrRootViewRequired)
+}
+
+func TestInertia_RenderSentinelErrors(t *testing.T) {
+	i, err := inertia.New(inertia.Config{RootView: "app.html", Version: "1.0.0"})
+	require.NoError(t, err)
+
+	_, err = i.Render("", nil, "/")
+	assert.ErrorIs(t, err, inertia.ErrComponentRequired)
+	assert.EqualError(t, err, "inertia: component name is required")
+
+	_, err = i.RenderOnly("", nil, "/", []string{"user"})
+	assert.ErrorIs(t, err, inertia.ErrComponentRequired)
+
+	_, err = i.Render("Home", nil, "")
+	assert.ErrorIs(t, err, inertia.ErrURLRequired)
+
+	_, err = i.RenderOnly("Home", nil, "", []string{"user"})
+	assert.ErrorIs(t, err, inertia.ErrURLRequired)
 }
 
 func TestInertia_Share(t *testing.T) {
@@ -130,6 +148,85 @@ func TestInertia_Share(t *testing.T) {
 	assert.Contains(t, shared, "user")
 }
 
+func TestInertia_SharedKeys(t *testing.T) {
+	config := inertia.Config{
+		RootView: "app.html",
+		Version:  "1.0.0",
+	}
+
+	i, err := inertia.New(config)
+	require.NoError(t, err)
+
+	called := false
+	i.Share("app_name", "My App")
+	i.ShareFunc("user", func() interface{} {
+		called = true
+		return nil
+	})
+	i.ShareOnce("features", []string{"beta"})
+
+	assert.Equal(t, []string{"app_name", "features", "user"}, i.SharedKeys())
+	assert.True(t, i.HasShared("app_name"))
+	assert.True(t, i.HasShared("user"))
+	assert.True(t, i.HasShared("features"))
+	assert.False(t, i.HasShared("missing"))
+
+	// Introspection must not evaluate shared functions
+	assert.False(t, called)
+}
+
+func TestInertia_SharePolicy(t *testing.T) {
+	config := inertia.Config{
+		RootView: "app.html",
+		Version:  "1.0.0",
+	}
+
+	i, err := inertia.New(config)
+	require.NoError(t, err)
+
+	menuCalls := 0
+	i.Share("app_name", "My App")
+	i.ShareFunc("menu", func() interface{} {
+		menuCalls++
+		return []string{"Home", "Users"}
+	})
+	i.SetSharePolicy("menu", inertia.ShareInitialOnly)
+
+	t.Run("full render includes initial-only data", func(t *testing.T) {
+		page, err := i.Render("Users/Index", map[string]interface{}{"users": []string{}}, "/users")
+		require.NoError(t, err)
+
+		assert.Contains(t, page.Props, "menu")
+		assert.Contains(t, page.Props, "app_name")
+	})
+
+	t.Run("unrelated partial reload omits initial-only data", func(t *testing.T) {
+		menuCalls = 0
+		page, err := i.RenderOnly("Users/Index", map[string]interface{}{"users": []string{}}, "/users", []string{"users"})
+		require.NoError(t, err)
+
+		assert.NotContains(t, page.Props, "menu")
+		assert.Contains(t, page.Props, "app_name")
+		assert.Contains(t, page.Props, "users")
+		assert.Zero(t, menuCalls, "omitted shared functions should not be evaluated")
+	})
+
+	t.Run("partial reload requesting it includes initial-only data", func(t *testing.T) {
+		page, err := i.RenderOnly("Users/Index", nil, "/users", []string{"menu"})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"Home", "Users"}, page.Props["menu"])
+	})
+
+	t.Run("ShareAlways restores the default", func(t *testing.T) {
+		i.SetSharePolicy("menu", inertia.ShareAlways)
+		page, err := i.RenderOnly("Users/Index", nil, "/users", []string{"users"})
+		require.NoError(t, err)
+
+		assert.Contains(t, page.Props, "menu")
+	})
+}
+
 func TestInertia_Version(t *testing.T) {
 	config := inertia.Config{
 		RootView: "app.html",
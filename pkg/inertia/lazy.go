@@ -1,9 +1,42 @@
 package inertia
 
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// propErrorsKey is the prop holding errors from failed lazy prop evaluators.
+const propErrorsKey = "propErrors"
+
+// errPropFailed is reported to the client when a lazy prop evaluator fails or panics.
+var errPropFailed = errors.New("failed to evaluate prop")
+
 // LazyProp represents a lazily-evaluated property.
 type LazyProp struct {
-	Evaluator func() interface{}
-	Group     string // "lazy", "always", or "defer"
+	Evaluator        func() interface{}
+	ContextEvaluator func(context.Context) interface{} // Receives the request context; takes precedence over Evaluator
+	ErrorEvaluator   func() (interface{}, error)       // May fail; takes precedence over Evaluator
+	Group            string                            // "lazy", "always", or "defer"
+}
+
+// evaluate runs the prop's evaluator, converting panics into errors.
+func (lp LazyProp) evaluate(ctx context.Context) (value interface{}, err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			value = nil
+			err = fmt.Errorf("%w: %v", errPropFailed, rec)
+		}
+	}()
+
+	switch {
+	case lp.ContextEvaluator != nil:
+		return lp.ContextEvaluator(ctx), nil
+	case lp.ErrorEvaluator != nil:
+		return lp.ErrorEvaluator()
+	default:
+		return lp.Evaluator(), nil
+	}
 }
 
 // Lazy adds a lazily-evaluated prop that is excluded from partial reloads
@@ -20,6 +53,36 @@ func (ic *InertiaContext) Lazy(key string, fn func() interface{}) *InertiaContex
 	return ic
 }
 
+// LazyCtx is like Lazy, but the evaluator receives the request context so it can
+// stop work when the client disconnects.
+func (ic *InertiaContext) LazyCtx(key string, fn func(context.Context) interface{}) *InertiaContext {
+	if ic.ctx.Get("_inertia_lazy_props") == nil {
+		ic.ctx.Set("_inertia_lazy_props", make(map[string]LazyProp))
+	}
+	lazyProps := ic.ctx.Get("_inertia_lazy_props").(map[string]LazyProp)
+	lazyProps[key] = LazyProp{
+		ContextEvaluator: fn,
+		Group:            "lazy",
+	}
+	return ic
+}
+
+// LazyE is like Lazy, but the evaluator may fail. A failing (or panicking) evaluator
+// doesn't abort the render: the prop is omitted, the error is logged, and a generic
+// message is reported in the propErrors prop, keyed by prop name, so the client can
+// show an error for just that section without seeing the error's details.
+func (ic *InertiaContext) LazyE(key string, fn func() (interface{}, error)) *InertiaContext {
+	if ic.ctx.Get("_inertia_lazy_props") == nil {
+		ic.ctx.Set("_inertia_lazy_props", make(map[string]LazyProp))
+	}
+	lazyProps := ic.ctx.Get("_inertia_lazy_props").(map[string]LazyProp)
+	lazyProps[key] = LazyProp{
+		ErrorEvaluator: fn,
+		Group:          "lazy",
+	}
+	return ic
+}
+
 // Always adds a prop that is always included, even in partial reloads.
 func (ic *InertiaContext) Always(key string, value interface{}) *InertiaContext {
 	if ic.ctx.Get("_inertia_always_props") == nil {
@@ -57,21 +120,63 @@ func (ic *InertiaContext) Defer(key string, fn func() interface{}) *InertiaConte
 	return ic
 }
 
-// evaluateLazyProps evaluates lazy props based on the request type.
-func (ic *InertiaContext) evaluateLazyProps(props map[string]interface{}, only []string) {
-	ic.mergeAlwaysProps(props)
+// DeferCtx is like Defer, but the evaluator receives the request context so it can
+// stop work when the client disconnects.
+func (ic *InertiaContext) DeferCtx(key string, fn func(context.Context) interface{}) *InertiaContext {
+	if ic.ctx.Get("_inertia_lazy_props") == nil {
+		ic.ctx.Set("_inertia_lazy_props", make(map[string]LazyProp))
+	}
+	lazyProps := ic.ctx.Get("_inertia_lazy_props").(map[string]LazyProp)
+	lazyProps[key] = LazyProp{
+		ContextEvaluator: fn,
+		Group:            "defer",
+	}
+	return ic
+}
 
+// DeferE is like Defer, but the evaluator may fail. Errors are reported as with LazyE.
+func (ic *InertiaContext) DeferE(key string, fn func() (interface{}, error)) *InertiaContext {
+	if ic.ctx.Get("_inertia_lazy_props") == nil {
+		ic.ctx.Set("_inertia_lazy_props", make(map[string]LazyProp))
+	}
+	lazyProps := ic.ctx.Get("_inertia_lazy_props").(map[string]LazyProp)
+	lazyProps[key] = LazyProp{
+		ErrorEvaluator: fn,
+		Group:          "defer",
+	}
+	return ic
+}
+
+// evaluateLazyProps evaluates lazy props based on the request type.
+// Evaluation stops and the context error is returned once ctx is done.
+//
+// On partial reloads the requested props are selected first and except is applied
+// last: a prop listed in both only and except is excluded, unless it is an
+// "always" prop, which is included regardless of either list. A partial reload
+// with except but no only list selects every prop not listed in except.
+func (ic *InertiaContext) evaluateLazyProps(
+	ctx context.Context,
+	props map[string]interface{},
+	only, except []string,
+) error {
 	lazyProps := ic.getLazyPropsFromContext()
 	if lazyProps == nil {
-		return
+		return ctx.Err()
 	}
 
-	isPartial := len(only) > 0
+	isPartial := len(only) > 0 || len(except) > 0
 	for key, lazyProp := range lazyProps {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if lazyProp.Group != "always" && ic.isKeyRequested(key, except) {
+			continue
+		}
 		if ic.shouldEvaluateLazyProp(key, lazyProp, isPartial, only) {
-			ic.evaluatePropIfNotExists(props, key, lazyProp)
+			ic.evaluatePropIfNotExists(ctx, props, key, lazyProp)
 		}
 	}
+	return ctx.Err()
 }
 
 // getLazyPropsFromContext retrieves lazy props from the context.
@@ -92,9 +197,7 @@ func (ic *InertiaContext) mergeAlwaysProps(props map[string]interface{}) {
 
 	alwaysProps := alwaysPropsInterface.(map[string]interface{})
 	for key, value := range alwaysProps {
-		if _, exists := props[key]; !exists {
-			props[key] = value
-		}
+		setDefault(props, key, value)
 	}
 }
 
@@ -119,7 +222,7 @@ func (ic *InertiaContext) shouldEvaluateLazyProp(
 
 // shouldEvaluateLazyGroup determines if a "lazy" group prop should be evaluated.
 func (ic *InertiaContext) shouldEvaluateLazyGroup(key string, isPartial bool, only []string) bool {
-	if !isPartial {
+	if !isPartial || len(only) == 0 {
 		return true
 	}
 	return ic.isKeyRequested(key, only)
@@ -130,6 +233,10 @@ func (ic *InertiaContext) shouldEvaluateDeferGroup(key string, isPartial bool, o
 	if !isPartial {
 		return false
 	}
+	if len(only) == 0 {
+		// Except-only partial reloads request everything that is not excluded
+		return true
+	}
 	return ic.isKeyRequested(key, only)
 }
 
@@ -143,13 +250,50 @@ func (ic *InertiaContext) isKeyRequested(key string, only []string) bool {
 	return false
 }
 
-// evaluatePropIfNotExists evaluates a lazy prop if it doesn't already exist.
+// excludeProps removes the props listed in except, keeping "always" props.
+func (ic *InertiaContext) excludeProps(props map[string]interface{}, except []string) {
+	for _, key := range except {
+		if !ic.isAlwaysProp(key) {
+			delete(props, key)
+		}
+	}
+}
+
+// isAlwaysProp reports whether key was added with Always or AlwaysLazy.
+func (ic *InertiaContext) isAlwaysProp(key string) bool {
+	if alwaysProps, ok := ic.ctx.Get("_inertia_always_props").(map[string]interface{}); ok {
+		if _, exists := alwaysProps[key]; exists {
+			return true
+		}
+	}
+	lazyProp, exists := ic.getLazyPropsFromContext()[key]
+	return exists && lazyProp.Group == "always"
+}
+
+// evaluatePropIfNotExists evaluates a lazy prop if it doesn't already exist. When
+// the handler or shared data already set the key, the evaluator is never invoked,
+// so expensive work is not wasted on a value that would be discarded.
+// Evaluation errors are logged and a generic message is recorded for the
+// propErrors prop instead of the value.
 func (ic *InertiaContext) evaluatePropIfNotExists(
+	ctx context.Context,
 	props map[string]interface{},
 	key string,
 	lazyProp LazyProp,
 ) {
-	if _, exists := props[key]; !exists {
-		props[key] = lazyProp.Evaluator()
+	if _, exists := props[key]; exists {
+		return
+	}
+
+	value, err := lazyProp.evaluate(ctx)
+	if err != nil {
+		ic.mgr.logf("inertia: failed to evaluate prop %q: %v", key, err)
+		if ic.propErrors == nil {
+			ic.propErrors = make(map[string]string)
+		}
+		// Errors and panic values may contain internals; only the generic message is sent
+		ic.propErrors[key] = errPropFailed.Error()
+		return
 	}
+	props[key] = value
 }
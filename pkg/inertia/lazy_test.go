@@ -1,8 +1,12 @@
 package inertia_test
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -213,3 +217,289 @@ func TestDefer(t *testing.T) {
 		assert.True(t, called, "deferred prop should be evaluated when requested")
 	})
 }
+
+func TestContextAwareLazyProps(t *testing.T) {
+	config := inertia.Config{
+		RootView: "app.html",
+		Version:  "1.0.0",
+	}
+
+	mgr, err := inertia.New(config)
+	require.NoError(t, err)
+
+	t.Run("evaluators receive the request context", func(t *testing.T) {
+		type ctxKey struct{}
+		req := httptest.NewRequest("GET", "/reports", http.NoBody)
+		req = req.WithContext(context.WithValue(req.Context(), ctxKey{}, "tenant-1"))
+		req.Header.Set("X-Inertia", "true")
+		w := httptest.NewRecorder()
+		ic := inertia.NewContext(NewMockContext(w, req), mgr)
+
+		ic.LazyCtx("tenant", func(ctx context.Context) interface{} {
+			return ctx.Value(ctxKey{})
+		})
+
+		require.NoError(t, ic.Render("Reports/Index", map[string]interface{}{}))
+		assert.Contains(t, w.Body.String(), "tenant-1")
+	})
+
+	t.Run("cancelled request skips evaluators", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		req := httptest.NewRequest("GET", "/reports", http.NoBody).WithContext(ctx)
+		req.Header.Set("X-Inertia", "true")
+		w := httptest.NewRecorder()
+		ic := inertia.NewContext(NewMockContext(w, req), mgr)
+
+		called := false
+		ic.LazyCtx("report", func(context.Context) interface{} {
+			called = true
+			return "expensive"
+		})
+		ic.Lazy("summary", func() interface{} {
+			called = true
+			return "expensive"
+		})
+
+		err := ic.Render("Reports/Index", map[string]interface{}{})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.False(t, called)
+		assert.Empty(t, w.Body.String())
+	})
+}
+
+func TestFallibleLazyProps(t *testing.T) {
+	config := inertia.Config{
+		RootView: "app.html",
+		Version:  "1.0.0",
+	}
+
+	mgr, err := inertia.New(config)
+	require.NoError(t, err)
+	logger := &recordingLogger{}
+	mgr.SetLogger(logger)
+
+	req := httptest.NewRequest("GET", "/dashboard", http.NoBody)
+	req.Header.Set("X-Inertia", "true")
+	req.Header.Set("X-Inertia-Partial-Data", "stats,revenue,activity")
+	req.Header.Set("X-Inertia-Partial-Component", "Dashboard")
+
+	var capturedReq *http.Request
+	handler := mgr.Middleware()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		capturedReq = r
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	w := httptest.NewRecorder()
+	ic := inertia.NewContext(NewMockContext(w, capturedReq), mgr)
+
+	ic.DeferE("stats", func() (interface{}, error) {
+		return map[string]int{"users": 10}, nil
+	})
+	ic.DeferE("revenue", func() (interface{}, error) {
+		return nil, errors.New("billing service unavailable")
+	})
+	ic.Defer("activity", func() interface{} {
+		var feed map[string][]string
+		feed["items"] = nil // panics: assignment to nil map
+		return feed
+	})
+
+	require.NoError(t, ic.Render("Dashboard", map[string]interface{}{}))
+
+	var page inertia.Page
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+
+	assert.Equal(t, map[string]interface{}{"users": float64(10)}, page.Props["stats"])
+	assert.NotContains(t, page.Props, "revenue")
+	assert.NotContains(t, page.Props, "activity")
+	assert.Equal(t, map[string]interface{}{
+		"revenue":  "failed to evaluate prop",
+		"activity": "failed to evaluate prop",
+	}, page.Props["propErrors"])
+	assert.Contains(t, strings.Join(logger.messages, "\n"), "billing service unavailable")
+}
+
+// TestPartialExcept tests the precedence of X-Inertia-Partial-Except over
+// requested and lazily-evaluated props.
+func TestPartialExcept(t *testing.T) {
+	mgr, err := inertia.New(inertia.Config{
+		RootView: "app.html",
+		Version:  "1.0.0",
+	})
+	require.NoError(t, err)
+
+	render := func(t *testing.T, headers map[string]string) (map[string]interface{}, map[string]bool) {
+		t.Helper()
+
+		req := httptest.NewRequest("GET", "/posts/1", http.NoBody)
+		req.Header.Set("X-Inertia", "true")
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+
+		evaluated := make(map[string]bool)
+		evaluator := func(key string) func() interface{} {
+			return func() interface{} {
+				evaluated[key] = true
+				return key + " value"
+			}
+		}
+
+		w := httptest.NewRecorder()
+		mgr.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ic := inertia.NewContext(NewMockContext(w, r), mgr)
+			ic.Defer("comments", evaluator("comments")).
+				Lazy("stats", evaluator("stats")).
+				AlwaysLazy("notifications", evaluator("notifications")).
+				Always("auth", "alice")
+			require.NoError(t, ic.Render("Posts/Show", map[string]interface{}{
+				"title": "Hello",
+				"body":  "World",
+			}))
+		})).ServeHTTP(w, req)
+
+		var page inertia.Page
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		return page.Props, evaluated
+	}
+
+	t.Run("except wins over a requested deferred prop", func(t *testing.T) {
+		props, evaluated := render(t, map[string]string{
+			"X-Inertia-Partial-Component": "Posts/Show",
+			"X-Inertia-Partial-Data":      "comments,title",
+			"X-Inertia-Partial-Except":    "comments",
+		})
+
+		assert.NotContains(t, props, "comments")
+		assert.False(t, evaluated["comments"], "excluded deferred prop should not be evaluated")
+		assert.Equal(t, "Hello", props["title"])
+		assert.NotContains(t, props, "body")
+	})
+
+	t.Run("except wins over a requested lazy prop", func(t *testing.T) {
+		props, evaluated := render(t, map[string]string{
+			"X-Inertia-Partial-Component": "Posts/Show",
+			"X-Inertia-Partial-Data":      "stats",
+			"X-Inertia-Partial-Except":    "stats",
+		})
+
+		assert.NotContains(t, props, "stats")
+		assert.False(t, evaluated["stats"])
+	})
+
+	t.Run("always props ignore except", func(t *testing.T) {
+		props, evaluated := render(t, map[string]string{
+			"X-Inertia-Partial-Component": "Posts/Show",
+			"X-Inertia-Partial-Data":      "title",
+			"X-Inertia-Partial-Except":    "auth, notifications",
+		})
+
+		assert.Equal(t, "alice", props["auth"])
+		assert.Equal(t, "notifications value", props["notifications"])
+		assert.True(t, evaluated["notifications"])
+		assert.Equal(t, "Hello", props["title"])
+	})
+
+	t.Run("except without only", func(t *testing.T) {
+		props, evaluated := render(t, map[string]string{
+			"X-Inertia-Partial-Component": "Posts/Show",
+			"X-Inertia-Partial-Except":    "body",
+		})
+
+		assert.Equal(t, "Hello", props["title"])
+		assert.NotContains(t, props, "body")
+		assert.Equal(t, "alice", props["auth"])
+		assert.Contains(t, props, "notifications")
+		assert.Equal(t, "stats value", props["stats"], "props not listed in except are included")
+		assert.Equal(t, "comments value", props["comments"])
+		assert.True(t, evaluated["stats"])
+		assert.True(t, evaluated["comments"])
+	})
+
+	t.Run("except without only excludes listed lazy props", func(t *testing.T) {
+		props, evaluated := render(t, map[string]string{
+			"X-Inertia-Partial-Component": "Posts/Show",
+			"X-Inertia-Partial-Except":    "stats,comments",
+		})
+
+		assert.Equal(t, "Hello", props["title"])
+		assert.Equal(t, "World", props["body"])
+		assert.NotContains(t, props, "stats")
+		assert.NotContains(t, props, "comments")
+		assert.False(t, evaluated["stats"])
+		assert.False(t, evaluated["comments"])
+	})
+
+	t.Run("except for another component is ignored", func(t *testing.T) {
+		props, evaluated := render(t, map[string]string{
+			"X-Inertia-Partial-Component": "Posts/Index",
+			"X-Inertia-Partial-Except":    "title,stats",
+		})
+
+		assert.Equal(t, "Hello", props["title"])
+		assert.Equal(t, "stats value", props["stats"])
+		assert.True(t, evaluated["stats"])
+		assert.False(t, evaluated["comments"])
+	})
+}
+
+// TestLazyPropsNotEvaluatedWhenKeyExists tests that evaluators are skipped for
+// keys already set by the handler.
+func TestLazyPropsNotEvaluatedWhenKeyExists(t *testing.T) {
+	mgr, err := inertia.New(inertia.Config{
+		RootView: "app.html",
+		Version:  "1.0.0",
+	})
+	require.NoError(t, err)
+
+	calls := 0
+	expensive := func() interface{} {
+		calls++
+		return "computed"
+	}
+
+	tests := []struct {
+		name    string
+		partial string
+		setup   func(ic *inertia.InertiaContext)
+	}{
+		{name: "lazy", setup: func(ic *inertia.InertiaContext) { ic.Lazy("report", expensive) }},
+		{name: "always lazy", setup: func(ic *inertia.InertiaContext) { ic.AlwaysLazy("report", expensive) }},
+		{name: "requested defer", partial: "report", setup: func(ic *inertia.InertiaContext) { ic.Defer("report", expensive) }},
+		{name: "lazy with context", setup: func(ic *inertia.InertiaContext) {
+			ic.LazyCtx("report", func(context.Context) interface{} { return expensive() })
+		}},
+		{name: "fallible lazy", setup: func(ic *inertia.InertiaContext) {
+			ic.LazyE("report", func() (interface{}, error) { return expensive(), nil })
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = 0
+
+			req := httptest.NewRequest("GET", "/reports", http.NoBody)
+			req.Header.Set("X-Inertia", "true")
+			if tt.partial != "" {
+				req.Header.Set("X-Inertia-Partial-Data", tt.partial)
+				req.Header.Set("X-Inertia-Partial-Component", "Reports/Show")
+			}
+
+			w := httptest.NewRecorder()
+			mgr.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ic := inertia.NewContext(NewMockContext(w, r), mgr)
+				tt.setup(ic)
+				require.NoError(t, ic.Render("Reports/Show", map[string]interface{}{
+					"report": "from handler",
+				}))
+			})).ServeHTTP(w, req)
+
+			var page inertia.Page
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+			assert.Equal(t, "from handler", page.Props["report"])
+			assert.Zero(t, calls, "evaluator should not run when the key is already set")
+		})
+	}
+}
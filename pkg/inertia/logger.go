@@ -0,0 +1,19 @@
+package inertia
+
+// Logger receives diagnostic messages from Inertia. *log.Logger satisfies it.
+type Logger interface {
+	Printf(format string, args ...interface{})
+}
+
+// SetLogger sets the logger used for warnings such as shadowed shared props.
+// Logging is disabled when no logger is set.
+func (i *Inertia) SetLogger(logger Logger) {
+	i.logger = logger
+}
+
+// logf writes a message to the configured logger, if any.
+func (i *Inertia) logf(format string, args ...interface{}) {
+	if i.logger != nil {
+		i.logger.Printf(format, args...)
+	}
+}
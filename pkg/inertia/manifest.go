@@ -0,0 +1,124 @@
+package inertia
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// manifestChunk is an entry of a Vite build manifest.
+type manifestChunk struct {
+	File    string   `json:"file"`
+	Imports []string `json:"imports"`
+}
+
+// componentManifest resolves page components to the chunks they load.
+type componentManifest struct {
+	chunks map[string]manifestChunk
+	keys   []string // manifest keys without extension, sorted for deterministic lookups
+
+	mu       sync.RWMutex
+	preloads map[string][]string // cached preload files per component
+}
+
+// SetComponentManifest loads a Vite build manifest (manifest.json) so full page
+// loads emit <link rel="modulepreload"> tags for the rendered component's chunk
+// and the chunks it imports. Components are matched against manifest entries by
+// path suffix, so "Users/Index" matches "resources/js/Pages/Users/Index.vue".
+// Preload URLs are prefixed with Config.AssetURL.
+func (i *Inertia) SetComponentManifest(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("inertia: failed to read manifest: %w", err)
+	}
+
+	var chunks map[string]manifestChunk
+	if err := json.Unmarshal(data, &chunks); err != nil {
+		return fmt.Errorf("inertia: failed to parse manifest %q: %w", path, err)
+	}
+
+	m := &componentManifest{
+		chunks:   chunks,
+		preloads: make(map[string][]string),
+	}
+	for key := range chunks {
+		m.keys = append(m.keys, key)
+	}
+	sort.Strings(m.keys)
+
+	i.manifest = m
+	return nil
+}
+
+// preloadFiles returns the chunk files to preload for component: its own chunk
+// followed by its transitive static imports.
+func (m *componentManifest) preloadFiles(component string) []string {
+	m.mu.RLock()
+	files, ok := m.preloads[component]
+	m.mu.RUnlock()
+	if ok {
+		return files
+	}
+
+	if key, found := m.lookup(component); found {
+		seen := make(map[string]bool)
+		m.collect(key, seen, &files)
+	}
+
+	m.mu.Lock()
+	m.preloads[component] = files
+	m.mu.Unlock()
+
+	return files
+}
+
+// lookup finds the manifest key of the component's source file.
+func (m *componentManifest) lookup(component string) (string, bool) {
+	for _, key := range m.keys {
+		name := strings.TrimSuffix(key, path.Ext(key))
+		if name == component || strings.HasSuffix(name, "/"+component) {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// collect appends the file of the chunk under key and of the chunks it imports.
+func (m *componentManifest) collect(key string, seen map[string]bool, files *[]string) {
+	if seen[key] {
+		return
+	}
+	seen[key] = true
+
+	chunk, ok := m.chunks[key]
+	if !ok {
+		return
+	}
+	if chunk.File != "" {
+		*files = append(*files, chunk.File)
+	}
+	for _, imported := range chunk.Imports {
+		m.collect(imported, seen, files)
+	}
+}
+
+// preloadTags returns modulepreload link tags for the component's chunks.
+func (i *Inertia) preloadTags(component string) []string {
+	if i.manifest == nil {
+		return nil
+	}
+
+	base := strings.TrimSuffix(i.config.AssetURL, "/")
+	files := i.manifest.preloadFiles(component)
+	tags := make([]string, 0, len(files))
+	for _, file := range files {
+		href := base + "/" + strings.TrimPrefix(file, "/")
+		tags = append(tags, fmt.Sprintf(`<link rel="modulepreload" href="%s">`, html.EscapeString(href)))
+	}
+	return tags
+}
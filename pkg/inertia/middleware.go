@@ -13,9 +13,18 @@ const (
 	contextKeyInertia          contextKey = "inertia"
 	contextKeyPartialOnly      contextKey = "partial_only"
 	contextKeyPartialComponent contextKey = "partial_component"
+	contextKeyPartialExcept    contextKey = "partial_except"
+	contextKeyErrorBag         contextKey = "error_bag"
 	contextKeyExternalRedirect contextKey = "external_redirect"
+	contextKeyContext          contextKey = "context"
 )
 
+// Middleware returns i.Middleware(), for routers registered with a function
+// call such as router.Use(inertia.Middleware(mgr)).
+func Middleware(i *Inertia) func(http.Handler) http.Handler {
+	return i.Middleware()
+}
+
 // Middleware returns an HTTP middleware that handles Inertia requests.
 //
 //nolint:gocognit // Middleware complexity is acceptable given the protocol requirements.
@@ -23,10 +32,11 @@ func (i *Inertia) Middleware() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// Always set version header
-			w.Header().Set("X-Inertia-Version", i.version)
+			version := i.Version()
+			w.Header().Set("X-Inertia-Version", version)
 
 			// Check if this is an Inertia request
-			isInertia := IsInertiaRequest(r)
+			isInertia := i.isInertiaRequest(r)
 
 			if isInertia {
 				// Store Inertia flag in context
@@ -34,7 +44,7 @@ func (i *Inertia) Middleware() func(http.Handler) http.Handler {
 
 				// Check version match
 				clientVersion := r.Header.Get("X-Inertia-Version")
-				if clientVersion != "" && clientVersion != i.version {
+				if clientVersion != "" && !i.versionMatches(clientVersion, version) {
 					// Version mismatch - force reload
 					w.WriteHeader(http.StatusConflict)
 					return
@@ -42,22 +52,34 @@ func (i *Inertia) Middleware() func(http.Handler) http.Handler {
 
 				// Handle partial reloads
 				if partialData := r.Header.Get("X-Inertia-Partial-Data"); partialData != "" {
-					only := strings.Split(partialData, ",")
-					for i := range only {
-						only[i] = strings.TrimSpace(only[i])
-					}
-					ctx = context.WithValue(ctx, contextKeyPartialOnly, only)
+					ctx = context.WithValue(ctx, contextKeyPartialOnly, splitPropList(partialData))
+				}
+
+				if partialExcept := r.Header.Get("X-Inertia-Partial-Except"); partialExcept != "" {
+					ctx = context.WithValue(ctx, contextKeyPartialExcept, splitPropList(partialExcept))
 				}
 
 				if partialComponent := r.Header.Get("X-Inertia-Partial-Component"); partialComponent != "" {
 					ctx = context.WithValue(ctx, contextKeyPartialComponent, partialComponent)
 				}
 
+				// Scope validation errors to the submitting form
+				if errorBag := r.Header.Get("X-Inertia-Error-Bag"); errorBag != "" {
+					ctx = context.WithValue(ctx, contextKeyErrorBag, errorBag)
+				}
+
 				r = r.WithContext(ctx)
 			}
 
 			// Wrap response writer to intercept status code
-			wrapped := &responseWriter{ResponseWriter: w, request: r}
+			// HEAD responses carry the headers of a GET without its body
+			wrapped := &responseWriter{ResponseWriter: w, request: r, discardBody: r.Method == http.MethodHead}
+
+			// Create the InertiaContext of this request, see FromRequest
+			hc := newHTTPContext(wrapped, r)
+			r = r.WithContext(context.WithValue(r.Context(), contextKeyContext, NewContext(hc, i)))
+			hc.r = r
+			wrapped.request = r
 
 			// Call next handler
 			next.ServeHTTP(wrapped, r)
@@ -74,11 +96,68 @@ func (i *Inertia) Middleware() func(http.Handler) http.Handler {
 	}
 }
 
+// FromRequest returns the InertiaContext that Middleware created for r, or nil
+// if r did not pass through Middleware. Every call within a request returns the
+// same instance, so data set on it (e.g. with Share or WithErrors) is seen by
+// later handlers. The context renders to the response writer Middleware passed
+// on and follows r, so middleware that derives a new request (e.g. with
+// WithRouteName) is taken into account.
+//
+//	func showUser(w http.ResponseWriter, r *http.Request) {
+//		_ = inertia.FromRequest(r).Render("Users/Show", inertia.Props{"user": user})
+//	}
+func FromRequest(r *http.Request) *InertiaContext {
+	ic, ok := r.Context().Value(contextKeyContext).(*InertiaContext)
+	if !ok {
+		return nil
+	}
+	if hc, ok := ic.ctx.(*httpContext); ok {
+		hc.r = r
+	}
+	return ic
+}
+
+// contextFor returns the InertiaContext Middleware created for r, rendering to
+// w, or a new context for w and r if r did not pass through Middleware.
+func (i *Inertia) contextFor(w http.ResponseWriter, r *http.Request) *InertiaContext {
+	if ic := FromRequest(r); ic != nil {
+		if hc, ok := ic.ctx.(*httpContext); ok {
+			hc.w = w
+		}
+		return ic
+	}
+	return NewContext(newHTTPContext(w, r), i)
+}
+
+// contextKeyRouterContext is the ContextInterface key caching the InertiaContext
+// returned by GetContext.
+const contextKeyRouterContext = "_inertia_context"
+
+// GetContext returns the InertiaContext that Middleware created for the request
+// of a router context, like FromRequest, or nil if the request did not pass
+// through Middleware. The result is cached on ctx.
+//
+//	router.Get("/chat", func(ctx cosan.Context) error {
+//		return inertia.GetContext(ctx).Render("Chat", inertia.Props{"messages": messages})
+//	})
+func GetContext(ctx ContextInterface) *InertiaContext {
+	if ic, ok := ctx.Get(contextKeyRouterContext).(*InertiaContext); ok {
+		return ic
+	}
+
+	ic := FromRequest(ctx.Request())
+	if ic != nil {
+		ctx.Set(contextKeyRouterContext, ic)
+	}
+	return ic
+}
+
 // responseWriter wraps http.ResponseWriter to track if response was written.
 type responseWriter struct {
 	http.ResponseWriter
-	request *http.Request
-	written bool
+	request     *http.Request
+	written     bool
+	discardBody bool
 }
 
 func (w *responseWriter) WriteHeader(statusCode int) {
@@ -88,15 +167,37 @@ func (w *responseWriter) WriteHeader(statusCode int) {
 
 func (w *responseWriter) Write(b []byte) (int, error) {
 	w.written = true
+	if w.discardBody {
+		return len(b), nil
+	}
 	return w.ResponseWriter.Write(b)
 }
 
+// Flush sends buffered data to the client, if the wrapped writer supports it.
+func (w *responseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
 // IsInertiaRequest checks if the request is an Inertia request.
 func IsInertiaRequest(r *http.Request) bool {
 	value := r.Header.Get("X-Inertia")
 	return strings.EqualFold(value, "true")
 }
 
+// IsXHR checks if the request was sent with X-Requested-With: XMLHttpRequest,
+// as set by jQuery and many AJAX libraries.
+func IsXHR(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("X-Requested-With"), "XMLHttpRequest")
+}
+
+// isInertiaRequest checks if the request should be handled as an Inertia request,
+// including XHR requests when Config.TreatXHRAsInertia is set.
+func (i *Inertia) isInertiaRequest(r *http.Request) bool {
+	return IsInertiaRequest(r) || (i.config.TreatXHRAsInertia && IsXHR(r))
+}
+
 // GetPartialOnly returns the list of props to include in partial reload.
 func GetPartialOnly(r *http.Request) []string {
 	if only, ok := r.Context().Value(contextKeyPartialOnly).([]string); ok {
@@ -105,6 +206,23 @@ func GetPartialOnly(r *http.Request) []string {
 	return nil
 }
 
+// GetPartialExcept returns the list of props to exclude from a partial reload.
+func GetPartialExcept(r *http.Request) []string {
+	if except, ok := r.Context().Value(contextKeyPartialExcept).([]string); ok {
+		return except
+	}
+	return nil
+}
+
+// splitPropList splits a comma-separated list of prop names from a partial reload header.
+func splitPropList(value string) []string {
+	keys := strings.Split(value, ",")
+	for i := range keys {
+		keys[i] = strings.TrimSpace(keys[i])
+	}
+	return keys
+}
+
 // GetPartialComponent returns the component name for partial reload.
 func GetPartialComponent(r *http.Request) string {
 	if component, ok := r.Context().Value(contextKeyPartialComponent).(string); ok {
@@ -113,6 +231,15 @@ func GetPartialComponent(r *http.Request) string {
 	return ""
 }
 
+// GetErrorBag returns the error bag of the form that sent the request, from the
+// X-Inertia-Error-Bag header, or "" if there is none.
+func GetErrorBag(r *http.Request) string {
+	if bag, ok := r.Context().Value(contextKeyErrorBag).(string); ok {
+		return bag
+	}
+	return ""
+}
+
 // SetExternalRedirect marks the request for external redirect.
 func SetExternalRedirect(r *http.Request, url string) {
 	ctx := context.WithValue(r.Context(), contextKeyExternalRedirect, url)
@@ -3,6 +3,7 @@ package inertia_test
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -233,3 +234,261 @@ func TestIsInertiaRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestMiddleware_VersionFunc(t *testing.T) {
+	current := "1.0.0"
+	config := inertia.Config{
+		RootView: "app.html",
+		Version:  "static",
+		VersionFunc: func() string {
+			return current
+		},
+	}
+
+	i, err := inertia.New(config)
+	require.NoError(t, err)
+
+	handler := i.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	serve := func(clientVersion string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/test", http.NoBody)
+		req.Header.Set("X-Inertia", "true")
+		req.Header.Set("X-Inertia-Version", clientVersion)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve("1.0.0")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "1.0.0", w.Header().Get("X-Inertia-Version"))
+	assert.Equal(t, "1.0.0", i.Version())
+
+	// Deploy a new build
+	current = "2.0.0"
+
+	w = serve("1.0.0")
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, "2.0.0", w.Header().Get("X-Inertia-Version"))
+
+	w = serve("2.0.0")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2.0.0", i.Version())
+}
+
+func TestMiddleware_VersionComparator(t *testing.T) {
+	// ignorePatch treats versions as compatible when major and minor match.
+	ignorePatch := func(client, server string) bool {
+		majorMinor := func(v string) string {
+			parts := strings.SplitN(v, ".", 3)
+			return strings.Join(parts[:min(len(parts), 2)], ".")
+		}
+		return majorMinor(client) == majorMinor(server)
+	}
+
+	tests := []struct {
+		name          string
+		comparator    func(client, server string) bool
+		clientVersion string
+		wantStatus    int
+	}{
+		{name: "default exact match", clientVersion: "1.0.2", wantStatus: http.StatusOK},
+		{name: "default mismatch", clientVersion: "1.0.1", wantStatus: http.StatusConflict},
+		{name: "custom ignores patch", comparator: ignorePatch, clientVersion: "1.0.1", wantStatus: http.StatusOK},
+		{name: "custom minor mismatch", comparator: ignorePatch, clientVersion: "1.1.2", wantStatus: http.StatusConflict},
+		{name: "custom major mismatch", comparator: ignorePatch, clientVersion: "2.0.2", wantStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i, err := inertia.New(inertia.Config{
+				RootView:          "app.html",
+				Version:           "1.0.2",
+				VersionComparator: tt.comparator,
+			})
+			require.NoError(t, err)
+
+			handler := i.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest("GET", "/test", http.NoBody)
+			req.Header.Set("X-Inertia", "true")
+			req.Header.Set("X-Inertia-Version", tt.clientVersion)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestIsXHR(t *testing.T) {
+	tests := []struct {
+		name        string
+		inertia     bool
+		xhr         bool
+		treatXHR    bool
+		wantXHR     bool
+		wantJSON    bool
+		wantInertia bool
+	}{
+		{name: "neither header", wantJSON: false},
+		{name: "X-Inertia only", inertia: true, wantJSON: true, wantInertia: true},
+		{name: "XHR only, strict", xhr: true, wantXHR: true, wantJSON: false},
+		{name: "XHR only, treated as Inertia", xhr: true, treatXHR: true, wantXHR: true, wantJSON: true},
+		{name: "both headers", inertia: true, xhr: true, wantXHR: true, wantJSON: true, wantInertia: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mgr, err := inertia.New(inertia.Config{
+				RootView:          writeRootTemplate(t, "app.html", testRootTemplate),
+				Version:           "1.0.0",
+				TreatXHRAsInertia: tt.treatXHR,
+			})
+			require.NoError(t, err)
+
+			req := newFullLoadRequest("/users")
+			if tt.inertia {
+				req.Header.Set("X-Inertia", "true")
+			}
+			if tt.xhr {
+				req.Header.Set("X-Requested-With", "XMLHttpRequest")
+			}
+
+			assert.Equal(t, tt.wantXHR, inertia.IsXHR(req))
+			assert.Equal(t, tt.wantInertia, inertia.IsInertiaRequest(req))
+
+			w := httptest.NewRecorder()
+			ic := inertia.NewContext(NewMockContext(w, req), mgr)
+			require.NoError(t, ic.Render("Users/Index", map[string]interface{}{}))
+
+			if tt.wantJSON {
+				assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			} else {
+				assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestMiddleware_HeadRequest(t *testing.T) {
+	mgr, err := inertia.New(inertia.Config{
+		RootView: writeRootTemplate(t, "app.html", testRootTemplate),
+		Version:  "1.0.0",
+	})
+	require.NoError(t, err)
+
+	lazyCalls := 0
+	handler := mgr.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ic := inertia.NewContext(NewMockContext(w, r), mgr)
+		ic.Lazy("stats", func() interface{} {
+			lazyCalls++
+			return "expensive"
+		})
+		require.NoError(t, ic.Render("Dashboard", map[string]interface{}{"title": "Home"}))
+	}))
+
+	serve := func(method string) *httptest.ResponseRecorder {
+		req := newFullLoadRequest("/dashboard")
+		req.Method = method
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	get := serve(http.MethodGet)
+	assert.Equal(t, 1, lazyCalls)
+	assert.Contains(t, get.Body.String(), "Dashboard")
+
+	head := serve(http.MethodHead)
+	assert.Equal(t, 1, lazyCalls, "HEAD should not evaluate lazy props")
+	assert.Equal(t, get.Code, head.Code)
+	assert.Equal(t, get.Header().Get("Content-Type"), head.Header().Get("Content-Type"))
+	assert.Equal(t, get.Header().Get("X-Inertia-Version"), head.Header().Get("X-Inertia-Version"))
+	assert.Equal(t, get.Header().Values("Vary"), head.Header().Values("Vary"))
+	assert.Empty(t, head.Body.String())
+}
+
+func TestFromRequest(t *testing.T) {
+	mgr, err := inertia.New(inertia.Config{
+		RootView: "app.html",
+		Version:  "1.0.0",
+	})
+	require.NoError(t, err)
+
+	var first, second *inertia.InertiaContext
+
+	// An inner middleware shares data and derives a new request
+	share := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			first = inertia.FromRequest(r)
+			first.Share("tenant", "acme")
+			next.ServeHTTP(w, inertia.WithRouteName(r, "users.index"))
+		})
+	}
+
+	handler := mgr.Middleware()(share(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		second = inertia.FromRequest(r)
+		require.NoError(t, second.Render("Users/Index", inertia.Props{}))
+	})))
+
+	req := httptest.NewRequest("GET", "/users", http.NoBody)
+	req.Header.Set("X-Inertia", "true")
+	req.Header.Set("X-Inertia-Version", "1.0.0")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	require.NotNil(t, first)
+	assert.Same(t, first, second)
+	assert.Contains(t, w.Body.String(), `"tenant":"acme"`)
+	assert.Contains(t, w.Body.String(), `"route":"users.index"`, "the context follows the derived request")
+
+	t.Run("without middleware", func(t *testing.T) {
+		assert.Nil(t, inertia.FromRequest(httptest.NewRequest("GET", "/", http.NoBody)))
+	})
+
+	t.Run("each request gets its own context", func(t *testing.T) {
+		previous := first
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		assert.NotSame(t, previous, first)
+		assert.Same(t, first, second)
+	})
+}
+
+func TestGetContext(t *testing.T) {
+	mgr, err := inertia.New(inertia.Config{
+		RootView: "app.html",
+		Version:  "1.0.0",
+	})
+	require.NoError(t, err)
+
+	var fromRequest, first, second *inertia.InertiaContext
+	handler := inertia.Middleware(mgr)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A router context wrapping the request, as routers pass to handlers
+		ctx := NewMockContext(w, r)
+		fromRequest = inertia.FromRequest(r)
+		first = inertia.GetContext(ctx)
+		second = inertia.GetContext(ctx)
+		require.NoError(t, first.Render("Chat", inertia.Props{"messages": []string{"hi"}}))
+	}))
+
+	req := httptest.NewRequest("GET", "/chat", http.NoBody)
+	req.Header.Set("X-Inertia", "true")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	require.NotNil(t, first)
+	assert.Same(t, fromRequest, first)
+	assert.Same(t, first, second)
+	assert.Equal(t, "1.0.0", w.Header().Get("X-Inertia-Version"))
+	assert.Contains(t, w.Body.String(), `"component":"Chat"`)
+
+	t.Run("without middleware", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/chat", http.NoBody)
+		assert.Nil(t, inertia.GetContext(NewMockContext(httptest.NewRecorder(), req)))
+	})
+}
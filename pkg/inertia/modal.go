@@ -0,0 +1,39 @@
+package inertia
+
+// modalPropKey is the prop describing the modal rendered by RenderModal.
+const modalPropKey = "_modal"
+
+// Modal describes the component rendered in a modal over the page. RenderModal
+// sends it as the "_modal" prop.
+type Modal struct {
+	Component string                 `json:"component"`
+	Props     map[string]interface{} `json:"props"`
+}
+
+// RenderModal renders modalComponent in a modal over baseComponent, e.g. a
+// create form over the list it belongs to. The page is rendered for
+// baseComponent with shared data, and props are passed to the modal in the
+// "_modal" prop, which the frontend's modal layer renders:
+//
+//	{"component": "Todos/Index", "props": {"_modal": {"component": "Todos/Create", "props": {...}}}}
+//
+// Props of the base component other than shared data can be added with Share.
+// Prop transformers run on the modal props as well as on the page props.
+func (ic *InertiaContext) RenderModal(baseComponent, modalComponent string, props Props) error {
+	if modalComponent == "" {
+		return ErrComponentRequired
+	}
+
+	modalProps := make(map[string]interface{}, len(props))
+	for key, value := range props {
+		modalProps[key] = value
+	}
+	resolveProps(modalProps)
+	if err := ic.mgr.transformProps(modalProps); err != nil {
+		return err
+	}
+
+	return ic.Render(baseComponent, map[string]interface{}{
+		modalPropKey: Modal{Component: modalComponent, Props: modalProps},
+	})
+}
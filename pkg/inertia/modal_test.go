@@ -0,0 +1,72 @@
+package inertia_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/toutaio/toutago-inertia/pkg/inertia"
+)
+
+func TestInertiaContext_RenderModal(t *testing.T) {
+	mgr, err := inertia.New(inertia.Config{
+		RootView: "app.html",
+		Version:  "1.0.0",
+	})
+	require.NoError(t, err)
+	mgr.Share("app_name", "Todos")
+
+	req := httptest.NewRequest("GET", "/todos/create", http.NoBody)
+	req.Header.Set("X-Inertia", "true")
+	w := httptest.NewRecorder()
+	ic := inertia.NewContext(NewMockContext(w, req), mgr)
+
+	err = ic.Share("todos", []string{"write docs"}).RenderModal("Todos/Index", "Todos/Create", inertia.Props{
+		"categories": []string{"work", "home"},
+		"visible":    inertia.When(true, "yes"),
+	})
+	require.NoError(t, err)
+
+	var page struct {
+		Component string                     `json:"component"`
+		URL       string                     `json:"url"`
+		Props     map[string]json.RawMessage `json:"props"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+
+	assert.Equal(t, "Todos/Index", page.Component)
+	assert.Equal(t, "/todos/create", page.URL)
+	assert.JSONEq(t, `"Todos"`, string(page.Props["app_name"]))
+	assert.JSONEq(t, `["write docs"]`, string(page.Props["todos"]))
+	assert.NotContains(t, page.Props, "categories", "modal props belong to the modal")
+	assert.JSONEq(t, `{
+		"component": "Todos/Create",
+		"props": {"categories": ["work", "home"], "visible": "yes"}
+	}`, string(page.Props["_modal"]))
+
+	t.Run("requires a modal component", func(t *testing.T) {
+		ic := inertia.NewContext(NewMockContext(httptest.NewRecorder(), req), mgr)
+		assert.ErrorIs(t, ic.RenderModal("Todos/Index", "", nil), inertia.ErrComponentRequired)
+	})
+}
+
+func TestInertiaContext_RenderModal_TransformsModalProps(t *testing.T) {
+	mgr, err := inertia.New(inertia.Config{RootView: "app.html", Version: "1.0.0"})
+	require.NoError(t, err)
+	mgr.AddPropTransformer(inertia.RedactFields("user.password_hash"))
+
+	req := httptest.NewRequest("GET", "/users/1/edit", http.NoBody)
+	req.Header.Set("X-Inertia", "true")
+	w := httptest.NewRecorder()
+	ic := inertia.NewContext(NewMockContext(w, req), mgr)
+
+	require.NoError(t, ic.RenderModal("Users/Index", "Users/Edit", inertia.Props{
+		"user": map[string]interface{}{"name": "Ada", "password_hash": "s2"},
+	}))
+
+	assert.NotContains(t, w.Body.String(), "s2")
+	assert.Contains(t, w.Body.String(), `"password_hash":"[REDACTED]"`)
+}
@@ -0,0 +1,75 @@
+package inertia
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"html/template"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// nonceKey is the prop under which the CSP nonce is exposed to the frontend.
+const nonceKey = "nonce"
+
+// Nonce sets the Content-Security-Policy nonce for this request. On full page loads
+// it is added as a nonce attribute to inline <script> tags in the head tags and
+// SSR output, made available to the root template as {{ .Nonce }}, and exposed to
+// the frontend as the "nonce" prop.
+func (ic *InertiaContext) Nonce(nonce string) *InertiaContext {
+	ic.nonce = nonce
+	return ic
+}
+
+// CSPNonce returns the nonce for this request. When none was set and
+// Config.GenerateNonce is enabled, a random nonce is generated and kept for the
+// rest of the request, so handlers can use it in their Content-Security-Policy
+// header before rendering.
+func (ic *InertiaContext) CSPNonce() string {
+	if ic.nonce == "" && ic.mgr.config.GenerateNonce {
+		ic.nonce = generateNonce()
+	}
+	return ic.nonce
+}
+
+// generateNonce returns a random nonce. URL-safe base64 avoids characters that
+// html/template would escape in attribute values.
+func generateNonce() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// mergeNonce exposes the nonce as a prop. Nonces are only generated for full page
+// loads, since Inertia navigations keep the policy of the initial document.
+func (ic *InertiaContext) mergeNonce(props map[string]interface{}, req *http.Request) {
+	nonce := ic.nonce
+	if nonce == "" && ic.mgr.wantsHTML(req) {
+		nonce = ic.CSPNonce()
+	}
+	if nonce == "" {
+		return
+	}
+
+	setDefault(props, nonceKey, nonce)
+}
+
+// scriptTag matches opening <script> tags.
+var scriptTag = regexp.MustCompile(`(?i)<script\b[^>]*>`)
+
+// applyNonce adds a nonce attribute to every <script> tag in html that lacks one.
+func applyNonce(html, nonce string) string {
+	if nonce == "" {
+		return html
+	}
+
+	attr := ` nonce="` + template.HTMLEscapeString(nonce) + `"`
+	return scriptTag.ReplaceAllStringFunc(html, func(tag string) string {
+		if strings.Contains(strings.ToLower(tag), "nonce=") {
+			return tag
+		}
+		return tag[:len("<script")] + attr + tag[len("<script"):]
+	})
+}
@@ -0,0 +1,161 @@
+package inertia
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// defaultPerPage is used when Paginate is called with a non-positive perPage.
+const defaultPerPage = 15
+
+// paginatorLinkWindow is the number of pages shown on each side of the current page
+// before the page links are collapsed with "...".
+const paginatorLinkWindow = 2
+
+// PaginatorLink is a single entry of a paginator's links, as rendered by
+// pagination components. URL is nil for disabled links and separators.
+type PaginatorLink struct {
+	URL    *string `json:"url"`
+	Label  string  `json:"label"`
+	Active bool    `json:"active"`
+}
+
+// Paginator is a page of items that serializes to the Laravel-style paginator
+// shape used by Inertia pagination components. It implements PropResolver, so it
+// can be passed directly as a prop.
+type Paginator[T any] struct {
+	Data         []T             `json:"data"`
+	CurrentPage  int             `json:"current_page"`
+	LastPage     int             `json:"last_page"`
+	PerPage      int             `json:"per_page"`
+	Total        int             `json:"total"`
+	From         int             `json:"from"`
+	To           int             `json:"to"`
+	Path         string          `json:"path"`
+	FirstPageURL string          `json:"first_page_url"`
+	LastPageURL  string          `json:"last_page_url"`
+	NextPageURL  *string         `json:"next_page_url"`
+	PrevPageURL  *string         `json:"prev_page_url"`
+	Links        []PaginatorLink `json:"links"`
+}
+
+// Paginate returns the given page (1-based) of items with perPage items per page.
+// Page URLs are relative ("?page=2") until a base URL is set with WithBaseURL.
+//
+//	ic.Render("Users/Index", map[string]interface{}{
+//		"users": inertia.Paginate(users, page, 20).WithBaseURL("/users"),
+//	})
+func Paginate[T any](items []T, page, perPage int) Paginator[T] {
+	if perPage <= 0 {
+		perPage = defaultPerPage
+	}
+	if page < 1 {
+		page = 1
+	}
+
+	total := len(items)
+	lastPage := (total + perPage - 1) / perPage
+	if lastPage < 1 {
+		lastPage = 1
+	}
+
+	p := Paginator[T]{
+		Data:        []T{},
+		CurrentPage: page,
+		LastPage:    lastPage,
+		PerPage:     perPage,
+		Total:       total,
+	}
+
+	start := (page - 1) * perPage
+	if start < total {
+		end := start + perPage
+		if end > total {
+			end = total
+		}
+		p.Data = items[start:end]
+		p.From = start + 1
+		p.To = end
+	}
+
+	p.buildURLs()
+	return p
+}
+
+// WithBaseURL sets the URL used to build page links. Existing query parameters
+// (e.g. filters) are preserved and the page parameter is added to them.
+func (p Paginator[T]) WithBaseURL(baseURL string) Paginator[T] {
+	p.Path = baseURL
+	p.buildURLs()
+	return p
+}
+
+// ResolveProp implements PropResolver.
+func (p Paginator[T]) ResolveProp() interface{} {
+	return p
+}
+
+// pageURL returns the URL of the given page.
+func (p *Paginator[T]) pageURL(page int) string {
+	u, err := url.Parse(p.Path)
+	if err != nil {
+		u = &url.URL{}
+	}
+	query := u.Query()
+	query.Set("page", strconv.Itoa(page))
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// buildURLs computes the page URLs and links.
+func (p *Paginator[T]) buildURLs() {
+	p.FirstPageURL = p.pageURL(1)
+	p.LastPageURL = p.pageURL(p.LastPage)
+	p.PrevPageURL = nil
+	p.NextPageURL = nil
+
+	if p.CurrentPage > 1 {
+		prev := p.pageURL(p.CurrentPage - 1)
+		p.PrevPageURL = &prev
+	}
+	if p.CurrentPage < p.LastPage {
+		next := p.pageURL(p.CurrentPage + 1)
+		p.NextPageURL = &next
+	}
+
+	links := []PaginatorLink{{URL: p.PrevPageURL, Label: "&laquo; Previous"}}
+	for _, page := range p.linkPages() {
+		if page == 0 {
+			links = append(links, PaginatorLink{Label: "..."})
+			continue
+		}
+		pageURL := p.pageURL(page)
+		links = append(links, PaginatorLink{
+			URL:    &pageURL,
+			Label:  strconv.Itoa(page),
+			Active: page == p.CurrentPage,
+		})
+	}
+	p.Links = append(links, PaginatorLink{URL: p.NextPageURL, Label: "Next &raquo;"})
+}
+
+// linkPages returns the page numbers to link to, with 0 marking a "..." gap.
+// The first two and last two pages are always shown, plus a window around the
+// current page.
+func (p *Paginator[T]) linkPages() []int {
+	var pages []int
+	last := 0
+	for page := 1; page <= p.LastPage; page++ {
+		nearEdge := page <= 2 || page > p.LastPage-2
+		nearCurrent := page >= p.CurrentPage-paginatorLinkWindow && page <= p.CurrentPage+paginatorLinkWindow
+		if !nearEdge && !nearCurrent {
+			continue
+		}
+		if last != 0 && page != last+1 {
+			pages = append(pages, 0)
+		}
+		pages = append(pages, page)
+		last = page
+	}
+	return pages
+}
@@ -0,0 +1,110 @@
+package inertia_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/toutaio/toutago-inertia/pkg/inertia"
+)
+
+// linkLabels returns the labels of a paginator's links.
+func linkLabels(links []inertia.PaginatorLink) []string {
+	labels := make([]string, 0, len(links))
+	for _, link := range links {
+		labels = append(labels, link.Label)
+	}
+	return labels
+}
+
+// activeLink returns the label of the active link.
+func activeLink(links []inertia.PaginatorLink) string {
+	for _, link := range links {
+		if link.Active {
+			return link.Label
+		}
+	}
+	return ""
+}
+
+func TestPaginate(t *testing.T) {
+	items := make([]int, 95)
+	for i := range items {
+		items[i] = i + 1
+	}
+
+	t.Run("first page", func(t *testing.T) {
+		p := inertia.Paginate(items, 1, 10).WithBaseURL("/users?role=admin")
+
+		assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, p.Data)
+		assert.Equal(t, 10, p.LastPage)
+		assert.Equal(t, 1, p.From)
+		assert.Equal(t, 10, p.To)
+		assert.Nil(t, p.PrevPageURL)
+		require.NotNil(t, p.NextPageURL)
+		assert.Equal(t, "/users?page=2&role=admin", *p.NextPageURL)
+
+		assert.Equal(t, []string{"&laquo; Previous", "1", "2", "3", "...", "9", "10", "Next &raquo;"}, linkLabels(p.Links))
+		assert.Equal(t, "1", activeLink(p.Links))
+		assert.Nil(t, p.Links[0].URL)
+	})
+
+	t.Run("middle page", func(t *testing.T) {
+		p := inertia.Paginate(items, 5, 10).WithBaseURL("/users")
+
+		assert.Equal(t, 41, p.From)
+		assert.Equal(t, 50, p.To)
+		require.NotNil(t, p.PrevPageURL)
+		assert.Equal(t, "/users?page=4", *p.PrevPageURL)
+		require.NotNil(t, p.NextPageURL)
+		assert.Equal(t, "/users?page=6", *p.NextPageURL)
+
+		assert.Equal(t, []string{"&laquo; Previous", "1", "2", "3", "4", "5", "6", "7", "...", "9", "10", "Next &raquo;"}, linkLabels(p.Links))
+		assert.Equal(t, "5", activeLink(p.Links))
+	})
+
+	t.Run("last page", func(t *testing.T) {
+		p := inertia.Paginate(items, 10, 10).WithBaseURL("/users")
+
+		assert.Equal(t, []int{91, 92, 93, 94, 95}, p.Data)
+		assert.Equal(t, 91, p.From)
+		assert.Equal(t, 95, p.To)
+		assert.Nil(t, p.NextPageURL)
+		assert.Equal(t, "/users?page=10", p.LastPageURL)
+		assert.Nil(t, p.Links[len(p.Links)-1].URL)
+		assert.Equal(t, "10", activeLink(p.Links))
+	})
+
+	t.Run("empty items", func(t *testing.T) {
+		p := inertia.Paginate([]string{}, 1, 10)
+
+		assert.Empty(t, p.Data)
+		assert.Equal(t, 1, p.LastPage)
+		assert.Equal(t, "?page=1", p.FirstPageURL)
+	})
+
+	t.Run("renders as a prop", func(t *testing.T) {
+		mgr, err := inertia.New(inertia.Config{RootView: "app.html", Version: "1.0.0"})
+		require.NoError(t, err)
+
+		page, err := mgr.Render("Users/Index", map[string]interface{}{
+			"users": inertia.Paginate(items, 2, 10).WithBaseURL("/users"),
+		}, "/users")
+		require.NoError(t, err)
+
+		data, err := json.Marshal(page)
+		require.NoError(t, err)
+
+		var decoded struct {
+			Props struct {
+				Users map[string]interface{} `json:"users"`
+			} `json:"props"`
+		}
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Equal(t, float64(2), decoded.Props.Users["current_page"])
+		assert.Equal(t, float64(95), decoded.Props.Users["total"])
+		assert.Equal(t, "/users?page=3", decoded.Props.Users["next_page_url"])
+		assert.Len(t, decoded.Props.Users["data"], 10)
+	})
+}
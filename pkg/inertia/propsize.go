@@ -0,0 +1,44 @@
+package inertia
+
+import (
+	"errors"
+	"fmt"
+)
+
+// checkPropSize reports props whose encoded size exceeds Config.MaxPropBytes,
+// naming the largest top-level prop. In strict mode (see SetStrictProps) the
+// render fails; otherwise the size is only logged.
+func (i *Inertia) checkPropSize(component string, props map[string]interface{}) error {
+	limit := i.config.MaxPropBytes
+	if limit <= 0 {
+		return nil
+	}
+
+	data, err := i.encodeJSON(props)
+	if err != nil {
+		return fmt.Errorf("inertia: failed to encode props: %w", err)
+	}
+	if len(data) <= limit {
+		return nil
+	}
+
+	largest, largestSize := "", 0
+	for key, value := range props {
+		encoded, err := i.encodeJSON(value)
+		if err != nil {
+			continue
+		}
+		if len(encoded) > largestSize || (len(encoded) == largestSize && key < largest) {
+			largest, largestSize = key, len(encoded)
+		}
+	}
+
+	msg := fmt.Sprintf("inertia: props of %s are %d bytes, over the %d byte limit; largest prop %q is %d bytes",
+		component, len(data), limit, largest, largestSize)
+	i.logf("%s", msg)
+
+	if i.strictProps {
+		return errors.New(msg)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package inertia
+
+import (
+	"net/http"
+	"runtime/debug"
+)
+
+// RecoverMiddleware returns a middleware that recovers panics in later handlers,
+// logs them with the configured logger and responds with the error component
+// (Config.ErrorComponent) and status 500, as page JSON for Inertia requests or
+// through the root template for full page loads.
+//
+// Place it outside Middleware so panics in Inertia handlers are caught:
+//
+//	handler := mgr.RecoverMiddleware()(mgr.Middleware()(mux))
+func (i *Inertia) RecoverMiddleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			wrapped := &responseWriter{ResponseWriter: w, request: r}
+
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+				if rec == http.ErrAbortHandler {
+					panic(rec)
+				}
+
+				i.logf("inertia: panic serving %s %s: %v\n%s", r.Method, r.URL.Path, rec, debug.Stack())
+
+				if wrapped.written {
+					// Part of the response is already sent; nothing more can be done
+					return
+				}
+
+				ic := i.contextFor(w, r)
+				if err := ic.Error(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError)); err != nil {
+					i.logf("inertia: failed to render error page: %v", err)
+					http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				}
+			}()
+
+			next.ServeHTTP(wrapped, r)
+		})
+	}
+}
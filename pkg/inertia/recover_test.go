@@ -0,0 +1,61 @@
+package inertia_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/toutaio/toutago-inertia/pkg/inertia"
+)
+
+func TestRecoverMiddleware(t *testing.T) {
+	logger := &recordingLogger{}
+	mgr, err := inertia.New(inertia.Config{
+		RootView:       writeRootTemplate(t, "app.html", testRootTemplate),
+		Version:        "1.0.0",
+		ErrorComponent: "Errors/ServerError",
+	})
+	require.NoError(t, err)
+	mgr.SetLogger(logger)
+
+	panicking := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ic := inertia.NewContext(NewMockContext(w, r), mgr)
+		var user *authUser
+		_ = ic.Render("Dashboard", map[string]interface{}{
+			"name": user.Name, // nil dereference
+		})
+	})
+	handler := mgr.RecoverMiddleware()(mgr.Middleware()(panicking))
+
+	t.Run("Inertia request receives an error page", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/dashboard", http.NoBody)
+		req.Header.Set("X-Inertia", "true")
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+		var page inertia.Page
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		assert.Equal(t, "Errors/ServerError", page.Component)
+		assert.Equal(t, float64(500), page.Props["status"])
+
+		require.NotEmpty(t, logger.messages)
+		assert.Contains(t, logger.messages[0], "panic serving GET /dashboard")
+	})
+
+	t.Run("full page load receives an HTML error page", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, newFullLoadRequest("/dashboard"))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Body.String(), `&#34;component&#34;:&#34;Errors/ServerError&#34;`)
+	})
+}
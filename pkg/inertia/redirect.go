@@ -2,6 +2,7 @@ package inertia
 
 import (
 	"net/http"
+	"net/url"
 )
 
 // ValidationErrors represents form validation errors.
@@ -12,7 +13,7 @@ type Flash map[string]string
 
 // Location performs an external redirect (409 for Inertia, 302 for browsers).
 func (i *Inertia) Location(w http.ResponseWriter, r *http.Request, url string) error {
-	if IsInertiaRequest(r) {
+	if i.isInertiaRequest(r) {
 		w.Header().Set("X-Inertia-Location", url)
 		w.WriteHeader(http.StatusConflict)
 		return nil
@@ -23,11 +24,42 @@ func (i *Inertia) Location(w http.ResponseWriter, r *http.Request, url string) e
 	return nil
 }
 
+// LocationInternal redirects to url, using a regular Inertia visit (303) for
+// same-origin targets and an external redirect (409) for other origins.
+func (i *Inertia) LocationInternal(w http.ResponseWriter, r *http.Request, url string) error {
+	if IsSameOrigin(r, url) {
+		return i.Redirect(w, r, url)
+	}
+	return i.Location(w, r, url)
+}
+
+// IsSameOrigin reports whether target points at the same host as the request.
+// Relative URLs are always same-origin.
+func IsSameOrigin(r *http.Request, target string) bool {
+	u, err := url.Parse(target)
+	if err != nil {
+		return false
+	}
+	if u.Host == "" {
+		return u.Scheme == ""
+	}
+	if u.Scheme != "" && u.Scheme != "http" && u.Scheme != "https" {
+		return false
+	}
+	return u.Host == r.Host
+}
+
 // Back redirects back to the previous page (using Referer header).
 func (i *Inertia) Back(w http.ResponseWriter, r *http.Request) error {
+	return i.BackOr(w, r, "/")
+}
+
+// BackOr redirects back to the previous page, or to fallback if the request has
+// no Referer header.
+func (i *Inertia) BackOr(w http.ResponseWriter, r *http.Request, fallback string) error {
 	referer := r.Header.Get("Referer")
 	if referer == "" {
-		referer = "/"
+		referer = fallback
 	}
 
 	return i.Location(w, r, referer)
@@ -35,7 +67,7 @@ func (i *Inertia) Back(w http.ResponseWriter, r *http.Request) error {
 
 // Redirect performs an internal redirect.
 func (i *Inertia) Redirect(w http.ResponseWriter, r *http.Request, url string) error {
-	if IsInertiaRequest(r) {
+	if i.isInertiaRequest(r) {
 		// For Inertia requests, always use 303 See Other to change method to GET
 		w.Header().Set("Location", url)
 		w.WriteHeader(http.StatusSeeOther)
@@ -47,25 +79,61 @@ func (i *Inertia) Redirect(w http.ResponseWriter, r *http.Request, url string) e
 	return nil
 }
 
-// Error creates an error page response.
+// Error creates an error page response. Shared data is merged into its props;
+// with Config.StaticSharedDataOnError, shared functions are not evaluated. Prop
+// transformers run as for any rendered page.
 func (i *Inertia) Error(status int, message, url string, _ *http.Request) (*Page, error) {
 	props := map[string]interface{}{
 		"status":  status,
 		"message": message,
 	}
 
-	page := NewPage("Error", props, url, i.version)
-	page.MergeSharedData(i.GetSharedData())
+	page := NewPage(i.errorComponent(), props, url, i.Version())
+	if i.config.StaticSharedDataOnError {
+		page.MergeSharedData(i.sharedData)
+	} else {
+		page.MergeSharedData(i.GetSharedData())
+	}
+	resolveProps(page.Props)
+	if err := i.transformProps(page.Props); err != nil {
+		return nil, err
+	}
 
 	return page, nil
 }
 
-// WithErrors adds validation errors to the page props.
+// errorComponent returns the component used for error pages.
+func (i *Inertia) errorComponent() string {
+	if i.config.ErrorComponent != "" {
+		return i.config.ErrorComponent
+	}
+	return "Error"
+}
+
+// DefaultErrorsPropKey is the prop that carries validation errors unless
+// Config.ErrorsPropKey is set.
+const DefaultErrorsPropKey = "errors"
+
+// WithErrors adds validation errors to the page props under DefaultErrorsPropKey.
 func (p *Page) WithErrors(errors ValidationErrors) *Page {
-	p.Props["errors"] = errors
+	return p.WithErrorsAt(DefaultErrorsPropKey, errors)
+}
+
+// WithErrorsAt adds validation errors to the page props under key.
+func (p *Page) WithErrorsAt(key string, errors ValidationErrors) *Page {
+	p.Props[key] = errors
 	return p
 }
 
+// ErrorsPropKey returns the prop that carries validation errors, see
+// Config.ErrorsPropKey.
+func (i *Inertia) ErrorsPropKey() string {
+	if i.config.ErrorsPropKey != "" {
+		return i.config.ErrorsPropKey
+	}
+	return DefaultErrorsPropKey
+}
+
 // WithFlash adds flash messages to the page props.
 func (p *Page) WithFlash(flash Flash) *Page {
 	for key, value := range flash {
@@ -1,6 +1,7 @@
 package inertia_test
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -52,6 +53,64 @@ func TestLocation_NonInertiaRequest(t *testing.T) {
 	assert.Equal(t, "https://external.com", w.Header().Get("Location"))
 }
 
+func TestLocationInternal(t *testing.T) {
+	config := inertia.Config{
+		RootView: "app.html",
+		Version:  "1.0.0",
+	}
+
+	i, err := inertia.New(config)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		target       string
+		wantCode     int
+		wantLocation string
+		wantInertia  string
+	}{
+		{
+			name:         "relative path",
+			target:       "/dashboard",
+			wantCode:     http.StatusSeeOther,
+			wantLocation: "/dashboard",
+		},
+		{
+			name:         "absolute same-origin URL",
+			target:       "http://example.com/dashboard",
+			wantCode:     http.StatusSeeOther,
+			wantLocation: "http://example.com/dashboard",
+		},
+		{
+			name:        "cross-origin URL",
+			target:      "https://external.com/login",
+			wantCode:    http.StatusConflict,
+			wantInertia: "https://external.com/login",
+		},
+		{
+			name:        "protocol-relative cross-origin URL",
+			target:      "//external.com/login",
+			wantCode:    http.StatusConflict,
+			wantInertia: "//external.com/login",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "http://example.com/login", http.NoBody)
+			req.Header.Set("X-Inertia", "true")
+			w := httptest.NewRecorder()
+			ic := inertia.NewContext(NewMockContext(w, req), i)
+
+			require.NoError(t, ic.LocationInternal(tt.target))
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			assert.Equal(t, tt.wantLocation, w.Header().Get("Location"))
+			assert.Equal(t, tt.wantInertia, w.Header().Get("X-Inertia-Location"))
+		})
+	}
+}
+
 func TestBack_InertiaRequest(t *testing.T) {
 	config := inertia.Config{
 		RootView: "app.html",
@@ -96,6 +155,48 @@ func TestBack_NoReferer(t *testing.T) {
 	assert.Equal(t, "/", w.Header().Get("X-Inertia-Location"))
 }
 
+func TestBackOr(t *testing.T) {
+	i, err := inertia.New(inertia.Config{
+		RootView: "app.html",
+		Version:  "1.0.0",
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		referer  string
+		expected string
+	}{
+		{name: "referer present", referer: "/previous-page", expected: "/previous-page"},
+		{name: "referer absent uses fallback", referer: "", expected: "/dashboard"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/test", http.NoBody)
+			req.Header.Set("X-Inertia", "true")
+			if tt.referer != "" {
+				req.Header.Set("Referer", tt.referer)
+			}
+			w := httptest.NewRecorder()
+
+			require.NoError(t, i.BackOr(w, req, "/dashboard"))
+			assert.Equal(t, http.StatusConflict, w.Code)
+			assert.Equal(t, tt.expected, w.Header().Get("X-Inertia-Location"))
+		})
+	}
+
+	t.Run("context helper", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/posts", http.NoBody)
+		w := httptest.NewRecorder()
+		ic := inertia.NewContext(NewMockContext(w, req), i)
+
+		require.NoError(t, ic.BackOr("/posts/create"))
+		assert.Equal(t, http.StatusFound, w.Code)
+		assert.Equal(t, "/posts/create", w.Header().Get("Location"))
+	})
+}
+
 func TestRedirect_InertiaRequest_GET(t *testing.T) {
 	config := inertia.Config{
 		RootView: "app.html",
@@ -181,6 +282,72 @@ func TestRedirect_NonInertiaRequest(t *testing.T) {
 	assert.Equal(t, "/dashboard", w.Header().Get("Location"))
 }
 
+func TestRedirects_TreatXHRAsInertia(t *testing.T) {
+	i, err := inertia.New(inertia.Config{RootView: "app.html", Version: "1.0.0", TreatXHRAsInertia: true})
+	require.NoError(t, err)
+
+	newXHR := func() *http.Request {
+		req := httptest.NewRequest("POST", "/users", http.NoBody)
+		req.Header.Set("X-Requested-With", "XMLHttpRequest")
+		req.Header.Set("Referer", "/users/create")
+		return req
+	}
+
+	tests := []struct {
+		name     string
+		redirect func(w http.ResponseWriter, r *http.Request) error
+		status   int
+		header   string
+		location string
+	}{
+		{
+			name:     "Location",
+			redirect: func(w http.ResponseWriter, r *http.Request) error { return i.Location(w, r, "https://external.com") },
+			status:   http.StatusConflict,
+			header:   "X-Inertia-Location",
+			location: "https://external.com",
+		},
+		{
+			name:     "LocationInternal",
+			redirect: func(w http.ResponseWriter, r *http.Request) error { return i.LocationInternal(w, r, "/users") },
+			status:   http.StatusSeeOther,
+			header:   "Location",
+			location: "/users",
+		},
+		{
+			name:     "Redirect",
+			redirect: func(w http.ResponseWriter, r *http.Request) error { return i.Redirect(w, r, "/users") },
+			status:   http.StatusSeeOther,
+			header:   "Location",
+			location: "/users",
+		},
+		{
+			name:     "Back",
+			redirect: i.Back,
+			status:   http.StatusConflict,
+			header:   "X-Inertia-Location",
+			location: "/users/create",
+		},
+		{
+			name:     "BackOr",
+			redirect: func(w http.ResponseWriter, r *http.Request) error { return i.BackOr(w, r, "/") },
+			status:   http.StatusConflict,
+			header:   "X-Inertia-Location",
+			location: "/users/create",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			require.NoError(t, tt.redirect(w, newXHR()))
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.location, w.Header().Get(tt.header))
+		})
+	}
+}
+
 func TestError_Response(t *testing.T) {
 	config := inertia.Config{
 		RootView: "app.html",
@@ -202,6 +369,44 @@ func TestError_Response(t *testing.T) {
 	assert.Equal(t, "Page not found", errorPage.Props["message"])
 }
 
+func TestError_StaticSharedDataOnError(t *testing.T) {
+	newManager := func(t *testing.T, staticOnly bool) *inertia.Inertia {
+		t.Helper()
+		mgr, err := inertia.New(inertia.Config{RootView: "app.html", Version: "1.0.0", StaticSharedDataOnError: staticOnly})
+		require.NoError(t, err)
+		mgr.Share("appName", "Acme")
+		mgr.ShareFunc("notifications", func() interface{} {
+			panic("database is down")
+		})
+		return mgr
+	}
+
+	t.Run("skips shared functions", func(t *testing.T) {
+		mgr := newManager(t, true)
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/orders", http.NoBody)
+		req.Header.Set("X-Inertia", "true")
+		ic := inertia.NewContext(NewMockContext(w, req), mgr)
+		require.NoError(t, ic.Error(http.StatusServiceUnavailable, "Service unavailable"))
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		var page inertia.Page
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		assert.Equal(t, "Error", page.Component)
+		assert.Equal(t, "Acme", page.Props["appName"])
+		assert.NotContains(t, page.Props, "notifications")
+	})
+
+	t.Run("evaluates shared functions by default", func(t *testing.T) {
+		mgr := newManager(t, false)
+
+		assert.Panics(t, func() {
+			_, _ = mgr.Error(http.StatusServiceUnavailable, "Service unavailable", "/orders", nil)
+		})
+	})
+}
+
 func TestValidationErrors(t *testing.T) {
 	errors := inertia.ValidationErrors{
 		"email":    []string{"Email is required", "Email must be valid"},
@@ -2,6 +2,9 @@ package inertia_test
 
 import (
 	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -208,3 +211,133 @@ func TestRender_EmptyProps(t *testing.T) {
 	require.NoError(t, err)
 	assert.NotNil(t, page.Props)
 }
+
+type recordingLogger struct {
+	messages []string
+}
+
+func (l *recordingLogger) Printf(format string, args ...interface{}) {
+	l.messages = append(l.messages, fmt.Sprintf(format, args...))
+}
+
+func TestRender_ShadowedSharedProps(t *testing.T) {
+	config := inertia.Config{
+		RootView: "app.html",
+		Version:  "1.0.0",
+	}
+
+	props := func() map[string]interface{} {
+		return map[string]interface{}{
+			"app_name": "Override App",
+			"title":    "Home",
+		}
+	}
+
+	t.Run("logs shadowed keys", func(t *testing.T) {
+		i, err := inertia.New(config)
+		require.NoError(t, err)
+
+		logger := &recordingLogger{}
+		i.SetLogger(logger)
+		i.Share("app_name", "Shared App")
+
+		page, err := i.Render("Home/Index", props(), "/")
+		require.NoError(t, err)
+
+		assert.Equal(t, "Override App", page.Props["app_name"])
+		require.Len(t, logger.messages, 1)
+		assert.Contains(t, logger.messages[0], "app_name")
+		assert.Contains(t, logger.messages[0], "Home/Index")
+	})
+
+	t.Run("strict mode returns an error", func(t *testing.T) {
+		i, err := inertia.New(config)
+		require.NoError(t, err)
+
+		i.SetStrictProps(true)
+		i.ShareFunc("app_name", func() interface{} { return "Shared App" })
+
+		_, err = i.Render("Home/Index", props(), "/")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "app_name")
+
+		_, err = i.RenderOnly("Home/Index", props(), "/", []string{"title"})
+		assert.NoError(t, err)
+	})
+}
+
+func TestInertia_SetJSONMarshaler(t *testing.T) {
+	render := func(t *testing.T, marshal func(interface{}) ([]byte, error)) string {
+		t.Helper()
+
+		mgr, err := inertia.New(inertia.Config{RootView: "app.html", Version: "1.0.0"})
+		require.NoError(t, err)
+		if marshal != nil {
+			mgr.SetJSONMarshaler(marshal)
+		}
+
+		req := httptest.NewRequest("GET", "/posts", http.NoBody)
+		req.Header.Set("X-Inertia", "true")
+		w := httptest.NewRecorder()
+		ic := inertia.NewContext(NewMockContext(w, req), mgr)
+
+		require.NoError(t, ic.Render("Posts/Index", map[string]interface{}{
+			"posts": []map[string]interface{}{
+				{"id": 1, "title": "Hello <World> & friends"},
+			},
+			"total": 1,
+		}))
+		return w.Body.String()
+	}
+
+	calls := 0
+	custom := func(v interface{}) ([]byte, error) {
+		calls++
+		return streamMarshal(v)
+	}
+
+	assert.Equal(t, render(t, nil), render(t, custom))
+	assert.Equal(t, 1, calls)
+}
+
+func TestInertiaContext_MaxPropBytes(t *testing.T) {
+	users := make([]map[string]interface{}, 200)
+	for n := range users {
+		users[n] = map[string]interface{}{"id": n, "name": "User"}
+	}
+
+	render := func(t *testing.T, strict bool, props map[string]interface{}) (*recordingLogger, error) {
+		t.Helper()
+
+		mgr, err := inertia.New(inertia.Config{RootView: "app.html", Version: "1.0.0", MaxPropBytes: 1024})
+		require.NoError(t, err)
+		logger := &recordingLogger{}
+		mgr.SetLogger(logger)
+		mgr.SetStrictProps(strict)
+
+		req := httptest.NewRequest("GET", "/users", http.NoBody)
+		req.Header.Set("X-Inertia", "true")
+		ic := inertia.NewContext(NewMockContext(httptest.NewRecorder(), req), mgr)
+		return logger, ic.Render("Users/Index", props)
+	}
+
+	t.Run("under the limit", func(t *testing.T) {
+		logger, err := render(t, true, map[string]interface{}{"users": users[:2], "title": "Users"})
+		require.NoError(t, err)
+		assert.Empty(t, logger.messages)
+	})
+
+	t.Run("over the limit logs the largest prop", func(t *testing.T) {
+		logger, err := render(t, false, map[string]interface{}{"users": users, "title": "Users"})
+		require.NoError(t, err)
+		require.Len(t, logger.messages, 1)
+		assert.Contains(t, logger.messages[0], `largest prop "users"`)
+		assert.Contains(t, logger.messages[0], "Users/Index")
+	})
+
+	t.Run("over the limit in strict mode", func(t *testing.T) {
+		_, err := render(t, true, map[string]interface{}{"users": users, "title": "Users"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `"users"`)
+	})
+}
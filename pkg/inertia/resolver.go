@@ -0,0 +1,49 @@
+package inertia
+
+// PropResolver is implemented by prop values that resolve themselves at render time.
+// Values implementing it can be passed directly in the props map; Render and
+// RenderOnly call ResolveProp once and serialize the returned value instead.
+type PropResolver interface {
+	ResolveProp() interface{}
+}
+
+// conditionalProp is a prop that is only included when its condition holds.
+type conditionalProp struct {
+	cond  bool
+	value interface{}
+	fn    func() interface{}
+}
+
+// When includes value as a prop only if cond is true. When cond is false the key
+// is omitted from the props entirely rather than being sent as null.
+//
+//	"canEdit": inertia.When(user.IsAdmin(), true),
+func When(cond bool, value interface{}) interface{} {
+	return conditionalProp{cond: cond, value: value}
+}
+
+// WhenFunc is like When, but fn is only called when cond is true.
+func WhenFunc(cond bool, fn func() interface{}) interface{} {
+	return conditionalProp{cond: cond, fn: fn}
+}
+
+// resolveProps replaces every top-level PropResolver value with its resolved value
+// and resolves conditional props, removing those whose condition is false.
+func resolveProps(props map[string]interface{}) {
+	for key, value := range props {
+		switch v := value.(type) {
+		case conditionalProp:
+			if !v.cond {
+				delete(props, key)
+				continue
+			}
+			if v.fn != nil {
+				props[key] = v.fn()
+			} else {
+				props[key] = v.value
+			}
+		case PropResolver:
+			props[key] = v.ResolveProp()
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package inertia_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/toutaio/toutago-inertia/pkg/inertia"
+)
+
+// countingResolver records how many times it was resolved.
+type countingResolver struct {
+	calls int
+	value interface{}
+}
+
+func (r *countingResolver) ResolveProp() interface{} {
+	r.calls++
+	return r.value
+}
+
+func TestRender_PropResolver(t *testing.T) {
+	config := inertia.Config{
+		RootView: "app.html",
+		Version:  "1.0.0",
+	}
+
+	mgr, err := inertia.New(config)
+	require.NoError(t, err)
+
+	resolver := &countingResolver{value: []string{"Alice", "Bob"}}
+
+	page, err := mgr.Render("Users/Index", map[string]interface{}{
+		"users": resolver,
+	}, "/users")
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, resolver.calls, "resolver should be evaluated exactly once")
+	assert.Equal(t, []string{"Alice", "Bob"}, page.Props["users"])
+
+	data, err := json.Marshal(page)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"users":["Alice","Bob"]`)
+	assert.Equal(t, 1, resolver.calls, "serialization should not re-evaluate the resolver")
+}
+
+func TestRenderOnly_PropResolver(t *testing.T) {
+	config := inertia.Config{
+		RootView: "app.html",
+		Version:  "1.0.0",
+	}
+
+	mgr, err := inertia.New(config)
+	require.NoError(t, err)
+
+	requested := &countingResolver{value: 42}
+	skipped := &countingResolver{value: "expensive"}
+
+	page, err := mgr.RenderOnly("Stats", map[string]interface{}{
+		"count":  requested,
+		"report": skipped,
+	}, "/stats", []string{"count"})
+	require.NoError(t, err)
+
+	assert.Equal(t, 42, page.Props["count"])
+	assert.Equal(t, 1, requested.calls)
+	assert.Equal(t, 0, skipped.calls, "filtered-out resolvers should not be evaluated")
+}
+
+func TestRender_When(t *testing.T) {
+	config := inertia.Config{
+		RootView: "app.html",
+		Version:  "1.0.0",
+	}
+
+	mgr, err := inertia.New(config)
+	require.NoError(t, err)
+
+	called := false
+	page, err := mgr.Render("Posts/Show", map[string]interface{}{
+		"canEdit":   inertia.When(true, true),
+		"canDelete": inertia.When(false, true),
+		"audit": inertia.WhenFunc(false, func() interface{} {
+			called = true
+			return "audit log"
+		}),
+		"stats": inertia.WhenFunc(true, func() interface{} {
+			return map[string]int{"views": 3}
+		}),
+	}, "/posts/1")
+	require.NoError(t, err)
+
+	assert.Equal(t, true, page.Props["canEdit"])
+	assert.Equal(t, map[string]int{"views": 3}, page.Props["stats"])
+	assert.NotContains(t, page.Props, "canDelete")
+	assert.NotContains(t, page.Props, "audit")
+	assert.False(t, called)
+
+	data, err := json.Marshal(page)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "canDelete")
+}
+
+func TestError_ResolvesSharedConditionalProps(t *testing.T) {
+	mgr, err := inertia.New(inertia.Config{RootView: "app.html", Version: "1.0.0"})
+	require.NoError(t, err)
+	mgr.Share("beta", inertia.When(true, map[string]bool{"newDashboard": true}))
+	mgr.Share("admin", inertia.When(false, "hidden"))
+	mgr.Share("plan", &countingResolver{value: "pro"})
+
+	page, err := mgr.Error(500, "Server error", "/reports", nil)
+	require.NoError(t, err)
+
+	data, err := json.Marshal(page.Props)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"status": 500,
+		"message": "Server error",
+		"beta": {"newDashboard": true},
+		"plan": "pro"
+	}`, string(data))
+}
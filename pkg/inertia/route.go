@@ -0,0 +1,55 @@
+package inertia
+
+import (
+	"context"
+	"net/http"
+)
+
+// contextKeyRouteName is the request context key holding the current route name.
+const contextKeyRouteName contextKey = "route_name"
+
+// routeKey is the prop under which the current route name is shared.
+const routeKey = "route"
+
+// WithRouteName returns a copy of r carrying the name of the matched route. Pages
+// rendered for the request receive it as the "route" prop, which frontends can
+// use to highlight the active navigation item.
+func WithRouteName(r *http.Request, name string) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), contextKeyRouteName, name))
+}
+
+// RouteName returns the route name stored on the request, if any.
+func RouteName(r *http.Request) string {
+	if name, ok := r.Context().Value(contextKeyRouteName).(string); ok {
+		return name
+	}
+	return ""
+}
+
+// RouteNameMiddleware returns a middleware that stores the route name returned by
+// resolve on each request. Since route names are router specific, resolve bridges
+// to the router in use; an empty name is not stored.
+//
+//	mux.Handle("/", mgr.RouteNameMiddleware(func(r *http.Request) string {
+//		return r.Pattern
+//	})(handler))
+func (i *Inertia) RouteNameMiddleware(resolve func(*http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if name := resolve(r); name != "" {
+				r = WithRouteName(r, name)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// mergeRouteName shares the request's route name as the "route" prop.
+func (ic *InertiaContext) mergeRouteName(props map[string]interface{}, req *http.Request) {
+	name := RouteName(req)
+	if name == "" {
+		return
+	}
+
+	setDefault(props, routeKey, name)
+}
@@ -0,0 +1,62 @@
+package inertia_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/toutaio/toutago-inertia/pkg/inertia"
+)
+
+func TestRouteNameMiddleware(t *testing.T) {
+	mgr, err := inertia.New(inertia.Config{
+		RootView: "app.html",
+		Version:  "1.0.0",
+	})
+	require.NoError(t, err)
+
+	routes := map[string]string{
+		"/users":    "users.index",
+		"/users/42": "users.show",
+	}
+	resolve := func(r *http.Request) string {
+		return routes[r.URL.Path]
+	}
+
+	handler := mgr.RouteNameMiddleware(resolve)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ic := inertia.NewContext(NewMockContext(w, r), mgr)
+		require.NoError(t, ic.Render("Page", map[string]interface{}{}))
+	}))
+
+	render := func(path string) map[string]interface{} {
+		req := httptest.NewRequest("GET", path, http.NoBody)
+		req.Header.Set("X-Inertia", "true")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		var page inertia.Page
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		return page.Props
+	}
+
+	for path, name := range routes {
+		t.Run(path, func(t *testing.T) {
+			assert.Equal(t, name, render(path)["route"])
+		})
+	}
+
+	t.Run("unresolved route is not shared", func(t *testing.T) {
+		assert.NotContains(t, render("/unknown"), "route")
+	})
+}
+
+func TestWithRouteName(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", http.NoBody)
+	assert.Empty(t, inertia.RouteName(req))
+
+	req = inertia.WithRouteName(req, "home")
+	assert.Equal(t, "home", inertia.RouteName(req))
+}
@@ -0,0 +1,121 @@
+package inertia
+
+import (
+	"net/http"
+	"strings"
+)
+
+// SecurityHeadersOptions configures SecurityHeadersMiddleware. Empty fields use
+// the defaults noted; optional headers are omitted when empty.
+type SecurityHeadersOptions struct {
+	// ContentTypeOptions is the X-Content-Type-Options value (default "nosniff").
+	ContentTypeOptions string
+
+	// ReferrerPolicy is the Referrer-Policy value
+	// (default "strict-origin-when-cross-origin").
+	ReferrerPolicy string
+
+	// FrameOptions is the X-Frame-Options value, e.g. "DENY". Optional.
+	FrameOptions string
+
+	// ContentSecurityPolicy is the Content-Security-Policy value. Optional.
+	ContentSecurityPolicy string
+
+	// Headers holds additional headers to set, e.g. Permissions-Policy.
+	Headers map[string]string
+}
+
+// headers returns the headers to apply to HTML responses.
+func (o SecurityHeadersOptions) headers() map[string]string {
+	headers := make(map[string]string, len(o.Headers)+4)
+	for name, value := range o.Headers {
+		headers[http.CanonicalHeaderKey(name)] = value
+	}
+
+	headers["X-Content-Type-Options"] = "nosniff"
+	if o.ContentTypeOptions != "" {
+		headers["X-Content-Type-Options"] = o.ContentTypeOptions
+	}
+	headers["Referrer-Policy"] = "strict-origin-when-cross-origin"
+	if o.ReferrerPolicy != "" {
+		headers["Referrer-Policy"] = o.ReferrerPolicy
+	}
+	if o.FrameOptions != "" {
+		headers["X-Frame-Options"] = o.FrameOptions
+	}
+	if o.ContentSecurityPolicy != "" {
+		headers["Content-Security-Policy"] = o.ContentSecurityPolicy
+	}
+
+	return headers
+}
+
+// SecurityHeadersMiddleware returns a middleware that adds baseline security
+// headers to HTML responses, i.e. full page loads rendered through the root
+// template (with or without SSR). Inertia JSON navigations and other non-HTML
+// responses are left untouched. Headers already set by the handler are kept.
+//
+//	handler := mgr.SecurityHeadersMiddleware(inertia.SecurityHeadersOptions{
+//		FrameOptions:          "DENY",
+//		ContentSecurityPolicy: "default-src 'self'",
+//	})(mgr.Middleware()(mux))
+func (i *Inertia) SecurityHeadersMiddleware(opts SecurityHeadersOptions) func(http.Handler) http.Handler {
+	headers := opts.headers()
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(&securityHeadersWriter{ResponseWriter: w, headers: headers}, r)
+		})
+	}
+}
+
+// securityHeadersWriter adds security headers when the response turns out to be HTML.
+type securityHeadersWriter struct {
+	http.ResponseWriter
+	headers     map[string]string
+	wroteHeader bool
+}
+
+func (w *securityHeadersWriter) WriteHeader(statusCode int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.applyHeaders()
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *securityHeadersWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush sends buffered data to the client, if the wrapped writer supports it.
+func (w *securityHeadersWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap returns the underlying writer for http.ResponseController.
+func (w *securityHeadersWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// applyHeaders sets the security headers if the response content type is HTML.
+func (w *securityHeadersWriter) applyHeaders() {
+	header := w.Header()
+	if !strings.HasPrefix(header.Get("Content-Type"), "text/html") {
+		return
+	}
+
+	for name, value := range w.headers {
+		if header.Get(name) == "" {
+			header.Set(name, value)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package inertia_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/toutaio/toutago-inertia/pkg/inertia"
+)
+
+func TestSecurityHeadersMiddleware(t *testing.T) {
+	mgr, err := inertia.New(inertia.Config{
+		RootView: writeRootTemplate(t, "app.html", testRootTemplate),
+		Version:  "1.0.0",
+	})
+	require.NoError(t, err)
+
+	handler := mgr.SecurityHeadersMiddleware(inertia.SecurityHeadersOptions{
+		FrameOptions:          "DENY",
+		ContentSecurityPolicy: "default-src 'self'",
+		Headers:               map[string]string{"permissions-policy": "camera=()"},
+	})(mgr.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ic := inertia.NewContext(NewMockContext(w, r), mgr)
+		require.NoError(t, ic.Render("Home", map[string]interface{}{"title": "Home"}))
+	})))
+
+	t.Run("full page load", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, newFullLoadRequest("/"))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+		assert.Equal(t, "strict-origin-when-cross-origin", w.Header().Get("Referrer-Policy"))
+		assert.Equal(t, "DENY", w.Header().Get("X-Frame-Options"))
+		assert.Equal(t, "default-src 'self'", w.Header().Get("Content-Security-Policy"))
+		assert.Equal(t, "camera=()", w.Header().Get("Permissions-Policy"))
+	})
+
+	t.Run("Inertia navigation", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/", http.NoBody)
+		req.Header.Set("X-Inertia", "true")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		assert.Empty(t, w.Header().Get("X-Content-Type-Options"))
+		assert.Empty(t, w.Header().Get("Referrer-Policy"))
+		assert.Empty(t, w.Header().Get("Content-Security-Policy"))
+	})
+}
+
+func TestSecurityHeadersMiddleware_KeepsHandlerHeaders(t *testing.T) {
+	mgr, err := inertia.New(inertia.Config{RootView: "app.html", Version: "1.0.0"})
+	require.NoError(t, err)
+
+	handler := mgr.SecurityHeadersMiddleware(inertia.SecurityHeadersOptions{
+		ReferrerPolicy: "no-referrer",
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Referrer-Policy", "same-origin")
+		_, _ = w.Write([]byte("<html></html>"))
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/", http.NoBody))
+
+	assert.Equal(t, "same-origin", w.Header().Get("Referrer-Policy"))
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+	assert.Empty(t, w.Header().Get("X-Frame-Options"))
+}
+
+func TestSecurityHeadersMiddleware_Flush(t *testing.T) {
+	mgr, err := inertia.New(inertia.Config{RootView: "app.html", Version: "1.0.0"})
+	require.NoError(t, err)
+
+	handler := mgr.SecurityHeadersMiddleware(inertia.SecurityHeadersOptions{})(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			flusher, ok := w.(http.Flusher)
+			require.True(t, ok, "writer should implement http.Flusher")
+			flusher.Flush()
+		}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, newFullLoadRequest("/"))
+
+	assert.True(t, w.Flushed)
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+}
@@ -0,0 +1,48 @@
+package inertia
+
+// ServerOnly marks props that are passed to the SSR renderer but never sent to the
+// client: they are stripped from the page everywhere, including the page given to
+// the SSR bundle, and handed to the bundle separately, see ssr.Renderer. Use it
+// for values such as CSRF tokens that only server rendering needs.
+func (ic *InertiaContext) ServerOnly(keys ...string) *InertiaContext {
+	if ic.serverOnly == nil {
+		ic.serverOnly = make(map[string]bool, len(keys))
+	}
+	for _, key := range keys {
+		ic.serverOnly[key] = true
+	}
+	return ic
+}
+
+// serverProps returns the server-only props of the page, or nil if there are none.
+func (ic *InertiaContext) serverProps(page *Page) map[string]interface{} {
+	if len(ic.serverOnly) == 0 {
+		return nil
+	}
+
+	props := make(map[string]interface{}, len(ic.serverOnly))
+	for key := range ic.serverOnly {
+		if value, ok := page.Props[key]; ok {
+			props[key] = value
+		}
+	}
+	return props
+}
+
+// clientPage returns the page as sent to the browser, without server-only props.
+func (ic *InertiaContext) clientPage(page *Page) *Page {
+	if len(ic.serverOnly) == 0 {
+		return page
+	}
+
+	props := make(map[string]interface{}, len(page.Props))
+	for key, value := range page.Props {
+		if !ic.serverOnly[key] {
+			props[key] = value
+		}
+	}
+
+	client := *page
+	client.Props = props
+	return &client
+}
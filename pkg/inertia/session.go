@@ -0,0 +1,229 @@
+package inertia
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// SessionStore persists data between requests, such as flashed values that must
+// survive a redirect.
+type SessionStore interface {
+	// Flash stores a value that is available to the next request.
+	Flash(w http.ResponseWriter, r *http.Request, key string, value interface{}) error
+	// Pull returns a flashed value and removes it from the session.
+	Pull(w http.ResponseWriter, r *http.Request, key string) (interface{}, bool)
+}
+
+// SetSessionStore sets the session store used for flashed data such as old input.
+func (i *Inertia) SetSessionStore(store SessionStore) {
+	i.sessions = store
+}
+
+// SessionStore returns the session store set with SetSessionStore, or nil.
+func (i *Inertia) SessionStore() SessionStore {
+	return i.sessions
+}
+
+// sessionCookieName is the cookie holding the MemorySessionStore session ID.
+const sessionCookieName = "inertia_session"
+
+// MemorySessionStore is an in-memory SessionStore that identifies sessions with a
+// cookie. It is intended for development and tests; data is lost on restart and
+// is not shared between processes.
+type MemorySessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]map[string]interface{}
+}
+
+// NewMemorySessionStore creates an in-memory session store.
+func NewMemorySessionStore() *MemorySessionStore {
+	return &MemorySessionStore{
+		sessions: make(map[string]map[string]interface{}),
+	}
+}
+
+// Flash implements SessionStore.
+func (s *MemorySessionStore) Flash(w http.ResponseWriter, r *http.Request, key string, value interface{}) error {
+	id := sessionID(r)
+	if id == "" {
+		buf := make([]byte, 16)
+		if _, err := rand.Read(buf); err != nil {
+			return err
+		}
+		id = hex.EncodeToString(buf)
+		http.SetCookie(w, &http.Cookie{
+			Name:     sessionCookieName,
+			Value:    id,
+			Path:     "/",
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.sessions[id] == nil {
+		s.sessions[id] = make(map[string]interface{})
+	}
+	s.sessions[id][key] = value
+	return nil
+}
+
+// Pull implements SessionStore.
+func (s *MemorySessionStore) Pull(_ http.ResponseWriter, r *http.Request, key string) (interface{}, bool) {
+	id := sessionID(r)
+	if id == "" {
+		return nil, false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	value, ok := s.sessions[id][key]
+	if ok {
+		delete(s.sessions[id], key)
+		if len(s.sessions[id]) == 0 {
+			delete(s.sessions, id)
+		}
+	}
+	return value, ok
+}
+
+// sessionID returns the session ID from the request cookie.
+func sessionID(r *http.Request) string {
+	cookie, err := r.Cookie(sessionCookieName)
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
+
+// WithOldInput captures the submitted form or JSON fields of r and flashes them to
+// the SessionStore, so the next render exposes them as the "old" prop (e.g. after
+// Back() on a validation failure). Fields whose name contains "password" are never
+// stored. A render in the same request also receives the old input.
+//
+// JSON bodies are restored after reading, so r can still be bound afterwards.
+func (ic *InertiaContext) WithOldInput(r *http.Request) *InertiaContext {
+	input := captureInput(r, ic.mgr.maxMemory())
+	if len(input) == 0 {
+		return ic
+	}
+
+	ic.oldInput = input
+	if ic.mgr.sessions != nil {
+		if err := ic.mgr.sessions.Flash(ic.ctx.Response(), r, "old", input); err != nil {
+			ic.mgr.logf("inertia: failed to flash old input: %v", err)
+		}
+	}
+	return ic
+}
+
+// mergeOldInput adds old input flashed by a previous request (or captured in this
+// one) as the "old" prop.
+func (ic *InertiaContext) mergeOldInput(props map[string]interface{}) {
+	var old interface{}
+	if ic.mgr.sessions != nil {
+		old, _ = ic.mgr.sessions.Pull(ic.ctx.Response(), ic.ctx.Request(), "old")
+	}
+	if ic.oldInput != nil {
+		old = ic.oldInput
+	}
+
+	if old == nil {
+		return
+	}
+	ic.sessionPulled = true
+	setDefault(props, "old", old)
+}
+
+// sessionFlashKey is the SessionStore key and prop name of session flash messages.
+const sessionFlashKey = "flash"
+
+// WithSessionFlash exposes flash messages stored in the SessionStore under
+// "flash" (e.g. by a handler that redirected) as the "flash" prop of the next
+// render, removing them from the session. When the session holds no messages
+// the prop is omitted instead of being sent empty on every page.
+func (ic *InertiaContext) WithSessionFlash() *InertiaContext {
+	ic.sessionFlash = true
+	return ic
+}
+
+// mergeSessionFlash adds the flash messages pulled from the session, if any, as
+// the "flash" prop.
+func (ic *InertiaContext) mergeSessionFlash(props map[string]interface{}) {
+	if !ic.sessionFlash || ic.mgr.sessions == nil {
+		return
+	}
+
+	flash, ok := ic.mgr.sessions.Pull(ic.ctx.Response(), ic.ctx.Request(), sessionFlashKey)
+	if !ok || isEmptyValue(flash) {
+		return
+	}
+	ic.sessionPulled = true
+	setDefault(props, sessionFlashKey, flash)
+}
+
+// isEmptyValue reports whether v is nil or an empty map, slice or string.
+func isEmptyValue(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.String:
+		return rv.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return rv.IsNil()
+	default:
+		return false
+	}
+}
+
+// captureInput returns the submitted form or JSON fields, without passwords.
+// At most maxMemory bytes of a multipart body are kept in memory.
+func captureInput(r *http.Request, maxMemory int64) map[string]interface{} {
+	input := make(map[string]interface{})
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	switch mediaType {
+	case "application/x-www-form-urlencoded", "multipart/form-data":
+		if mediaType == "multipart/form-data" {
+			_ = r.ParseMultipartForm(maxMemory)
+		} else {
+			_ = r.ParseForm()
+		}
+		for key, values := range r.PostForm {
+			if len(values) == 1 {
+				input[key] = values[0]
+			} else {
+				input[key] = values
+			}
+		}
+	case "application/json":
+		if r.Body == nil || r.Body == http.NoBody {
+			break
+		}
+		body, err := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		if err != nil {
+			break
+		}
+		_ = json.Unmarshal(body, &input)
+	}
+
+	for key := range input {
+		if strings.Contains(strings.ToLower(key), "password") {
+			delete(input, key)
+		}
+	}
+	return input
+}
@@ -0,0 +1,143 @@
+package inertia_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/toutaio/toutago-inertia/pkg/inertia"
+)
+
+func TestInertiaContext_WithOldInput(t *testing.T) {
+	config := inertia.Config{
+		RootView: "app.html",
+		Version:  "1.0.0",
+	}
+
+	mgr, err := inertia.New(config)
+	require.NoError(t, err)
+	mgr.SetSessionStore(inertia.NewMemorySessionStore())
+
+	// renderOld renders a page with the given cookies and returns its "old" prop.
+	renderOld := func(cookies []*http.Cookie) (interface{}, bool) {
+		req := httptest.NewRequest("GET", "/posts/create", http.NoBody)
+		req.Header.Set("X-Inertia", "true")
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+		w := httptest.NewRecorder()
+		ictx := inertia.NewContext(NewMockContext(w, req), mgr)
+		require.NoError(t, ictx.Render("Posts/Create", map[string]interface{}{}))
+
+		var page inertia.Page
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		old, ok := page.Props["old"]
+		return old, ok
+	}
+
+	t.Run("form input round-trips through a back redirect", func(t *testing.T) {
+		form := url.Values{}
+		form.Set("title", "Draft")
+		form.Set("password", "secret")
+
+		req := httptest.NewRequest("POST", "/posts", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("X-Inertia", "true")
+		req.Header.Set("Referer", "/posts/create")
+		w := httptest.NewRecorder()
+		ictx := inertia.NewContext(NewMockContext(w, req), mgr)
+
+		require.NoError(t, ictx.WithOldInput(req).WithError("body", "The body field is required.").Back())
+		cookies := w.Result().Cookies()
+		require.NotEmpty(t, cookies)
+
+		old, ok := renderOld(cookies)
+		require.True(t, ok)
+		assert.Equal(t, map[string]interface{}{"title": "Draft"}, old)
+
+		// Flashed input is only available once
+		_, ok = renderOld(cookies)
+		assert.False(t, ok)
+	})
+
+	t.Run("JSON body remains readable", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/posts", strings.NewReader(`{"title":"Draft","tags":["go"]}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		ictx := inertia.NewContext(NewMockContext(w, req), mgr)
+
+		ictx.WithOldInput(req)
+
+		var input struct {
+			Title string `json:"title"`
+		}
+		_, err := inertia.Bind(req, &input)
+		require.NoError(t, err)
+		assert.Equal(t, "Draft", input.Title)
+
+		old, ok := renderOld(w.Result().Cookies())
+		require.True(t, ok)
+		assert.Equal(t, "Draft", old.(map[string]interface{})["title"])
+	})
+
+	t.Run("no old prop without flashed input", func(t *testing.T) {
+		_, ok := renderOld(nil)
+		assert.False(t, ok)
+	})
+}
+
+func TestInertiaContext_WithSessionFlash(t *testing.T) {
+	mgr, err := inertia.New(inertia.Config{
+		RootView: "app.html",
+		Version:  "1.0.0",
+	})
+	require.NoError(t, err)
+	store := inertia.NewMemorySessionStore()
+	mgr.SetSessionStore(store)
+
+	// flashCookies flashes value to a new session and returns its cookies.
+	flashCookies := func(value interface{}) []*http.Cookie {
+		w := httptest.NewRecorder()
+		require.NoError(t, store.Flash(w, httptest.NewRequest("POST", "/todos", http.NoBody), "flash", value))
+		return w.Result().Cookies()
+	}
+
+	// renderFlash renders a page with the given cookies and returns its props.
+	renderFlash := func(cookies []*http.Cookie) map[string]interface{} {
+		req := httptest.NewRequest("GET", "/todos", http.NoBody)
+		req.Header.Set("X-Inertia", "true")
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+		w := httptest.NewRecorder()
+		ictx := inertia.NewContext(NewMockContext(w, req), mgr)
+		require.NoError(t, ictx.WithSessionFlash().Render("Todos/Index", map[string]interface{}{}))
+
+		var page inertia.Page
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		return page.Props
+	}
+
+	t.Run("included when present", func(t *testing.T) {
+		cookies := flashCookies(inertia.Flash{"success": "Todo created."})
+
+		props := renderFlash(cookies)
+		assert.Equal(t, map[string]interface{}{"success": "Todo created."}, props["flash"])
+
+		// Flash messages are shown once
+		assert.NotContains(t, renderFlash(cookies), "flash")
+	})
+
+	t.Run("omitted when empty", func(t *testing.T) {
+		assert.NotContains(t, renderFlash(flashCookies(inertia.Flash{})), "flash")
+	})
+
+	t.Run("omitted without a session", func(t *testing.T) {
+		assert.NotContains(t, renderFlash(nil), "flash")
+	})
+}
@@ -2,6 +2,9 @@ package inertia
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	htmlpkg "html"
 	"strings"
 	"testing"
 
@@ -88,6 +91,43 @@ func TestSSRIntegration(t *testing.T) {
 			t.Error("expected error from SSR render")
 		}
 	})
+
+	t.Run("SSR error returns bootable data-page", func(t *testing.T) {
+		renderer, _ := ssr.NewRenderer()
+		defer renderer.Close()
+
+		renderer.LoadBundle(`
+			global.render = function(page) {
+				throw new Error('Render failed');
+			};
+		`)
+
+		i, _ := New(Config{RootView: "app"})
+		i.SetSSRRenderer(renderer)
+
+		page := NewPage("Users/Show", map[string]interface{}{"name": "Ada & Bob"}, "/users/1", "1")
+		html, err := i.RenderSSR(context.Background(), page)
+		if !errors.Is(err, ErrSSRFailed) {
+			t.Fatalf("expected ErrSSRFailed, got %v", err)
+		}
+
+		prefix, suffix := `<div id="app" data-page="`, `"></div>`
+		if !strings.HasPrefix(html, prefix) || !strings.HasSuffix(html, suffix) {
+			t.Fatalf("expected empty app element, got %q", html)
+		}
+
+		var decoded Page
+		attr := htmlpkg.UnescapeString(strings.TrimSuffix(strings.TrimPrefix(html, prefix), suffix))
+		if err := json.Unmarshal([]byte(attr), &decoded); err != nil {
+			t.Fatalf("data-page is not valid JSON: %v", err)
+		}
+		if decoded.Component != "Users/Show" || decoded.URL != "/users/1" || decoded.Version != "1" {
+			t.Errorf("unexpected page: %+v", decoded)
+		}
+		if decoded.Props["name"] != "Ada & Bob" {
+			t.Errorf("expected props in data-page, got %v", decoded.Props)
+		}
+	})
 }
 
 func TestSSRWithComplexData(t *testing.T) {
@@ -152,3 +192,69 @@ func TestSSRWithComplexData(t *testing.T) {
 		}
 	})
 }
+
+func TestSSRNamedBundles(t *testing.T) {
+	renderer, err := ssr.NewRenderer(&ssr.Config{PoolSize: 1})
+	if err != nil {
+		t.Fatalf("failed to create renderer: %v", err)
+	}
+	defer renderer.Close()
+
+	if err := renderer.LoadBundle(`global.render = function(page) { return '<main>public ' + page.component + '</main>'; };`); err != nil {
+		t.Fatalf("failed to load default bundle: %v", err)
+	}
+	if err := renderer.LoadBundleNamed("admin", `global.render = function(page) { return '<main>admin ' + page.component + '</main>'; };`); err != nil {
+		t.Fatalf("failed to load admin bundle: %v", err)
+	}
+
+	i, _ := New(Config{RootView: "app"})
+	i.SetSSRRenderer(renderer)
+	i.MapSSRBundle("Admin/", "admin")
+
+	html, err := i.RenderSSR(context.Background(), NewPage("Admin/Dashboard", nil, "/admin", "1"))
+	if err != nil {
+		t.Fatalf("SSR render failed: %v", err)
+	}
+	if html != "<main>admin Admin/Dashboard</main>" {
+		t.Errorf("expected admin bundle output, got %q", html)
+	}
+
+	html, err = i.RenderSSR(context.Background(), NewPage("Home", nil, "/", "1"))
+	if err != nil {
+		t.Fatalf("SSR render failed: %v", err)
+	}
+	if html != "<main>public Home</main>" {
+		t.Errorf("expected default bundle output, got %q", html)
+	}
+}
+
+func TestWarmupSSR(t *testing.T) {
+	t.Run("no-op without a renderer", func(t *testing.T) {
+		i, _ := New(Config{RootView: "app"})
+		if err := i.WarmupSSR(); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("warms up the renderer", func(t *testing.T) {
+		renderer, err := ssr.NewRenderer(&ssr.Config{PoolSize: 2})
+		if err != nil {
+			t.Fatalf("failed to create renderer: %v", err)
+		}
+		if err := renderer.LoadBundle(`global.render = function(page) { return '<div>' + page.component + '</div>'; };`); err != nil {
+			t.Fatalf("failed to load bundle: %v", err)
+		}
+
+		i, _ := New(Config{RootView: "app"})
+		i.SetSSRRenderer(renderer)
+
+		if err := i.WarmupSSR(); err != nil {
+			t.Fatalf("warmup failed: %v", err)
+		}
+
+		renderer.Close()
+		if err := i.WarmupSSR(); err == nil {
+			t.Error("expected warmup of a closed renderer to fail")
+		}
+	})
+}
@@ -0,0 +1,132 @@
+package inertia
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/http"
+)
+
+// streamMarker stands in for the SSR body while the root template is executed
+// for a streamed response.
+const streamMarker = "<!--inertia-ssr-stream-->"
+
+// ssrStreamer returns the renderer to stream the page with, if SSR applies to
+// the request and Config.StreamSSR is set. Components mapped to a named bundle
+// are rendered without streaming.
+func (i *Inertia) ssrStreamer(r *http.Request, page *Page) (StreamingSSRRenderer, bool) {
+	if !i.config.StreamSSR || !i.shouldSSR(r) {
+		return nil, false
+	}
+	if _, ok := i.ssrBundleFor(page.Component); ok {
+		return nil, false
+	}
+	streamer, ok := i.ssrRenderer.(StreamingSSRRenderer)
+	return streamer, ok
+}
+
+// streamHTML writes the root template up to {{ .Inertia }} and flushes it, then
+// streams the SSR body and writes the rest of the template. When SSR fails, the
+// client-rendered app element takes the place of the body.
+func (ic *InertiaContext) streamHTML(
+	tmpl *template.Template,
+	streamer StreamingSSRRenderer,
+	page *Page,
+	pageJSON []byte,
+	head []string,
+	status int,
+) error {
+	data := ic.templateData(page, pageJSON, head, "")
+	data.Inertia = streamMarker
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("inertia: failed to execute root template: %w", err)
+	}
+	shell, tail, found := bytes.Cut(buf.Bytes(), []byte(streamMarker))
+
+	res := ic.ctx.Response()
+	res.Header().Set("Content-Type", "text/html; charset=utf-8")
+	ic.writeServerTiming(res.Header())
+	res.WriteHeader(status)
+	if _, err := res.Write(shell); err != nil {
+		return err
+	}
+	if !found {
+		// The template builds its own app element, there is nowhere to stream to
+		return nil
+	}
+	if flusher, ok := res.(http.Flusher); ok {
+		flusher.Flush()
+	}
+
+	req := ic.ctx.Request()
+	nw := &nonceWriter{w: res, nonce: ic.nonce}
+	pageData := ssrPageData(ic.clientPage(page), ic.serverProps(page))
+	err := streamer.RenderStream(req.Context(), pageData, nw)
+	if err == nil {
+		err = nw.writePending()
+	}
+	if err != nil {
+		ic.mgr.logf("inertia: rendering %s without SSR: %v", page.Component, err)
+		if _, err := res.Write([]byte(applyNonce(appElement(pageJSON), ic.nonce))); err != nil {
+			return err
+		}
+	}
+
+	_, err = res.Write(tail)
+	return err
+}
+
+// nonceWriter adds the CSP nonce to script tags written to it, see applyNonce.
+// A script tag split across writes is held back until it is complete.
+type nonceWriter struct {
+	w       http.ResponseWriter
+	nonce   string
+	pending []byte
+}
+
+func (w *nonceWriter) Write(p []byte) (int, error) {
+	w.pending = append(w.pending, p...)
+	n := completeTags(w.pending)
+	if n > 0 {
+		if _, err := w.w.Write([]byte(applyNonce(string(w.pending[:n]), w.nonce))); err != nil {
+			return 0, err
+		}
+		w.pending = append(w.pending[:0], w.pending[n:]...)
+	}
+	return len(p), nil
+}
+
+// writePending writes anything still held back once the stream has ended.
+func (w *nonceWriter) writePending() error {
+	if len(w.pending) == 0 {
+		return nil
+	}
+	_, err := w.w.Write([]byte(applyNonce(string(w.pending), w.nonce)))
+	w.pending = nil
+	return err
+}
+
+// Flush flushes the underlying response writer, if it supports it.
+func (w *nonceWriter) Flush() {
+	if flusher, ok := w.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// completeTags returns the length of the prefix of b that can be written, i.e.
+// b without a trailing unterminated tag that is, or may become, a script tag.
+func completeTags(b []byte) int {
+	idx := bytes.LastIndexByte(b, '<')
+	if idx < 0 || bytes.IndexByte(b[idx:], '>') >= 0 {
+		return len(b)
+	}
+
+	const open = "<script"
+	tail := bytes.ToLower(b[idx:])
+	if (len(tail) < len(open) && bytes.HasPrefix([]byte(open), tail)) || bytes.HasPrefix(tail, []byte(open)) {
+		return idx
+	}
+	return len(b)
+}
@@ -0,0 +1,268 @@
+package inertia
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+)
+
+// TemplateData is passed to the root template on full page loads.
+//
+// A minimal root template looks like:
+//
+//	<head>{{ .InertiaHead }}</head>
+//	<body>{{ .Inertia }}</body>
+//
+// Templates may also build the app element themselves with
+// <div id="app" data-page="{{ .Page }}"></div>.
+type TemplateData struct {
+	Page        string        // JSON-encoded page object
+	Component   string        // Rendered component name
+	AssetURL    string        // Config.AssetURL
+	InertiaHead template.HTML // Head tags for this response
+	Inertia     template.HTML // SSR body, or the app element with its data-page attribute
+	Nonce       string        // CSP nonce for inline scripts, see InertiaContext.Nonce
+}
+
+// templateCache caches parsed root templates by name.
+type templateCache struct {
+	mu        sync.RWMutex
+	templates map[string]*template.Template
+}
+
+// get returns the cached template for name, parsing it with load on first use.
+func (c *templateCache) get(name string, load func(string) (*template.Template, error)) (*template.Template, error) {
+	c.mu.RLock()
+	tmpl, ok := c.templates[name]
+	c.mu.RUnlock()
+	if ok {
+		return tmpl, nil
+	}
+
+	tmpl, err := load(name)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	if c.templates == nil {
+		c.templates = make(map[string]*template.Template)
+	}
+	c.templates[name] = tmpl
+	c.mu.Unlock()
+
+	return tmpl, nil
+}
+
+// rootTemplate returns the parsed root template registered under name in
+// Config.RootViews, falling back to Config.TemplateName or Config.RootView.
+func (i *Inertia) rootTemplate(name string) (*template.Template, error) {
+	file := i.config.RootView
+	if i.config.TemplateFS != nil && i.config.TemplateName != "" {
+		file = i.config.TemplateName
+	}
+	if view, ok := i.config.RootViews[name]; ok && name != "" {
+		file = view
+	}
+	return i.templates.get(file, i.parseTemplate)
+}
+
+// loadRootViews parses and caches RootView and every template in RootViews.
+func (i *Inertia) loadRootViews() error {
+	if _, err := i.rootTemplate(""); err != nil {
+		return err
+	}
+	for name := range i.config.RootViews {
+		if _, err := i.rootTemplate(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseTemplate parses a root template from Config.TemplateFS, or from the OS
+// filesystem when it is nil.
+func (i *Inertia) parseTemplate(file string) (*template.Template, error) {
+	if i.config.TemplateFS == nil {
+		tmpl, err := template.ParseFiles(file)
+		if err != nil {
+			return nil, fmt.Errorf("inertia: failed to parse root template %q: %w", file, err)
+		}
+		return tmpl, nil
+	}
+
+	content, err := fs.ReadFile(i.config.TemplateFS, file)
+	if err != nil {
+		return nil, fmt.Errorf("inertia: failed to read root template %q: %w", file, err)
+	}
+	tmpl, err := template.New(path.Base(file)).Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("inertia: failed to parse root template %q: %w", file, err)
+	}
+	return tmpl, nil
+}
+
+// wantsHTML reports whether the request is a full page load from a browser,
+// which is answered with the root template rather than the page JSON.
+func (i *Inertia) wantsHTML(r *http.Request) bool {
+	return !i.isInertiaRequest(r) && strings.Contains(r.Header.Get("Accept"), "text/html")
+}
+
+// wantsPropsJSON reports whether the request comes from an API client that
+// accepts JSON but is neither an Inertia request nor a browser page load.
+func (i *Inertia) wantsPropsJSON(r *http.Request) bool {
+	accept := r.Header.Get("Accept")
+	return !i.isInertiaRequest(r) && !strings.Contains(accept, "text/html") &&
+		strings.Contains(accept, "application/json")
+}
+
+// Head adds tags (e.g. <title> or <meta>) to the <head> of the next full page load.
+// Head tags are ignored for Inertia navigations, which only receive page JSON.
+func (ic *InertiaContext) Head(tags ...string) *InertiaContext {
+	ic.headTags = append(ic.headTags, tags...)
+	return ic
+}
+
+// RootView selects the root template registered in Config.RootViews under name for
+// full page loads rendered through this context. Unknown names use Config.RootView.
+func (ic *InertiaContext) RootView(name string) *InertiaContext {
+	ic.rootView = name
+	return ic
+}
+
+// renderHTML renders the page into the root template for a full page load.
+func (ic *InertiaContext) renderHTML(page *Page, status int) error {
+	req := ic.ctx.Request()
+	res := ic.ctx.Response()
+
+	tmpl, err := ic.mgr.rootTemplate(ic.rootView)
+	if err != nil {
+		return err
+	}
+
+	if req.Method == http.MethodHead {
+		// Skip the template and SSR; only the headers are sent
+		res.Header().Set("Content-Type", "text/html; charset=utf-8")
+		ic.writeServerTiming(res.Header())
+		res.WriteHeader(status)
+		return nil
+	}
+
+	done := ic.timePhase(timingEncode)
+	pageJSON, err := ic.mgr.encodeJSON(ic.clientPage(page))
+	done()
+	if err != nil {
+		return fmt.Errorf("inertia: failed to encode page: %w", err)
+	}
+
+	head := append(ic.mgr.preloadTags(page.Component), ic.headTags...)
+	body := appElement(pageJSON)
+
+	if streamer, ok := ic.mgr.ssrStreamer(req, page); ok {
+		return ic.streamHTML(tmpl, streamer, page, pageJSON, head, status)
+	}
+
+	if ic.mgr.shouldSSR(req) {
+		done := ic.timePhase(timingSSR)
+		result, err := ic.mgr.renderSSRWith(req.Context(), ic.clientPage(page), ic.serverProps(page))
+		done()
+		switch {
+		case errors.Is(err, ErrSSRFailed):
+			// Keep the client-rendered body so the page still boots
+			ic.mgr.logf("inertia: rendering %s without SSR: %v", page.Component, err)
+		case err != nil:
+			return err
+		default:
+			ssrHead, ssrBody := parseSSRResult(result)
+			head = append(ssrHead, head...)
+			if ssrBody != "" {
+				body = ssrBody
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, ic.templateData(page, pageJSON, head, body)); err != nil {
+		return fmt.Errorf("inertia: failed to execute root template: %w", err)
+	}
+
+	res.Header().Set("Content-Type", "text/html; charset=utf-8")
+	ic.writeServerTiming(res.Header())
+	res.WriteHeader(status)
+	_, err = buf.WriteTo(res)
+	return err
+}
+
+// templateData returns the root template data for the page.
+func (ic *InertiaContext) templateData(page *Page, pageJSON []byte, head []string, body string) TemplateData {
+	// Head tags are set by the application.
+	inertiaHead := template.HTML(applyNonce(strings.Join(head, "\n"), ic.nonce)) //nolint:gosec
+	// Body is escaped or produced by the SSR bundle.
+	inertia := template.HTML(applyNonce(body, ic.nonce)) //nolint:gosec
+
+	return TemplateData{
+		Page:        string(pageJSON),
+		Component:   page.Component,
+		AssetURL:    ic.mgr.config.AssetURL,
+		InertiaHead: inertiaHead,
+		Inertia:     inertia,
+		Nonce:       ic.nonce,
+	}
+}
+
+// pageElement returns the empty app element carrying page in its data-page
+// attribute, from which the client boots without SSR.
+func (i *Inertia) pageElement(page *Page) (string, error) {
+	pageJSON, err := i.encodeJSON(page)
+	if err != nil {
+		return "", fmt.Errorf("inertia: failed to encode page: %w", err)
+	}
+	return appElement(pageJSON), nil
+}
+
+// appElement returns the app element for the encoded page.
+func appElement(pageJSON []byte) string {
+	return fmt.Sprintf(`<div id="app" data-page="%s"></div>`, html.EscapeString(string(pageJSON)))
+}
+
+// shouldSSR reports whether a full page load should be server-side rendered.
+func (i *Inertia) shouldSSR(r *http.Request) bool {
+	if !i.config.SSR || i.ssrRenderer == nil {
+		return false
+	}
+	return i.config.SSRForRequests == nil || i.config.SSRForRequests(r)
+}
+
+// parseSSRResult splits an SSR result into head tags and body HTML.
+// Bundles may return plain HTML or an object with "head" and "body"/"html" keys.
+func parseSSRResult(result string) (head []string, body string) {
+	var obj struct {
+		Head json.RawMessage `json:"head"`
+		Body string          `json:"body"`
+		HTML string          `json:"html"`
+	}
+	if !strings.HasPrefix(strings.TrimSpace(result), "{") || json.Unmarshal([]byte(result), &obj) != nil {
+		return nil, result
+	}
+
+	var tags []string
+	if err := json.Unmarshal(obj.Head, &tags); err != nil {
+		var tag string
+		if json.Unmarshal(obj.Head, &tag) == nil && tag != "" {
+			tags = []string{tag}
+		}
+	}
+
+	if obj.Body != "" {
+		return tags, obj.Body
+	}
+	return tags, obj.HTML
+}
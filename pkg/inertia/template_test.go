@@ -0,0 +1,644 @@
+package inertia_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/toutaio/toutago-inertia/pkg/inertia"
+	"github.com/toutaio/toutago-inertia/pkg/ssr"
+)
+
+const testRootTemplate = `<!DOCTYPE html>
+<html>
+<head>
+{{ .InertiaHead }}
+</head>
+<body>
+{{ .Inertia }}
+</body>
+</html>`
+
+// writeRootTemplate writes a root template to a temporary directory and returns its path.
+func writeRootTemplate(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+// newFullLoadRequest creates a browser-style full page load request.
+func newFullLoadRequest(url string) *http.Request {
+	req := httptest.NewRequest("GET", url, http.NoBody)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	return req
+}
+
+func TestInertiaContext_RenderHTML(t *testing.T) {
+	mgr, err := inertia.New(inertia.Config{
+		RootView: writeRootTemplate(t, "app.html", testRootTemplate),
+		Version:  "1.0.0",
+	})
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	ic := inertia.NewContext(NewMockContext(w, newFullLoadRequest("/users")), mgr)
+
+	err = ic.Render("Users/Index", map[string]interface{}{
+		"users": []string{"Alice"},
+	})
+	require.NoError(t, err)
+
+	body := w.Body.String()
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, body, `<div id="app" data-page="`)
+	assert.Contains(t, body, `&#34;component&#34;:&#34;Users/Index&#34;`)
+	assert.Contains(t, body, "Alice")
+}
+
+func TestInertiaContext_RenderHTML_MissingTemplate(t *testing.T) {
+	mgr, err := inertia.New(inertia.Config{
+		RootView: filepath.Join(t.TempDir(), "missing.html"),
+	})
+	require.NoError(t, err)
+
+	ic := inertia.NewContext(NewMockContext(httptest.NewRecorder(), newFullLoadRequest("/")), mgr)
+	err = ic.Render("Home", map[string]interface{}{})
+	assert.Error(t, err)
+}
+
+func TestInertiaContext_Head(t *testing.T) {
+	mgr, err := inertia.New(inertia.Config{
+		RootView: writeRootTemplate(t, "app.html", testRootTemplate),
+		Version:  "1.0.0",
+	})
+	require.NoError(t, err)
+
+	t.Run("head tags appear in full page loads", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ic := inertia.NewContext(NewMockContext(w, newFullLoadRequest("/posts/1")), mgr)
+
+		err := ic.
+			Head(`<title>Hello World</title>`).
+			Head(`<link rel="canonical" href="https://example.com/posts/1">`, `<meta property="og:title" content="Hello">`).
+			Render("Posts/Show", map[string]interface{}{})
+		require.NoError(t, err)
+
+		body := w.Body.String()
+		assert.Contains(t, body, "<title>Hello World</title>")
+		assert.Contains(t, body, `<link rel="canonical" href="https://example.com/posts/1">`)
+		assert.Contains(t, body, `<meta property="og:title" content="Hello">`)
+	})
+
+	t.Run("head tags are ignored for Inertia navigations", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/posts/1", http.NoBody)
+		req.Header.Set("X-Inertia", "true")
+		w := httptest.NewRecorder()
+		ic := inertia.NewContext(NewMockContext(w, req), mgr)
+
+		err := ic.Head(`<title>Hello World</title>`).Render("Posts/Show", map[string]interface{}{})
+		require.NoError(t, err)
+
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		assert.NotContains(t, w.Body.String(), "Hello World")
+	})
+}
+
+func TestInertiaContext_Head_MergesSSRHead(t *testing.T) {
+	renderer, err := ssr.NewRenderer(&ssr.Config{PoolSize: 1})
+	require.NoError(t, err)
+	defer renderer.Close()
+
+	require.NoError(t, renderer.LoadBundle(`
+		global.render = function(page) {
+			return {
+				head: ['<title>' + page.component + '</title>'],
+				body: '<div id="app">rendered on the server</div>'
+			};
+		};
+	`))
+
+	mgr, err := inertia.New(inertia.Config{
+		RootView: writeRootTemplate(t, "app.html", testRootTemplate),
+		SSR:      true,
+	})
+	require.NoError(t, err)
+	mgr.SetSSRRenderer(renderer)
+
+	w := httptest.NewRecorder()
+	ic := inertia.NewContext(NewMockContext(w, newFullLoadRequest("/about")), mgr)
+
+	err = ic.Head(`<meta name="description" content="About us">`).Render("About", map[string]interface{}{})
+	require.NoError(t, err)
+
+	body := w.Body.String()
+	assert.Contains(t, body, "<title>About</title>\n<meta name=\"description\" content=\"About us\">")
+	assert.Contains(t, body, `<div id="app">rendered on the server</div>`)
+}
+
+func TestInertiaContext_SSRErrorFallsBackToClient(t *testing.T) {
+	renderer, err := ssr.NewRenderer(&ssr.Config{PoolSize: 1})
+	require.NoError(t, err)
+	defer renderer.Close()
+
+	require.NoError(t, renderer.LoadBundle(`
+		global.render = function(page) {
+			throw new Error('Render failed');
+		};
+	`))
+
+	mgr, err := inertia.New(inertia.Config{
+		RootView: writeRootTemplate(t, "app.html", testRootTemplate),
+		SSR:      true,
+	})
+	require.NoError(t, err)
+	mgr.SetSSRRenderer(renderer)
+	logger := &recordingLogger{}
+	mgr.SetLogger(logger)
+
+	w := httptest.NewRecorder()
+	ic := inertia.NewContext(NewMockContext(w, newFullLoadRequest("/about")), mgr)
+	require.NoError(t, ic.Render("About", map[string]interface{}{"title": "About us"}))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `<div id="app" data-page="`)
+	assert.Contains(t, w.Body.String(), `&#34;component&#34;:&#34;About&#34;`)
+	require.Len(t, logger.messages, 1)
+	assert.Contains(t, logger.messages[0], "without SSR")
+}
+
+// streamingRenderer records the response written before it streams the body.
+type streamingRenderer struct {
+	res     *httptest.ResponseRecorder
+	err     error
+	shell   string
+	flushed bool
+}
+
+func (r *streamingRenderer) RenderToString(context.Context, map[string]interface{}) (string, error) {
+	return "", errors.New("not streamed")
+}
+
+func (r *streamingRenderer) RenderStream(_ context.Context, page map[string]interface{}, w io.Writer) error {
+	r.shell = r.res.Body.String()
+	r.flushed = r.res.Flushed
+	if r.err != nil {
+		return r.err
+	}
+	_, err := fmt.Fprintf(w, "<main>%s</main>", page["component"])
+	return err
+}
+
+func TestInertiaContext_StreamSSR(t *testing.T) {
+	newManager := func(t *testing.T, renderer inertia.SSRRenderer) *inertia.Inertia {
+		t.Helper()
+		mgr, err := inertia.New(inertia.Config{
+			RootView:  writeRootTemplate(t, "app.html", `<html><head>{{ .InertiaHead }}</head><body>{{ .Inertia }}</body></html>`),
+			SSR:       true,
+			StreamSSR: true,
+		})
+		require.NoError(t, err)
+		mgr.SetSSRRenderer(renderer)
+		return mgr
+	}
+
+	t.Run("flushes the shell before the body", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		renderer := &streamingRenderer{res: w}
+		mgr := newManager(t, renderer)
+
+		ic := inertia.NewContext(NewMockContext(w, newFullLoadRequest("/")), mgr)
+		ic.Head("<title>Home</title>")
+		require.NoError(t, ic.Render("Home", nil))
+
+		assert.Equal(t, "<html><head><title>Home</title></head><body>", renderer.shell)
+		assert.True(t, renderer.flushed)
+		assert.Equal(t, "<html><head><title>Home</title></head><body><main>Home</main></body></html>", w.Body.String())
+	})
+
+	t.Run("falls back to the client when streaming fails", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		mgr := newManager(t, &streamingRenderer{res: w, err: errors.New("boom")})
+		logger := &recordingLogger{}
+		mgr.SetLogger(logger)
+
+		ic := inertia.NewContext(NewMockContext(w, newFullLoadRequest("/")), mgr)
+		require.NoError(t, ic.Render("Home", nil))
+
+		assert.Contains(t, w.Body.String(), `<body><div id="app" data-page="`)
+		assert.True(t, strings.HasSuffix(w.Body.String(), "</div></body></html>"))
+		require.Len(t, logger.messages, 1)
+		assert.Contains(t, logger.messages[0], "boom")
+	})
+}
+
+// chunkedRenderer streams its chunks in separate writes.
+type chunkedRenderer struct {
+	chunks []string
+}
+
+func (r *chunkedRenderer) RenderToString(context.Context, map[string]interface{}) (string, error) {
+	return "", errors.New("not streamed")
+}
+
+func (r *chunkedRenderer) RenderStream(_ context.Context, _ map[string]interface{}, w io.Writer) error {
+	for _, chunk := range r.chunks {
+		if _, err := io.WriteString(w, chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestInertiaContext_StreamSSR_Nonce(t *testing.T) {
+	mgr, err := inertia.New(inertia.Config{
+		RootView:  writeRootTemplate(t, "app.html", `<html><body>{{ .Inertia }}</body></html>`),
+		SSR:       true,
+		StreamSSR: true,
+	})
+	require.NoError(t, err)
+	mgr.SetSSRRenderer(&chunkedRenderer{chunks: []string{
+		"<main>1 <", " 2</main><scr", "ipt>a()</script><SCRIPT", ` src="/b.js"></SCRIPT><`,
+	}})
+
+	w := httptest.NewRecorder()
+	ic := inertia.NewContext(NewMockContext(w, newFullLoadRequest("/")), mgr)
+	require.NoError(t, ic.Nonce("abc123").Render("Home", nil))
+
+	assert.Equal(t, `<html><body><main>1 < 2</main><script nonce="abc123">a()</script>`+
+		`<SCRIPT nonce="abc123" src="/b.js"></SCRIPT><</body></html>`, w.Body.String())
+}
+
+func TestInertiaContext_RootView(t *testing.T) {
+	mgr, err := inertia.New(inertia.Config{
+		RootView: writeRootTemplate(t, "app.html", `<html class="app">{{ .Inertia }}</html>`),
+		RootViews: map[string]string{
+			"marketing": writeRootTemplate(t, "marketing.html", `<html class="marketing">{{ .Inertia }}</html>`),
+		},
+		Version: "1.0.0",
+	})
+	require.NoError(t, err)
+
+	render := func(rootView string) string {
+		w := httptest.NewRecorder()
+		ic := inertia.NewContext(NewMockContext(w, newFullLoadRequest("/")), mgr)
+		if rootView != "" {
+			ic.RootView(rootView)
+		}
+		require.NoError(t, ic.Render("Home", map[string]interface{}{}))
+		return w.Body.String()
+	}
+
+	t.Run("default root view", func(t *testing.T) {
+		body := render("")
+		assert.Contains(t, body, `<html class="app">`)
+		assert.Contains(t, body, `data-page=`)
+	})
+
+	t.Run("named root view", func(t *testing.T) {
+		body := render("marketing")
+		assert.Contains(t, body, `<html class="marketing">`)
+		assert.Contains(t, body, `data-page=`)
+	})
+
+	t.Run("unknown root view falls back to default", func(t *testing.T) {
+		assert.Contains(t, render("missing"), `<html class="app">`)
+	})
+}
+
+func TestInertiaContext_SSRForRequests(t *testing.T) {
+	renderer, err := ssr.NewRenderer(&ssr.Config{PoolSize: 1})
+	require.NoError(t, err)
+	defer renderer.Close()
+
+	require.NoError(t, renderer.LoadBundle(`
+		global.render = function(page) {
+			return '<div id="app" data-page="ssr">rendered ' + page.component + ' on the server</div>';
+		};
+	`))
+
+	mgr, err := inertia.New(inertia.Config{
+		RootView: writeRootTemplate(t, "app.html", testRootTemplate),
+		SSR:      true,
+		SSRForRequests: func(r *http.Request) bool {
+			return strings.Contains(r.UserAgent(), "Googlebot")
+		},
+	})
+	require.NoError(t, err)
+	mgr.SetSSRRenderer(renderer)
+
+	render := func(userAgent string) string {
+		req := newFullLoadRequest("/about")
+		req.Header.Set("User-Agent", userAgent)
+		w := httptest.NewRecorder()
+		ic := inertia.NewContext(NewMockContext(w, req), mgr)
+		require.NoError(t, ic.Render("About", map[string]interface{}{}))
+		return w.Body.String()
+	}
+
+	t.Run("crawler gets server-rendered HTML", func(t *testing.T) {
+		body := render("Mozilla/5.0 (compatible; Googlebot/2.1)")
+		assert.Contains(t, body, "rendered About on the server")
+		assert.Contains(t, body, `data-page=`)
+	})
+
+	t.Run("browser gets the client-rendered shell", func(t *testing.T) {
+		body := render("Mozilla/5.0 (X11; Linux x86_64) Firefox/130.0")
+		assert.NotContains(t, body, "on the server")
+		assert.Contains(t, body, `<div id="app" data-page="`)
+		assert.Contains(t, body, `&#34;component&#34;:&#34;About&#34;`)
+	})
+}
+
+func TestInertiaContext_Nonce(t *testing.T) {
+	rootTemplate := `<html><head>{{ .InertiaHead }}</head><body>{{ .Inertia }}` +
+		`<script nonce="{{ .Nonce }}" src="/app.js"></script></body></html>`
+
+	t.Run("nonce is applied to scripts and props", func(t *testing.T) {
+		mgr, err := inertia.New(inertia.Config{
+			RootView: writeRootTemplate(t, "app.html", rootTemplate),
+			Version:  "1.0.0",
+		})
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		ic := inertia.NewContext(NewMockContext(w, newFullLoadRequest("/")), mgr)
+
+		err = ic.Nonce("abc123").
+			Head(`<script>window.config = {};</script>`).
+			Render("Home", map[string]interface{}{})
+		require.NoError(t, err)
+
+		body := w.Body.String()
+		assert.Contains(t, body, `<script nonce="abc123">window.config = {};</script>`)
+		assert.Contains(t, body, `<script nonce="abc123" src="/app.js"></script>`)
+		assert.Contains(t, body, `&#34;nonce&#34;:&#34;abc123&#34;`)
+	})
+
+	t.Run("generated nonce", func(t *testing.T) {
+		mgr, err := inertia.New(inertia.Config{
+			RootView:      writeRootTemplate(t, "app.html", rootTemplate),
+			Version:       "1.0.0",
+			GenerateNonce: true,
+		})
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		ic := inertia.NewContext(NewMockContext(w, newFullLoadRequest("/")), mgr)
+
+		nonce := ic.CSPNonce()
+		require.NotEmpty(t, nonce)
+		assert.Equal(t, nonce, ic.CSPNonce(), "nonce should be stable within a request")
+
+		require.NoError(t, ic.Render("Home", map[string]interface{}{}))
+
+		body := w.Body.String()
+		assert.Contains(t, body, `<script nonce="`+nonce+`" src="/app.js"></script>`)
+		assert.Contains(t, body, `&#34;nonce&#34;:&#34;`+nonce+`&#34;`)
+	})
+
+	t.Run("no nonce is generated for Inertia navigations", func(t *testing.T) {
+		mgr, err := inertia.New(inertia.Config{
+			RootView:      "app.html",
+			Version:       "1.0.0",
+			GenerateNonce: true,
+		})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest("GET", "/", http.NoBody)
+		req.Header.Set("X-Inertia", "true")
+		w := httptest.NewRecorder()
+		ic := inertia.NewContext(NewMockContext(w, req), mgr)
+
+		require.NoError(t, ic.Render("Home", map[string]interface{}{}))
+		assert.NotContains(t, w.Body.String(), `"nonce"`)
+	})
+}
+
+func TestInertiaContext_ServerOnly(t *testing.T) {
+	renderer, err := ssr.NewRenderer(&ssr.Config{PoolSize: 1})
+	require.NoError(t, err)
+	defer renderer.Close()
+
+	require.NoError(t, renderer.LoadBundle(`
+		global.render = function(page, server) {
+			var html = '<div id="app" data-page="' + JSON.stringify(page).replace(/"/g, '&quot;') + '">';
+			return html + 'csrf:' + server.csrf + '</div>';
+		};
+	`))
+
+	mgr, err := inertia.New(inertia.Config{
+		RootView: writeRootTemplate(t, "app.html", `<html>{{ .Inertia }}</html>`),
+		Version:  "1.0.0",
+		SSR:      true,
+	})
+	require.NoError(t, err)
+	mgr.SetSSRRenderer(renderer)
+
+	props := func() map[string]interface{} {
+		return map[string]interface{}{"csrf": "s3cr3t", "title": "Login"}
+	}
+
+	t.Run("server-only prop reaches SSR but not the page it serializes", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ic := inertia.NewContext(NewMockContext(w, newFullLoadRequest("/login")), mgr)
+
+		require.NoError(t, ic.ServerOnly("csrf").Render("Auth/Login", props()))
+
+		body := w.Body.String()
+		assert.Contains(t, body, "csrf:s3cr3t")
+		assert.Contains(t, body, "Login")
+		assert.NotContains(t, body, "&quot;csrf&quot;", "server-only prop leaked into the SSR page:\n%s", body)
+		assert.Equal(t, 1, strings.Count(body, "s3cr3t"), "server-only prop leaked into data-page:\n%s", body)
+	})
+
+	t.Run("server-only prop is absent from the SSR fallback", func(t *testing.T) {
+		failing, err := inertia.New(inertia.Config{
+			RootView: writeRootTemplate(t, "fallback.html", `<html>{{ .Inertia }}</html>`),
+			Version:  "1.0.0",
+			SSR:      true,
+		})
+		require.NoError(t, err)
+		failing.SetSSRRenderer(&streamingRenderer{res: httptest.NewRecorder()})
+		failing.SetLogger(&recordingLogger{})
+
+		w := httptest.NewRecorder()
+		ic := inertia.NewContext(NewMockContext(w, newFullLoadRequest("/login")), failing)
+		require.NoError(t, ic.ServerOnly("csrf").Render("Auth/Login", props()))
+
+		assert.Contains(t, w.Body.String(), "Login")
+		assert.NotContains(t, w.Body.String(), "s3cr3t")
+	})
+
+	t.Run("server-only prop is stripped from JSON navigations", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/login", http.NoBody)
+		req.Header.Set("X-Inertia", "true")
+		w := httptest.NewRecorder()
+		ic := inertia.NewContext(NewMockContext(w, req), mgr)
+
+		require.NoError(t, ic.ServerOnly("csrf").Render("Auth/Login", props()))
+
+		var page inertia.Page
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		assert.NotContains(t, page.Props, "csrf")
+		assert.Equal(t, "Login", page.Props["title"])
+	})
+}
+
+func TestInertia_SetComponentManifest(t *testing.T) {
+	manifest := writeRootTemplate(t, "manifest.json", `{
+		"resources/js/app.js": {"file": "assets/app-1a2b.js", "isEntry": true, "imports": ["_vendor-9z8y.js"]},
+		"resources/js/Pages/Users/Index.vue": {"file": "assets/Index-3c4d.js", "isDynamicEntry": true, "imports": ["_vendor-9z8y.js", "_Table-5e6f.js"]},
+		"resources/js/Pages/Users/Show.vue": {"file": "assets/Show-7a8b.js", "isDynamicEntry": true},
+		"_Table-5e6f.js": {"file": "assets/Table-5e6f.js", "imports": ["_vendor-9z8y.js"]},
+		"_vendor-9z8y.js": {"file": "assets/vendor-9z8y.js"}
+	}`)
+
+	mgr, err := inertia.New(inertia.Config{
+		RootView: writeRootTemplate(t, "app.html", testRootTemplate),
+		Version:  "1.0.0",
+		AssetURL: "/build/",
+	})
+	require.NoError(t, err)
+	require.NoError(t, mgr.SetComponentManifest(manifest))
+
+	render := func(req *http.Request, component string) string {
+		w := httptest.NewRecorder()
+		ic := inertia.NewContext(NewMockContext(w, req), mgr)
+		require.NoError(t, ic.Render(component, map[string]interface{}{}))
+		return w.Body.String()
+	}
+
+	t.Run("full load preloads component chunks", func(t *testing.T) {
+		body := render(newFullLoadRequest("/users"), "Users/Index")
+
+		assert.Contains(t, body, `<link rel="modulepreload" href="/build/assets/Index-3c4d.js">
+<link rel="modulepreload" href="/build/assets/vendor-9z8y.js">
+<link rel="modulepreload" href="/build/assets/Table-5e6f.js">`)
+		assert.Equal(t, 1, strings.Count(body, "vendor-9z8y.js"), "shared imports are preloaded once")
+		assert.NotContains(t, body, "Show-7a8b.js")
+	})
+
+	t.Run("unknown component has no preloads", func(t *testing.T) {
+		assert.NotContains(t, render(newFullLoadRequest("/"), "Missing"), "modulepreload")
+	})
+
+	t.Run("Inertia navigations are unaffected", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/users", http.NoBody)
+		req.Header.Set("X-Inertia", "true")
+		assert.NotContains(t, render(req, "Users/Index"), "modulepreload")
+	})
+
+	t.Run("invalid manifest", func(t *testing.T) {
+		assert.Error(t, mgr.SetComponentManifest(writeRootTemplate(t, "bad.json", `not json`)))
+		assert.Error(t, mgr.SetComponentManifest(filepath.Join(t.TempDir(), "missing.json")))
+	})
+}
+
+func TestNew_ValidateRootView(t *testing.T) {
+	t.Run("missing file", func(t *testing.T) {
+		_, err := inertia.New(inertia.Config{
+			RootView:         filepath.Join(t.TempDir(), "missing.html"),
+			ValidateRootView: true,
+		})
+		require.Error(t, err)
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+		assert.Contains(t, err.Error(), "missing.html")
+	})
+
+	t.Run("unparseable template", func(t *testing.T) {
+		_, err := inertia.New(inertia.Config{
+			RootView:         writeRootTemplate(t, "app.html", `{{ .Inertia `),
+			ValidateRootView: true,
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("missing named root view", func(t *testing.T) {
+		_, err := inertia.New(inertia.Config{
+			RootView:         writeRootTemplate(t, "app.html", testRootTemplate),
+			RootViews:        map[string]string{"admin": filepath.Join(t.TempDir(), "admin.html")},
+			ValidateRootView: true,
+		})
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+	})
+
+	t.Run("valid template", func(t *testing.T) {
+		mgr, err := inertia.New(inertia.Config{
+			RootView:         writeRootTemplate(t, "app.html", testRootTemplate),
+			ValidateRootView: true,
+		})
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		require.NoError(t, inertia.NewContext(NewMockContext(w, newFullLoadRequest("/")), mgr).Render("Home", nil))
+		assert.Contains(t, w.Body.String(), `data-page=`)
+	})
+
+	t.Run("off by default", func(t *testing.T) {
+		_, err := inertia.New(inertia.Config{RootView: filepath.Join(t.TempDir(), "missing.html")})
+		assert.NoError(t, err)
+	})
+}
+
+func TestInertiaContext_TemplateFS(t *testing.T) {
+	templates := fstest.MapFS{
+		"views/app.html":   {Data: []byte(`<html class="embedded">{{ .Inertia }}</html>`)},
+		"views/admin.html": {Data: []byte(`<html class="admin">{{ .Inertia }}</html>`)},
+	}
+
+	render := func(t *testing.T, config inertia.Config, rootView string) string {
+		t.Helper()
+
+		mgr, err := inertia.New(config)
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		ic := inertia.NewContext(NewMockContext(w, newFullLoadRequest("/")), mgr)
+		require.NoError(t, ic.RootView(rootView).Render("Home", map[string]interface{}{}))
+		return w.Body.String()
+	}
+
+	t.Run("TemplateName", func(t *testing.T) {
+		body := render(t, inertia.Config{TemplateFS: templates, TemplateName: "views/app.html"}, "")
+		assert.Contains(t, body, `<html class="embedded">`)
+		assert.Contains(t, body, `data-page=`)
+	})
+
+	t.Run("RootView and RootViews within the filesystem", func(t *testing.T) {
+		config := inertia.Config{
+			TemplateFS: templates,
+			RootView:   "views/app.html",
+			RootViews:  map[string]string{"admin": "views/admin.html"},
+		}
+		assert.Contains(t, render(t, config, ""), `<html class="embedded">`)
+		assert.Contains(t, render(t, config, "admin"), `<html class="admin">`)
+	})
+
+	t.Run("missing template", func(t *testing.T) {
+		_, err := inertia.New(inertia.Config{
+			TemplateFS:       templates,
+			TemplateName:     "views/missing.html",
+			ValidateRootView: true,
+		})
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+	})
+
+	t.Run("TemplateName requires TemplateFS", func(t *testing.T) {
+		_, err := inertia.New(inertia.Config{TemplateName: "views/app.html"})
+		assert.ErrorIs(t, err, inertia.ErrRootViewRequired)
+	})
+}
@@ -0,0 +1,57 @@
+package inertia
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Render phases reported in the Server-Timing header.
+const (
+	timingShared = "inertia-shared"
+	timingLazy   = "inertia-lazy"
+	timingSSR    = "inertia-ssr"
+	timingEncode = "inertia-encode"
+)
+
+// timingMetric is the accumulated duration of one render phase.
+type timingMetric struct {
+	name string
+	dur  time.Duration
+}
+
+// timePhase starts timing a render phase and returns the function that stops
+// it. Durations of repeated phases add up. It is a no-op unless
+// Config.ServerTiming is set.
+func (ic *InertiaContext) timePhase(name string) func() {
+	if !ic.mgr.config.ServerTiming {
+		return func() {}
+	}
+
+	start := time.Now()
+	return func() {
+		elapsed := time.Since(start)
+		for idx := range ic.timings {
+			if ic.timings[idx].name == name {
+				ic.timings[idx].dur += elapsed
+				return
+			}
+		}
+		ic.timings = append(ic.timings, timingMetric{name: name, dur: elapsed})
+	}
+}
+
+// writeServerTiming sets the Server-Timing header from the timed render phases,
+// with durations in milliseconds, e.g. "inertia-shared;dur=0.12".
+func (ic *InertiaContext) writeServerTiming(h http.Header) {
+	if len(ic.timings) == 0 {
+		return
+	}
+
+	metrics := make([]string, len(ic.timings))
+	for idx, metric := range ic.timings {
+		metrics[idx] = fmt.Sprintf("%s;dur=%.2f", metric.name, float64(metric.dur.Microseconds())/1000)
+	}
+	h.Set("Server-Timing", strings.Join(metrics, ", "))
+}
@@ -0,0 +1,58 @@
+package inertia_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/toutaio/toutago-inertia/pkg/inertia"
+)
+
+func TestInertiaContext_ServerTiming(t *testing.T) {
+	render := func(t *testing.T, config inertia.Config, req *http.Request) *httptest.ResponseRecorder {
+		t.Helper()
+
+		mgr, err := inertia.New(config)
+		require.NoError(t, err)
+		mgr.Share("app_name", "My App")
+
+		w := httptest.NewRecorder()
+		ic := inertia.NewContext(NewMockContext(w, req), mgr)
+		ic.Lazy("stats", func() interface{} { return 42 })
+		require.NoError(t, ic.Render("Dashboard", map[string]interface{}{}))
+		return w
+	}
+
+	t.Run("inertia request", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/dashboard", http.NoBody)
+		req.Header.Set("X-Inertia", "true")
+		w := render(t, inertia.Config{RootView: "app.html", Version: "1.0.0", ServerTiming: true}, req)
+
+		timing := w.Header().Get("Server-Timing")
+		assert.Regexp(t, `^inertia-shared;dur=\d+\.\d{2}, inertia-lazy;dur=\d+\.\d{2}, inertia-encode;dur=\d+\.\d{2}$`, timing)
+	})
+
+	t.Run("full page load", func(t *testing.T) {
+		config := inertia.Config{
+			RootView:     writeRootTemplate(t, "app.html", testRootTemplate),
+			Version:      "1.0.0",
+			ServerTiming: true,
+		}
+		w := render(t, config, newFullLoadRequest("/dashboard"))
+
+		timing := w.Header().Get("Server-Timing")
+		for _, metric := range []string{"inertia-shared;dur=", "inertia-lazy;dur=", "inertia-encode;dur="} {
+			assert.Contains(t, timing, metric)
+		}
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/dashboard", http.NoBody)
+		req.Header.Set("X-Inertia", "true")
+		w := render(t, inertia.Config{RootView: "app.html", Version: "1.0.0"}, req)
+
+		assert.Empty(t, w.Header().Get("Server-Timing"))
+	})
+}
@@ -0,0 +1,151 @@
+package inertia
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// RedactedPlaceholder replaces prop values removed by RedactFields.
+const RedactedPlaceholder = "[REDACTED]"
+
+// PropTransformer rewrites the resolved page props before they are serialized.
+// It may replace, add or delete top-level keys; nested values should be copied
+// rather than modified, since they may be shared between requests.
+type PropTransformer func(props map[string]interface{}) error
+
+// AddPropTransformer registers a transformer that runs on every rendered page,
+// after shared data is merged and lazy props are resolved. Transformers run in
+// the order they were added.
+func (i *Inertia) AddPropTransformer(transformer PropTransformer) {
+	i.transformers = append(i.transformers, transformer)
+}
+
+// transformProps applies the registered prop transformers.
+func (i *Inertia) transformProps(props map[string]interface{}) error {
+	for _, transform := range i.transformers {
+		if err := transform(props); err != nil {
+			return fmt.Errorf("inertia: prop transformer failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// PageDecorator observes or modifies a page right before it is written, see
+// SetPageDecorator.
+type PageDecorator func(page *Page, r *http.Request)
+
+// SetPageDecorator sets a function called by InertiaContext.Render with the
+// complete page, after props are assembled, transformed and filtered, right
+// before it is encoded. Unlike prop transformers it may change any page field,
+// e.g. to add a build timestamp prop or rewrite the URL. Props added here skip
+// partial reload filtering. A nil decorator removes it.
+func (i *Inertia) SetPageDecorator(decorator PageDecorator) {
+	i.pageDecorator = decorator
+}
+
+// decoratePage applies the page decorator, if any.
+func (i *Inertia) decoratePage(page *Page, r *http.Request) {
+	if i.pageDecorator != nil {
+		i.pageDecorator(page, r)
+	}
+}
+
+// RedactFields returns a PropTransformer that replaces the values at the given
+// dot-separated key paths with RedactedPlaceholder, e.g. "user.password_hash".
+// Paths follow JSON keys, so structs are matched by their json tags; a path
+// through a list applies to every element. Missing paths are ignored.
+//
+// It is a safety net for secrets accidentally left in props, not a replacement
+// for `json:"-"` tags:
+//
+//	mgr.AddPropTransformer(inertia.RedactFields("user.password_hash", "api_token"))
+func RedactFields(keys ...string) PropTransformer {
+	paths := make([][]string, 0, len(keys))
+	for _, key := range keys {
+		if key != "" {
+			paths = append(paths, strings.Split(key, "."))
+		}
+	}
+
+	return func(props map[string]interface{}) error {
+		for _, path := range paths {
+			value, ok := props[path[0]]
+			if !ok {
+				continue
+			}
+			redacted, err := redactPath(value, path[1:])
+			if err != nil {
+				return fmt.Errorf("redact %s: %w", strings.Join(path, "."), err)
+			}
+			props[path[0]] = redacted
+		}
+		return nil
+	}
+}
+
+// redactPath returns a copy of value with the nested path replaced by
+// RedactedPlaceholder. Structs and typed maps are converted to their JSON form
+// first; value itself is never modified.
+func redactPath(value interface{}, path []string) (interface{}, error) {
+	if len(path) == 0 {
+		return RedactedPlaceholder, nil
+	}
+	if value == nil {
+		return nil, nil
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		nested, ok := v[path[0]]
+		if !ok {
+			return value, nil
+		}
+		redacted, err := redactPath(nested, path[1:])
+		if err != nil {
+			return nil, err
+		}
+		copied := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			copied[key] = val
+		}
+		copied[path[0]] = redacted
+		return copied, nil
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for idx, item := range v {
+			redacted, err := redactPath(item, path)
+			if err != nil {
+				return nil, err
+			}
+			copied[idx] = redacted
+		}
+		return copied, nil
+	}
+
+	switch reflect.Indirect(reflect.ValueOf(value)).Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		generic, err := toJSONValue(value)
+		if err != nil {
+			return nil, err
+		}
+		return redactPath(generic, path)
+	default:
+		return value, nil
+	}
+}
+
+// toJSONValue converts value to its generic JSON representation.
+func toJSONValue(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+	return generic, nil
+}
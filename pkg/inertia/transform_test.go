@@ -0,0 +1,124 @@
+package inertia_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/toutaio/toutago-inertia/pkg/inertia"
+)
+
+type redactUser struct {
+	Name         string `json:"name"`
+	Email        string `json:"email"`
+	PasswordHash string `json:"password_hash"`
+}
+
+func TestRedactFields(t *testing.T) {
+	i, err := inertia.New(inertia.Config{
+		RootView: "app.html",
+		Version:  "1.0.0",
+	})
+	require.NoError(t, err)
+	i.AddPropTransformer(inertia.RedactFields("user.password_hash", "api_token", "missing.key"))
+
+	account := map[string]interface{}{"id": 1, "password_hash": "keep-me"}
+	page, err := i.Render("Users/Show", map[string]interface{}{
+		"user":      redactUser{Name: "Ada", Email: "ada@example.com", PasswordHash: "$2a$10$secret"},
+		"api_token": "tok_123",
+		"account":   account,
+	}, "/users/1")
+	require.NoError(t, err)
+
+	user, ok := page.Props["user"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, inertia.RedactedPlaceholder, user["password_hash"])
+	assert.Equal(t, "Ada", user["name"])
+	assert.Equal(t, "ada@example.com", user["email"])
+	assert.Equal(t, inertia.RedactedPlaceholder, page.Props["api_token"])
+
+	assert.Equal(t, "keep-me", page.Props["account"].(map[string]interface{})["password_hash"])
+	assert.NotContains(t, page.Props, "missing")
+}
+
+func TestRedactFields_NestedMapsAndLists(t *testing.T) {
+	nested := map[string]interface{}{"name": "Ada", "password_hash": "secret"}
+	props := map[string]interface{}{
+		"user":  nested,
+		"users": []interface{}{map[string]interface{}{"name": "Bob", "password_hash": "secret"}},
+	}
+
+	require.NoError(t, inertia.RedactFields("user.password_hash", "users.password_hash")(props))
+
+	assert.Equal(t, inertia.RedactedPlaceholder, props["user"].(map[string]interface{})["password_hash"])
+	assert.Equal(t, "secret", nested["password_hash"], "original map should not be modified")
+
+	users := props["users"].([]interface{})
+	assert.Equal(t, inertia.RedactedPlaceholder, users[0].(map[string]interface{})["password_hash"])
+	assert.Equal(t, "Bob", users[0].(map[string]interface{})["name"])
+}
+
+func TestAddPropTransformer_Error(t *testing.T) {
+	i, err := inertia.New(inertia.Config{
+		RootView: "app.html",
+		Version:  "1.0.0",
+	})
+	require.NoError(t, err)
+
+	boom := errors.New("boom")
+	i.AddPropTransformer(func(props map[string]interface{}) error { return boom })
+
+	_, err = i.Render("Home", nil, "/")
+	assert.ErrorIs(t, err, boom)
+}
+
+func TestSetPageDecorator(t *testing.T) {
+	mgr, err := inertia.New(inertia.Config{RootView: "app.html", Version: "1.0.0"})
+	require.NoError(t, err)
+
+	var calls []*http.Request
+	mgr.SetPageDecorator(func(page *inertia.Page, r *http.Request) {
+		calls = append(calls, r)
+		page.Props["buildTime"] = "2024-01-01T00:00:00Z"
+		page.URL = "/app" + page.URL
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users?page=2", http.NoBody)
+	req.Header.Set("X-Inertia", "true")
+	w := httptest.NewRecorder()
+	require.NoError(t, inertia.NewContext(NewMockContext(w, req), mgr).Render("Users/Index", nil))
+
+	var page inertia.Page
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	assert.Equal(t, "2024-01-01T00:00:00Z", page.Props["buildTime"])
+	assert.Equal(t, "/app/users?page=2", page.URL)
+	require.Len(t, calls, 1)
+	assert.Same(t, req, calls[0])
+}
+
+func TestAddPropTransformer_ErrorPage(t *testing.T) {
+	mgr, err := inertia.New(inertia.Config{RootView: "app.html", Version: "1.0.0"})
+	require.NoError(t, err)
+	mgr.Share("user", redactUser{Name: "Ada", Email: "ada@example.com", PasswordHash: "secret"})
+	mgr.AddPropTransformer(inertia.RedactFields("user.password_hash"))
+
+	req := httptest.NewRequest(http.MethodGet, "/reports", http.NoBody)
+	req.Header.Set("X-Inertia", "true")
+	w := httptest.NewRecorder()
+	require.NoError(t, inertia.NewContext(NewMockContext(w, req), mgr).Error(http.StatusInternalServerError, "Server error"))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "secret")
+
+	var page inertia.Page
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	assert.Equal(t, map[string]interface{}{
+		"name":          "Ada",
+		"email":         "ada@example.com",
+		"password_hash": inertia.RedactedPlaceholder,
+	}, page.Props["user"])
+}
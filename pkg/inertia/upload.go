@@ -0,0 +1,108 @@
+package inertia
+
+import (
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"strings"
+)
+
+// FileRules describes constraints for an uploaded file.
+type FileRules struct {
+	Required bool     // The field must contain a file
+	MaxSize  int64    // Maximum file size in bytes (0 means unlimited)
+	Types    []string // Allowed content types, e.g. "image/png" or "image/*"
+}
+
+// File returns the first file uploaded under the given form field.
+// Returns http.ErrMissingFile if the field has no file.
+func (ic *InertiaContext) File(field string) (*multipart.FileHeader, error) {
+	files := ic.Files(field)
+	if len(files) == 0 {
+		return nil, http.ErrMissingFile
+	}
+	return files[0], nil
+}
+
+// Files returns all files uploaded under the given form field.
+func (ic *InertiaContext) Files(field string) []*multipart.FileHeader {
+	form, err := ic.multipartForm()
+	if err != nil || form == nil {
+		return nil
+	}
+	return form.File[field]
+}
+
+// FormValue returns the first value of a multipart or form-encoded field.
+func (ic *InertiaContext) FormValue(field string) string {
+	// Form-encoded bodies are parsed by FormValue itself
+	if _, err := ic.multipartForm(); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+		return ""
+	}
+	return ic.ctx.Request().FormValue(field)
+}
+
+// ValidateFile checks the files uploaded under field against the rules and records
+// a validation error for the next render when they fail. Returns true if valid.
+func (ic *InertiaContext) ValidateFile(field string, rules FileRules) bool {
+	files := ic.Files(field)
+	if len(files) == 0 {
+		if rules.Required {
+			ic.WithError(field, fmt.Sprintf("The %s field is required.", field))
+			return false
+		}
+		return true
+	}
+
+	for _, file := range files {
+		if rules.MaxSize > 0 && file.Size > rules.MaxSize {
+			ic.WithError(field, fmt.Sprintf("The %s may not be greater than %d bytes.", field, rules.MaxSize))
+			return false
+		}
+
+		if len(rules.Types) > 0 && !matchesContentType(file.Header.Get("Content-Type"), rules.Types) {
+			ic.WithError(field, fmt.Sprintf("The %s must be a file of type: %s.", field, strings.Join(rules.Types, ", ")))
+			return false
+		}
+	}
+
+	return true
+}
+
+// multipartForm parses the request's multipart body once and returns it.
+func (ic *InertiaContext) multipartForm() (*multipart.Form, error) {
+	req := ic.ctx.Request()
+	if req.MultipartForm != nil {
+		return req.MultipartForm, nil
+	}
+
+	if err := req.ParseMultipartForm(ic.mgr.maxMemory()); err != nil {
+		return nil, err
+	}
+	return req.MultipartForm, nil
+}
+
+// maxMemory returns the configured multipart memory limit.
+func (i *Inertia) maxMemory() int64 {
+	if i.config.MaxMemory > 0 {
+		return i.config.MaxMemory
+	}
+	return defaultMaxMemory
+}
+
+// matchesContentType reports whether contentType matches any of the allowed types.
+func matchesContentType(contentType string, allowed []string) bool {
+	contentType = strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+
+	for _, pattern := range allowed {
+		pattern = strings.ToLower(pattern)
+		if pattern == contentType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(pattern, "/*"); ok && strings.HasPrefix(contentType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package inertia_test
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/toutaio/toutago-inertia/pkg/inertia"
+)
+
+// newUploadRequest builds a multipart request with a text field and a single file.
+func newUploadRequest(t *testing.T, contentType string, content []byte) *http.Request {
+	t.Helper()
+
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	require.NoError(t, mw.WriteField("title", "Holiday"))
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", `form-data; name="avatar"; filename="avatar.png"`)
+	header.Set("Content-Type", contentType)
+	part, err := mw.CreatePart(header)
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, mw.Close())
+
+	req := httptest.NewRequest("POST", "/photos", &buf)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	req.Header.Set("X-Inertia", "true")
+	return req
+}
+
+func TestInertiaContext_File(t *testing.T) {
+	config := inertia.Config{
+		RootView: "app.html",
+		Version:  "1.0.0",
+	}
+
+	mgr, err := inertia.New(config)
+	require.NoError(t, err)
+
+	req := newUploadRequest(t, "image/png", []byte("fake image data"))
+	w := httptest.NewRecorder()
+	ic := inertia.NewContext(NewMockContext(w, req), mgr)
+
+	file, err := ic.File("avatar")
+	require.NoError(t, err)
+	assert.Equal(t, "avatar.png", file.Filename)
+
+	f, err := file.Open()
+	require.NoError(t, err)
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	require.NoError(t, err)
+	assert.Equal(t, "fake image data", string(data))
+
+	assert.Equal(t, "Holiday", ic.FormValue("title"))
+	assert.Len(t, ic.Files("avatar"), 1)
+
+	_, err = ic.File("missing")
+	assert.ErrorIs(t, err, http.ErrMissingFile)
+}
+
+func TestInertiaContext_FormValue_URLEncoded(t *testing.T) {
+	mgr, err := inertia.New(inertia.Config{RootView: "app.html", Version: "1.0.0"})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/albums", strings.NewReader("title=Holiday&year=2024"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	ic := inertia.NewContext(NewMockContext(httptest.NewRecorder(), req), mgr)
+
+	assert.Equal(t, "Holiday", ic.FormValue("title"))
+	assert.Equal(t, "2024", ic.FormValue("year"))
+	assert.Empty(t, ic.Files("avatar"))
+}
+
+func TestInertiaContext_ValidateFile(t *testing.T) {
+	config := inertia.Config{
+		RootView: "app.html",
+		Version:  "1.0.0",
+	}
+
+	mgr, err := inertia.New(config)
+	require.NoError(t, err)
+
+	t.Run("valid file", func(t *testing.T) {
+		req := newUploadRequest(t, "image/png", []byte("small"))
+		ic := inertia.NewContext(NewMockContext(httptest.NewRecorder(), req), mgr)
+
+		assert.True(t, ic.ValidateFile("avatar", inertia.FileRules{
+			Required: true,
+			MaxSize:  1024,
+			Types:    []string{"image/*"},
+		}))
+	})
+
+	t.Run("too large and wrong type surface as errors", func(t *testing.T) {
+		req := newUploadRequest(t, "application/pdf", bytes.Repeat([]byte("x"), 2048))
+		w := httptest.NewRecorder()
+		ic := inertia.NewContext(NewMockContext(w, req), mgr)
+
+		assert.False(t, ic.ValidateFile("avatar", inertia.FileRules{MaxSize: 1024}))
+		assert.False(t, ic.ValidateFile("avatar", inertia.FileRules{Types: []string{"image/png"}}))
+		assert.False(t, ic.ValidateFile("document", inertia.FileRules{Required: true}))
+
+		err := ic.Render("Photos/Create", map[string]interface{}{})
+		require.NoError(t, err)
+
+		body := w.Body.String()
+		assert.Contains(t, body, "The avatar may not be greater than 1024 bytes.")
+		assert.Contains(t, body, "The avatar must be a file of type: image/png.")
+		assert.Contains(t, body, "The document field is required.")
+	})
+}
@@ -0,0 +1,35 @@
+package inertia
+
+// contextKeyUser is the ContextInterface key holding the authenticated user.
+const contextKeyUser = "_inertia_user"
+
+// SetUser stores the authenticated user for this request. Once set, the user is
+// shared with every page rendered through this context as the auth.user prop.
+func (ic *InertiaContext) SetUser(user interface{}) *InertiaContext {
+	ic.ctx.Set(contextKeyUser, user)
+	return ic
+}
+
+// User returns the authenticated user set with SetUser, or nil.
+func (ic *InertiaContext) User() interface{} {
+	return ic.ctx.Get(contextKeyUser)
+}
+
+// UserAs returns the authenticated user as type T.
+// It reports false if no user is set or the user is not a T.
+//
+//	user, ok := inertia.UserAs[*models.User](ic)
+func UserAs[T any](ic *InertiaContext) (T, bool) {
+	user, ok := ic.User().(T)
+	return user, ok
+}
+
+// mergeAuthUser shares the authenticated user as auth.user unless the handler
+// already provides an auth prop.
+func (ic *InertiaContext) mergeAuthUser(props map[string]interface{}) {
+	user := ic.User()
+	if user == nil {
+		return
+	}
+	setDefault(props, "auth", map[string]interface{}{"user": user})
+}
@@ -0,0 +1,80 @@
+package inertia_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/toutaio/toutago-inertia/pkg/inertia"
+)
+
+type authUser struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestInertiaContext_User(t *testing.T) {
+	config := inertia.Config{
+		RootView: "app.html",
+		Version:  "1.0.0",
+	}
+
+	mgr, err := inertia.New(config)
+	require.NoError(t, err)
+
+	newContext := func() (*inertia.InertiaContext, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest("GET", "/dashboard", http.NoBody)
+		req.Header.Set("X-Inertia", "true")
+		w := httptest.NewRecorder()
+		return inertia.NewContext(NewMockContext(w, req), mgr), w
+	}
+
+	t.Run("set and get", func(t *testing.T) {
+		ictx, _ := newContext()
+		assert.Nil(t, ictx.User())
+
+		_, ok := inertia.UserAs[*authUser](ictx)
+		assert.False(t, ok)
+
+		ictx.SetUser(&authUser{ID: 1, Name: "Alice"})
+
+		user, ok := inertia.UserAs[*authUser](ictx)
+		require.True(t, ok)
+		assert.Equal(t, "Alice", user.Name)
+
+		_, ok = inertia.UserAs[string](ictx)
+		assert.False(t, ok)
+	})
+
+	t.Run("user is shared as auth.user", func(t *testing.T) {
+		ictx, w := newContext()
+		ictx.SetUser(&authUser{ID: 1, Name: "Alice"})
+
+		require.NoError(t, ictx.Render("Dashboard", map[string]interface{}{}))
+
+		var page struct {
+			Props struct {
+				Auth struct {
+					User authUser `json:"user"`
+				} `json:"auth"`
+			} `json:"props"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		assert.Equal(t, authUser{ID: 1, Name: "Alice"}, page.Props.Auth.User)
+	})
+
+	t.Run("handler auth prop takes precedence", func(t *testing.T) {
+		ictx, w := newContext()
+		ictx.SetUser(&authUser{ID: 1, Name: "Alice"})
+
+		require.NoError(t, ictx.Render("Dashboard", map[string]interface{}{
+			"auth": map[string]interface{}{"guest": true},
+		}))
+
+		assert.Contains(t, w.Body.String(), `"guest":true`)
+		assert.NotContains(t, w.Body.String(), "Alice")
+	})
+}
@@ -0,0 +1,138 @@
+package inertia
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// FieldError is the subset of go-playground/validator's FieldError used to build
+// ValidationErrors. It is declared here so the package does not depend on the
+// validator module; validator.FieldError satisfies it.
+type FieldError interface {
+	Tag() string
+	Namespace() string
+	Field() string
+	Param() string
+	Kind() reflect.Kind
+	Error() string
+}
+
+// ValidationTranslator returns the message for a failed validation rule, or an
+// empty string to use the default message.
+type ValidationTranslator func(fe FieldError) string
+
+// ErrorsFromValidator converts the validator.ValidationErrors returned by
+// go-playground/validator into ValidationErrors with human-readable messages.
+// It returns nil when err holds no field errors. Field errors wrapped with
+// fmt.Errorf or combined with errors.Join are all collected.
+//
+// Errors are keyed by the validator's field names, with nested fields joined by
+// dots. These are the Go field names unless JSONTagName is registered on the
+// validator, which ErrorsFromValidator cannot do for you; register it so keys
+// match the JSON field names the client submits:
+//
+//	validate := validator.New()
+//	validate.RegisterTagNameFunc(inertia.JSONTagName)
+//
+//	if err := validate.Struct(input); err != nil {
+//		return ic.WithErrors(inertia.ErrorsFromValidator(err)).Back()
+//	}
+func ErrorsFromValidator(err error, translate ...ValidationTranslator) ValidationErrors {
+	fieldErrs := fieldErrors(err)
+	if len(fieldErrs) == 0 {
+		return nil
+	}
+
+	errs := NewValidationErrors()
+	for _, fe := range fieldErrs {
+		message := ""
+		for _, fn := range translate {
+			if message = fn(fe); message != "" {
+				break
+			}
+		}
+		if message == "" {
+			message = validatorMessage(fe)
+		}
+		errs.Add(fieldErrorKey(fe), message)
+	}
+	return errs
+}
+
+// JSONTagName returns the JSON name of a struct field, or "" for fields tagged
+// `json:"-"` so the validator skips them. Pass it to the validator's
+// RegisterTagNameFunc so field errors are reported under JSON keys.
+func JSONTagName(field reflect.StructField) string {
+	if name, _, _ := strings.Cut(field.Tag.Get("json"), ","); name == "-" {
+		return ""
+	}
+	return jsonFieldName(field)
+}
+
+// fieldErrors extracts the field errors from err, which is expected to wrap one
+// or more validator.ValidationErrors slices.
+func fieldErrors(err error) []FieldError {
+	if err == nil {
+		return nil
+	}
+	if fe, ok := err.(FieldError); ok {
+		return []FieldError{fe}
+	}
+
+	if v := reflect.ValueOf(err); v.Kind() == reflect.Slice {
+		fieldErrs := make([]FieldError, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			if fe, ok := v.Index(i).Interface().(FieldError); ok {
+				fieldErrs = append(fieldErrs, fe)
+			}
+		}
+		if len(fieldErrs) > 0 {
+			return fieldErrs
+		}
+	}
+
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var fieldErrs []FieldError
+		for _, inner := range joined.Unwrap() {
+			fieldErrs = append(fieldErrs, fieldErrors(inner)...)
+		}
+		return fieldErrs
+	}
+	return fieldErrors(errors.Unwrap(err))
+}
+
+// fieldErrorKey returns the error key for a field: its namespace without the
+// top-level struct name, e.g. "address.city".
+func fieldErrorKey(fe FieldError) string {
+	if _, key, ok := strings.Cut(fe.Namespace(), "."); ok {
+		return key
+	}
+	return fe.Field()
+}
+
+// validatorMessage returns the default message for a failed validation rule,
+// worded like the messages produced by Bind.
+func validatorMessage(fe FieldError) string {
+	name := fe.Field()
+
+	switch fe.Tag() {
+	case "required", "required_if", "required_unless", "required_with", "required_without":
+		return fmt.Sprintf("The %s field is required.", name)
+	case "email":
+		return fmt.Sprintf("The %s must be a valid email address.", name)
+	case "min", "gte":
+		return sizeMessage(name, "min", fe.Param(), fe.Kind())
+	case "max", "lte":
+		return sizeMessage(name, "max", fe.Param(), fe.Kind())
+	case "len":
+		return sizeMessage(name, "len", fe.Param(), fe.Kind())
+	case "oneof":
+		return fmt.Sprintf("The selected %s is invalid.", name)
+	case "url", "http_url":
+		return fmt.Sprintf("The %s must be a valid URL.", name)
+	default:
+		return fmt.Sprintf("The %s field is invalid.", name)
+	}
+}
@@ -0,0 +1,155 @@
+package inertia_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/toutaio/toutago-inertia/pkg/inertia"
+)
+
+// fakeFieldError mirrors go-playground/validator's FieldError.
+type fakeFieldError struct {
+	tag, namespace, field, param string
+	kind                         reflect.Kind
+}
+
+func (e fakeFieldError) Tag() string        { return e.tag }
+func (e fakeFieldError) Namespace() string  { return e.namespace }
+func (e fakeFieldError) Field() string      { return e.field }
+func (e fakeFieldError) Param() string      { return e.param }
+func (e fakeFieldError) Kind() reflect.Kind { return e.kind }
+func (e fakeFieldError) Error() string      { return "validation failed on " + e.tag }
+
+// fakeValidationErrors mirrors validator.ValidationErrors.
+type fakeValidationErrors []inertia.FieldError
+
+func (fakeValidationErrors) Error() string { return "validation failed" }
+
+func TestErrorsFromValidator(t *testing.T) {
+	validationErr := fakeValidationErrors{
+		fakeFieldError{tag: "required", namespace: "SignupRequest.name", field: "name", kind: reflect.String},
+		fakeFieldError{tag: "min", namespace: "SignupRequest.password", field: "password", param: "8", kind: reflect.String},
+		fakeFieldError{tag: "min", namespace: "SignupRequest.age", field: "age", param: "18", kind: reflect.Int},
+		fakeFieldError{tag: "email", namespace: "SignupRequest.email", field: "email", kind: reflect.String},
+		fakeFieldError{tag: "required", namespace: "SignupRequest.address.city", field: "city", kind: reflect.String},
+	}
+
+	t.Run("default messages", func(t *testing.T) {
+		errs := inertia.ErrorsFromValidator(validationErr)
+
+		assert.Equal(t, inertia.ValidationErrors{
+			"name":         {"The name field is required."},
+			"password":     {"The password must be at least 8 characters."},
+			"age":          {"The age must be at least 18."},
+			"email":        {"The email must be a valid email address."},
+			"address.city": {"The city field is required."},
+		}, errs)
+	})
+
+	t.Run("wrapped errors", func(t *testing.T) {
+		errs := inertia.ErrorsFromValidator(fmt.Errorf("create user: %w", validationErr))
+		assert.Len(t, errs, 5)
+	})
+
+	t.Run("joined errors", func(t *testing.T) {
+		other := fakeValidationErrors{
+			fakeFieldError{tag: "required", namespace: "Profile.bio", field: "bio", kind: reflect.String},
+		}
+		errs := inertia.ErrorsFromValidator(errors.Join(errors.New("boom"), validationErr, fmt.Errorf("profile: %w", other)))
+		assert.Len(t, errs, 6)
+		assert.Equal(t, []string{"The bio field is required."}, errs["bio"])
+	})
+
+	t.Run("custom translator", func(t *testing.T) {
+		errs := inertia.ErrorsFromValidator(validationErr, func(fe inertia.FieldError) string {
+			if fe.Tag() == "required" {
+				return fe.Field() + " est obligatoire"
+			}
+			return ""
+		})
+
+		assert.Equal(t, []string{"name est obligatoire"}, errs["name"])
+		assert.Equal(t, []string{"The email must be a valid email address."}, errs["email"])
+	})
+
+	t.Run("non-validation errors", func(t *testing.T) {
+		assert.Nil(t, inertia.ErrorsFromValidator(nil))
+		assert.Nil(t, inertia.ErrorsFromValidator(errors.New("boom")))
+	})
+}
+
+func TestJSONTagName(t *testing.T) {
+	type input struct {
+		Email  string `json:"email_address,omitempty"`
+		Name   string
+		Secret string `json:"-"`
+	}
+
+	typ := reflect.TypeOf(input{})
+	assert.Equal(t, "email_address", inertia.JSONTagName(typ.Field(0)))
+	assert.Equal(t, "Name", inertia.JSONTagName(typ.Field(1)))
+	assert.Equal(t, "", inertia.JSONTagName(typ.Field(2)), "json:\"-\" fields are skipped by the validator")
+}
+
+// playgroundFieldError has the method set of validator.FieldError, implemented on
+// a pointer like the validator's own fieldError.
+type playgroundFieldError interface {
+	Tag() string
+	ActualTag() string
+	Namespace() string
+	StructNamespace() string
+	Field() string
+	StructField() string
+	Value() interface{}
+	Param() string
+	Kind() reflect.Kind
+	Type() reflect.Type
+	Error() string
+}
+
+type playgroundError struct {
+	tag, ns, structNs, field, structField, param string
+	value                                        interface{}
+}
+
+func (e *playgroundError) Tag() string             { return e.tag }
+func (e *playgroundError) ActualTag() string       { return e.tag }
+func (e *playgroundError) Namespace() string       { return e.ns }
+func (e *playgroundError) StructNamespace() string { return e.structNs }
+func (e *playgroundError) Field() string           { return e.field }
+func (e *playgroundError) StructField() string     { return e.structField }
+func (e *playgroundError) Value() interface{}      { return e.value }
+func (e *playgroundError) Param() string           { return e.param }
+func (e *playgroundError) Kind() reflect.Kind      { return reflect.TypeOf(e.value).Kind() }
+func (e *playgroundError) Type() reflect.Type      { return reflect.TypeOf(e.value) }
+func (e *playgroundError) Error() string {
+	return "Key: '" + e.ns + "' Error:Field validation for '" + e.field + "' failed"
+}
+
+// playgroundValidationErrors has the shape of validator.ValidationErrors: a slice
+// of the validator's own FieldError interface, not inertia.FieldError.
+type playgroundValidationErrors []playgroundFieldError
+
+func (playgroundValidationErrors) Error() string { return "validation failed" }
+
+func TestErrorsFromValidator_PlaygroundShape(t *testing.T) {
+	// As reported with JSONTagName registered on the validator
+	var err error = playgroundValidationErrors{
+		&playgroundError{
+			tag: "required", ns: "SignupRequest.email_address", structNs: "SignupRequest.Email",
+			field: "email_address", structField: "Email", value: "",
+		},
+		&playgroundError{
+			tag: "gte", ns: "SignupRequest.age", structNs: "SignupRequest.Age",
+			field: "age", structField: "Age", param: "18", value: 16,
+		},
+	}
+
+	assert.Equal(t, inertia.ValidationErrors{
+		"email_address": {"The email_address field is required."},
+		"age":           {"The age must be at least 18."},
+	}, inertia.ErrorsFromValidator(fmt.Errorf("signup: %w", err)))
+}
@@ -0,0 +1,138 @@
+package realtime
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRegisteredClient creates a connectionless client registered with the hub.
+func newRegisteredClient(hub *Hub) *Client {
+	client := hub.newClient(nil)
+	hub.mu.Lock()
+	hub.clients[client] = true
+	hub.mu.Unlock()
+	return client
+}
+
+// nextFrame returns the next queued frame for the client, or nil if none.
+func nextFrame(t *testing.T, client *Client) *Message {
+	t.Helper()
+
+	select {
+	case data := <-client.send:
+		var msg Message
+		require.NoError(t, json.Unmarshal(data, &msg))
+		return &msg
+	default:
+		return nil
+	}
+}
+
+func TestInboundMessages(t *testing.T) {
+	t.Run("malformed JSON", func(t *testing.T) {
+		client := newRegisteredClient(NewHub())
+		client.handleInbound([]byte(`{"type":`))
+
+		frame := nextFrame(t, client)
+		require.NotNil(t, frame)
+		assert.Equal(t, MessageTypeError, frame.Type)
+		assert.Contains(t, frame.Data.(map[string]interface{})["message"], "invalid message")
+	})
+
+	t.Run("subscribe without channel", func(t *testing.T) {
+		client := newRegisteredClient(NewHub())
+		client.handleInbound([]byte(`{"type":"subscribe"}`))
+
+		frame := nextFrame(t, client)
+		require.NotNil(t, frame)
+		assert.Equal(t, MessageTypeError, frame.Type)
+		assert.Empty(t, client.channels)
+	})
+
+	t.Run("valid subscribe", func(t *testing.T) {
+		client := newRegisteredClient(NewHub())
+		client.handleInbound([]byte(`{"type":"subscribe","channel":"news"}`))
+
+		assert.True(t, client.IsSubscribed("news"))
+		assert.Nil(t, nextFrame(t, client))
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		client := newRegisteredClient(NewHub())
+		client.handleInbound([]byte(`{"type":"typing","channel":"chat"}`))
+
+		frame := nextFrame(t, client)
+		require.NotNil(t, frame)
+		assert.Equal(t, MessageTypeError, frame.Type)
+		assert.Equal(t, "chat", frame.Channel)
+		assert.Contains(t, frame.Data.(map[string]interface{})["message"], "unknown message type: typing")
+	})
+
+	t.Run("unknown type ignored when configured", func(t *testing.T) {
+		client := newRegisteredClient(NewHub(WithUnknownMessageErrors(false)))
+		client.handleInbound([]byte(`{"type":"typing","channel":"chat"}`))
+
+		assert.Nil(t, nextFrame(t, client))
+	})
+
+	t.Run("custom type reaches the handler", func(t *testing.T) {
+		var received []Message
+		hub := NewHub(WithMessageHandler(func(_ *Client, msg Message) error {
+			received = append(received, msg)
+			if msg.Type == "forbidden" {
+				return errors.New("not allowed")
+			}
+			return nil
+		}))
+		client := newRegisteredClient(hub)
+
+		client.handleInbound([]byte(`{"type":"typing","channel":"chat","data":{"user":"alice"}}`))
+		require.Len(t, received, 1)
+		assert.Equal(t, "typing", received[0].Type)
+		assert.Equal(t, "chat", received[0].Channel)
+		assert.Equal(t, map[string]interface{}{"user": "alice"}, received[0].Data)
+		assert.Nil(t, nextFrame(t, client))
+
+		client.handleInbound([]byte(`{"type":"forbidden","channel":"chat"}`))
+		frame := nextFrame(t, client)
+		require.NotNil(t, frame)
+		assert.Equal(t, "not allowed", frame.Data.(map[string]interface{})["message"])
+	})
+}
+
+func TestMaxChannelsPerClient(t *testing.T) {
+	hub := NewHub(WithMaxChannelsPerClient(2))
+	client := newRegisteredClient(hub)
+
+	client.handleInbound([]byte(`{"type":"subscribe","channel":"news"}`))
+	client.handleInbound([]byte(`{"type":"subscribe","channel":"sports"}`))
+	assert.Nil(t, nextFrame(t, client))
+
+	client.handleInbound([]byte(`{"type":"subscribe","channel":"weather"}`))
+	frame := nextFrame(t, client)
+	require.NotNil(t, frame)
+	assert.Equal(t, MessageTypeError, frame.Type)
+	assert.Equal(t, "weather", frame.Channel)
+	assert.Equal(t, ErrTooManyChannels.Error(), frame.Data.(map[string]interface{})["message"])
+
+	assert.ErrorIs(t, client.TrySubscribe("weather"), ErrTooManyChannels)
+	assert.False(t, client.IsSubscribed("weather"))
+	assert.True(t, client.IsSubscribed("news"))
+	assert.True(t, client.IsSubscribed("sports"))
+
+	// Re-subscribing to a channel doesn't count against the limit
+	assert.NoError(t, client.TrySubscribe("news"))
+
+	// Unsubscribing frees a slot
+	client.Unsubscribe("news")
+	assert.NoError(t, client.TrySubscribe("weather"))
+	assert.True(t, client.IsSubscribed("weather"))
+
+	// Server-side subscriptions are not limited
+	client.Subscribe("alerts")
+	assert.True(t, client.IsSubscribed("alerts"))
+}
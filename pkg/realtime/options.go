@@ -0,0 +1,151 @@
+package realtime
+
+import (
+	"net/http"
+	"time"
+)
+
+// HubOption configures a Hub.
+type HubOption func(*Hub)
+
+// WithSubprotocols sets the WebSocket subprotocols supported by the hub, in order
+// of preference. The first protocol requested by the client that matches is
+// negotiated and recorded on the Client.
+func WithSubprotocols(protocols []string) HubOption {
+	return func(h *Hub) {
+		h.upgrader.Subprotocols = protocols
+	}
+}
+
+// WithCompression enables permessage-deflate compression (RFC 7692).
+//
+// Compression is only used when the client also offers it during the handshake;
+// clients without support fall back to uncompressed frames. Compression trades
+// CPU for bandwidth and is most useful for large or repetitive payloads.
+func WithCompression(enabled bool) HubOption {
+	return func(h *Hub) {
+		h.upgrader.EnableCompression = enabled
+	}
+}
+
+// WithClientSendBuffer sets the number of outgoing messages buffered per client.
+//
+// When a client's buffer is full the hub disconnects it instead of blocking, so
+// this value sets the backpressure point for slow consumers. Larger buffers
+// tolerate bursts on high-throughput channels; smaller ones bound memory use.
+func WithClientSendBuffer(n int) HubOption {
+	return func(h *Hub) {
+		if n > 0 {
+			h.sendBuffer = n
+		}
+	}
+}
+
+// WithWriteWait sets the time allowed to write a message to the peer.
+func WithWriteWait(d time.Duration) HubOption {
+	return func(h *Hub) {
+		if d > 0 {
+			h.writeWait = d
+		}
+	}
+}
+
+// WithPongWait sets how long a connection may stay silent before it is closed.
+// Each pong from the peer extends the read deadline by this duration.
+func WithPongWait(d time.Duration) HubOption {
+	return func(h *Hub) {
+		if d > 0 {
+			h.pongWait = d
+		}
+	}
+}
+
+// WithPingPeriod sets how often pings are sent to the peer. It must be positive
+// and less than the pong wait; otherwise NewHub logs the invalid value and, as
+// by default, 90% of the pong wait is used. Zero keeps the default.
+func WithPingPeriod(d time.Duration) HubOption {
+	return func(h *Hub) {
+		h.pingPeriod = d
+	}
+}
+
+// WithReloadDebounce sets the window in which PushInertiaReload calls for the same
+// channel and component are collapsed into a single message. Zero disables debouncing.
+func WithReloadDebounce(d time.Duration) HubOption {
+	return func(h *Hub) {
+		if d >= 0 {
+			h.reloadDebounce = d
+		}
+	}
+}
+
+// WithOnConnect sets a callback invoked after a client is registered.
+//
+// Callbacks run on the hub's event loop without holding the hub lock, so they may
+// call hub methods such as Publish, but must not block for long.
+func WithOnConnect(fn func(*Client)) HubOption {
+	return func(h *Hub) {
+		h.onConnect = fn
+	}
+}
+
+// WithOnDisconnect sets a callback invoked after a client is unregistered.
+// The same constraints as WithOnConnect apply.
+func WithOnDisconnect(fn func(*Client)) HubOption {
+	return func(h *Hub) {
+		h.onDisconnect = fn
+	}
+}
+
+// WithMessageHandler sets a handler for inbound message types other than
+// subscribe and unsubscribe. An error returned by the handler is sent back to
+// the client as an error frame.
+func WithMessageHandler(fn func(*Client, Message) error) HubOption {
+	return func(h *Hub) {
+		h.messageHandler = fn
+	}
+}
+
+// WithUnknownMessageErrors controls whether inbound messages of an unknown type
+// are answered with an error frame (the default) or silently ignored. It has no
+// effect when a message handler is set.
+func WithUnknownMessageErrors(enabled bool) HubOption {
+	return func(h *Hub) {
+		h.rejectUnknown = enabled
+	}
+}
+
+// WithMaxChannelsPerClient limits the number of channels a client may subscribe
+// to. Inbound subscribe messages and Client.TrySubscribe beyond the limit are
+// rejected with ErrTooManyChannels, which inbound messages answer with an error
+// frame; existing subscriptions stay active. Client.Subscribe, called by server
+// code, is not limited. Zero or a negative n means no limit (the default).
+func WithMaxChannelsPerClient(n int) HubOption {
+	return func(h *Hub) {
+		h.maxChannels = n
+	}
+}
+
+// WithSequenceNumbers numbers the messages of each channel: Message.Seq starts at
+// 1 and increases by one for every message published to the channel, and clients
+// receive a channel's messages in that order. Sequences are kept per channel
+// name, so clients subscribed through a pattern such as "orders.*" should track
+// Seq per Message.Channel.
+func WithSequenceNumbers(enabled bool) HubOption {
+	return func(h *Hub) {
+		h.sequenced = enabled
+	}
+}
+
+// WithAuthenticator sets a function that authenticates WebSocket requests before
+// they are upgraded, e.g. from a token in the query string or the
+// Sec-WebSocket-Protocol header, since browsers cannot set other headers on
+// WebSocket requests. Rejected requests get 401 Unauthorized; accepted clients
+// carry the returned user ID, see Client.UserID. Clients sending the token as a
+// subprotocol pair it with a fixed protocol name, which must be accepted with
+// WithSubprotocols, or browsers drop the connection.
+func WithAuthenticator(fn func(r *http.Request) (userID string, ok bool)) HubOption {
+	return func(h *Hub) {
+		h.authenticate = fn
+	}
+}
@@ -0,0 +1,277 @@
+package realtime
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startHubServer runs the hub and serves its WebSocket handler on a test server.
+func startHubServer(t *testing.T, hub *Hub) string {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go hub.Run(ctx)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = hub.HandleWebSocket(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+// waitForClient waits until the hub has registered a client and returns it.
+func waitForClient(t *testing.T, hub *Hub) *Client {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		hub.mu.RLock()
+		for client := range hub.clients {
+			hub.mu.RUnlock()
+			return client
+		}
+		hub.mu.RUnlock()
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	t.Fatal("client was not registered")
+	return nil
+}
+
+func TestWithSubprotocols(t *testing.T) {
+	hub := NewHub(WithSubprotocols([]string{"inertia.v1"}), WithCompression(true))
+	assert.True(t, hub.upgrader.EnableCompression)
+
+	wsURL := startHubServer(t, hub)
+
+	dialer := websocket.Dialer{
+		Subprotocols:      []string{"unknown", "inertia.v1"},
+		EnableCompression: true,
+	}
+	conn, resp, err := dialer.Dial(wsURL, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	defer resp.Body.Close()
+
+	assert.Equal(t, "inertia.v1", conn.Subprotocol())
+	assert.Equal(t, "inertia.v1", resp.Header.Get("Sec-WebSocket-Protocol"))
+
+	client := waitForClient(t, hub)
+	assert.Equal(t, "inertia.v1", client.Subprotocol())
+}
+
+func TestWithClientSendBuffer(t *testing.T) {
+	hub := NewHub(WithClientSendBuffer(1))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.Run(ctx)
+
+	stalled := hub.newClient(nil)
+	assert.Equal(t, 1, cap(stalled.send))
+	stalled.Subscribe("feed")
+
+	healthy := hub.newClient(nil)
+	healthy.Subscribe("other")
+
+	hub.register <- stalled
+	hub.register <- healthy
+
+	// The stalled client never drains its buffer
+	for i := 0; i < 5; i++ {
+		hub.Publish("feed", "update", i)
+	}
+
+	require.Eventually(t, func() bool {
+		hub.mu.RLock()
+		defer hub.mu.RUnlock()
+		return !hub.clients[stalled]
+	}, time.Second, 5*time.Millisecond, "stalled client should be disconnected")
+
+	// The hub loop keeps serving other clients
+	hub.Publish("other", "update", "still running")
+	select {
+	case <-healthy.send:
+	case <-time.After(time.Second):
+		t.Fatal("hub should not be blocked by a stalled client")
+	}
+}
+
+func TestWithOnConnectAndDisconnect(t *testing.T) {
+	connected := make(chan *Client, 1)
+	disconnected := make(chan *Client, 1)
+
+	var hub *Hub
+	hub = NewHub(
+		WithOnConnect(func(c *Client) {
+			// Publishing from a callback must not deadlock the hub
+			hub.Publish("*", "presence", map[string]int{"online": 1})
+			connected <- c
+		}),
+		WithOnDisconnect(func(c *Client) {
+			hub.Publish("*", "presence", map[string]int{"online": 0})
+			disconnected <- c
+		}),
+	)
+	wsURL := startHubServer(t, hub)
+
+	conn, resp, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var client *Client
+	select {
+	case client = <-connected:
+	case <-time.After(time.Second):
+		t.Fatal("OnConnect was not called")
+	}
+	assert.Same(t, waitForClient(t, hub), client)
+
+	// The presence message published from OnConnect is delivered
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	_, data, err := conn.ReadMessage()
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"type":"presence"`)
+
+	conn.Close()
+
+	select {
+	case c := <-disconnected:
+		assert.Same(t, client, c)
+	case <-time.After(2 * time.Second):
+		t.Fatal("OnDisconnect was not called")
+	}
+
+	// The hub keeps processing after the callbacks
+	hub.Publish("*", "ping", nil)
+	hub.mu.RLock()
+	assert.Empty(t, hub.clients)
+	hub.mu.RUnlock()
+}
+
+func TestKeepaliveTimings(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		hub := NewHub()
+		assert.Equal(t, defaultWriteWait, hub.writeWait)
+		assert.Equal(t, defaultPongWait, hub.pongWait)
+		assert.Equal(t, defaultPongWait*9/10, hub.pingPeriod)
+	})
+
+	t.Run("ping period must be less than pong wait", func(t *testing.T) {
+		var logs bytes.Buffer
+		defer log.SetOutput(log.Writer())
+		log.SetOutput(&logs)
+
+		hub := NewHub(WithPongWait(time.Second), WithPingPeriod(2*time.Second))
+		assert.Equal(t, 900*time.Millisecond, hub.pingPeriod)
+		assert.Contains(t, logs.String(), "ping period 2s must be positive and less than the pong wait 1s; using 900ms")
+
+		logs.Reset()
+		hub = NewHub(WithPongWait(time.Second), WithPingPeriod(-time.Second))
+		assert.Equal(t, 900*time.Millisecond, hub.pingPeriod)
+		assert.Contains(t, logs.String(), "ping period -1s must be positive")
+
+		logs.Reset()
+		hub = NewHub(WithPongWait(time.Second))
+		assert.Equal(t, 900*time.Millisecond, hub.pingPeriod, "ping period follows a shorter pong wait")
+		assert.Empty(t, logs.String(), "the default ping period is not logged")
+	})
+
+	t.Run("silent peer is dropped after pong wait", func(t *testing.T) {
+		hub := NewHub(
+			WithWriteWait(time.Second),
+			WithPongWait(150*time.Millisecond),
+			WithPingPeriod(time.Hour),
+		)
+		assert.Equal(t, time.Second, hub.writeWait)
+		assert.Equal(t, 135*time.Millisecond, hub.pingPeriod)
+
+		wsURL := startHubServer(t, hub)
+
+		// The peer never reads, so it never answers pings
+		conn, resp, err := websocket.DefaultDialer.Dial(wsURL, nil)
+		require.NoError(t, err)
+		defer conn.Close()
+		defer resp.Body.Close()
+
+		waitForClient(t, hub)
+		start := time.Now()
+
+		assert.Eventually(t, func() bool {
+			hub.mu.RLock()
+			defer hub.mu.RUnlock()
+			return len(hub.clients) == 0
+		}, 2*time.Second, 10*time.Millisecond)
+		assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+	})
+
+	t.Run("responsive peer stays connected", func(t *testing.T) {
+		hub := NewHub(WithPongWait(150*time.Millisecond), WithPingPeriod(50*time.Millisecond))
+		wsURL := startHubServer(t, hub)
+
+		conn, resp, err := websocket.DefaultDialer.Dial(wsURL, nil)
+		require.NoError(t, err)
+		defer conn.Close()
+		defer resp.Body.Close()
+
+		// Reading lets the default ping handler answer with pongs
+		go func() {
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+
+		client := waitForClient(t, hub)
+		time.Sleep(400 * time.Millisecond)
+
+		hub.mu.RLock()
+		defer hub.mu.RUnlock()
+		assert.True(t, hub.clients[client])
+	})
+}
+
+func TestWithAuthenticator(t *testing.T) {
+	hub := NewHub(WithAuthenticator(func(r *http.Request) (string, bool) {
+		if r.URL.Query().Get("token") != "secret" {
+			return "", false
+		}
+		return "user-42", true
+	}))
+	wsURL := startHubServer(t, hub)
+
+	t.Run("rejected", func(t *testing.T) {
+		conn, resp, err := websocket.DefaultDialer.Dial(wsURL+"?token=wrong", nil)
+		if conn != nil {
+			conn.Close()
+		}
+		require.ErrorIs(t, err, websocket.ErrBadHandshake)
+		require.NotNil(t, resp)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		assert.Equal(t, 0, hub.ClientCount())
+	})
+
+	t.Run("accepted", func(t *testing.T) {
+		conn, resp, err := websocket.DefaultDialer.Dial(wsURL+"?token=secret", nil)
+		require.NoError(t, err)
+		defer conn.Close()
+		defer resp.Body.Close()
+
+		client := waitForClient(t, hub)
+		assert.Equal(t, "user-42", client.UserID())
+	})
+}
@@ -4,9 +4,11 @@ package realtime
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"log"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
@@ -14,14 +16,14 @@ import (
 )
 
 const (
-	// Time allowed to write a message to the peer.
-	writeWait = 10 * time.Second
+	// Default time allowed to write a message to the peer.
+	defaultWriteWait = 10 * time.Second
 
-	// Time allowed to read the next pong message from the peer.
-	pongWait = 60 * time.Second
+	// Default time allowed to read the next pong message from the peer.
+	defaultPongWait = 60 * time.Second
 
-	// Send pings to peer with this period. Must be less than pongWait.
-	pingPeriod = (pongWait * 9) / 10
+	// Default number of outgoing messages buffered per client.
+	defaultSendBuffer = 256
 
 	// Maximum message size allowed from peer.
 	//nolint:unused // reserved for future use
@@ -40,11 +42,20 @@ var defaultUpgrader = websocket.Upgrader{
 	},
 }
 
+// MessageTypeError is the type of frames sent to a client whose message was rejected.
+const MessageTypeError = "error"
+
 // Message represents a WebSocket message.
 type Message struct {
 	Channel string      `json:"channel"`
 	Type    string      `json:"type"`
+	ID      string      `json:"id,omitempty"` // Correlates a request with its response
 	Data    interface{} `json:"data"`
+
+	// Seq is the position of the message in its channel, starting at 1, when the
+	// hub is created with WithSequenceNumbers. Clients use it to detect gaps or
+	// reorder messages.
+	Seq uint64 `json:"seq,omitempty"`
 }
 
 // Client represents a WebSocket client connection.
@@ -53,16 +64,52 @@ type Client struct {
 	conn     *websocket.Conn
 	send     chan []byte
 	channels map[string]bool
+	protocol string
+	userID   string
 	mu       sync.RWMutex
 }
 
-// Subscribe adds the client to a channel.
+// Subprotocol returns the WebSocket subprotocol negotiated during the upgrade.
+// Returns an empty string if no subprotocol was negotiated.
+func (c *Client) Subprotocol() string {
+	return c.protocol
+}
+
+// UserID returns the user ID returned by the hub's authenticator for this
+// connection, see WithAuthenticator. Returns an empty string without one.
+func (c *Client) UserID() string {
+	return c.userID
+}
+
+// ErrTooManyChannels is returned by Client.TrySubscribe when the client has
+// reached the limit set with WithMaxChannelsPerClient.
+var ErrTooManyChannels = errors.New("too many channel subscriptions")
+
+// Subscribe adds the client to a channel. It is meant for server code and is not
+// subject to WithMaxChannelsPerClient; use TrySubscribe to enforce the limit.
 func (c *Client) Subscribe(channel string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.channels[channel] = true
 }
 
+// TrySubscribe adds the client to a channel like Subscribe, but returns
+// ErrTooManyChannels when the client is already subscribed to the maximum number
+// of channels set with WithMaxChannelsPerClient. Inbound subscribe messages use it.
+func (c *Client) TrySubscribe(channel string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.channels[channel] {
+		return nil
+	}
+	if c.hub != nil && c.hub.maxChannels > 0 && len(c.channels) >= c.hub.maxChannels {
+		return ErrTooManyChannels
+	}
+	c.channels[channel] = true
+	return nil
+}
+
 // Unsubscribe removes the client from a channel.
 func (c *Client) Unsubscribe(channel string) {
 	c.mu.Lock()
@@ -70,6 +117,18 @@ func (c *Client) Unsubscribe(channel string) {
 	delete(c.channels, channel)
 }
 
+// UnsubscribeAll removes the client from every channel, e.g. on logout, and
+// updates the hub's channel membership so it stops receiving channel broadcasts.
+func (c *Client) UnsubscribeAll() {
+	c.mu.Lock()
+	c.channels = make(map[string]bool)
+	c.mu.Unlock()
+
+	if c.hub != nil {
+		c.hub.UpdateChannelMembership(c)
+	}
+}
+
 // IsSubscribed checks if client is subscribed to a channel.
 func (c *Client) IsSubscribed(channel string) bool {
 	c.mu.RLock()
@@ -87,9 +146,9 @@ func (c *Client) readPump() {
 	}()
 
 	if c.conn != nil {
-		_ = c.conn.SetReadDeadline(time.Now().Add(pongWait))
+		_ = c.conn.SetReadDeadline(time.Now().Add(c.hub.pongWait))
 		c.conn.SetPongHandler(func(string) error {
-			_ = c.conn.SetReadDeadline(time.Now().Add(pongWait))
+			_ = c.conn.SetReadDeadline(time.Now().Add(c.hub.pongWait))
 			return nil
 		})
 	}
@@ -107,24 +166,67 @@ func (c *Client) readPump() {
 			break
 		}
 
-		// Handle subscription/unsubscription messages
-		var msg Message
-		if err := json.Unmarshal(message, &msg); err != nil {
-			continue
-		}
+		c.handleInbound(message)
+	}
+}
 
-		switch msg.Type {
-		case "subscribe":
-			c.Subscribe(msg.Channel)
-		case "unsubscribe":
+// handleInbound validates and dispatches a message received from the client.
+// Invalid messages are answered with an error frame.
+func (c *Client) handleInbound(data []byte) {
+	var msg Message
+	if err := json.Unmarshal(data, &msg); err != nil {
+		c.sendError("", "invalid message: "+err.Error())
+		return
+	}
+
+	switch msg.Type {
+	case "subscribe", "unsubscribe":
+		if msg.Channel == "" {
+			c.sendError(msg.Channel, msg.Type+" requires a channel")
+			return
+		}
+		if msg.Type == "subscribe" {
+			if err := c.TrySubscribe(msg.Channel); err != nil {
+				c.sendError(msg.Channel, err.Error())
+			}
+		} else {
 			c.Unsubscribe(msg.Channel)
 		}
+	case "":
+		c.sendError(msg.Channel, "message type is required")
+	default:
+		if handler := c.hub.requestHandler(msg.Type); handler != nil {
+			c.handleRequest(handler, msg)
+			return
+		}
+		if c.hub.messageHandler != nil {
+			if err := c.hub.messageHandler(c, msg); err != nil {
+				c.sendError(msg.Channel, err.Error())
+			}
+			return
+		}
+		if c.hub.rejectUnknown {
+			c.sendError(msg.Channel, "unknown message type: "+msg.Type)
+		}
 	}
 }
 
+// sendError queues an error frame for the client.
+func (c *Client) sendError(channel, message string) {
+	data, err := json.Marshal(&Message{
+		Channel: channel,
+		Type:    MessageTypeError,
+		Data:    map[string]string{"message": message},
+	})
+	if err != nil {
+		return
+	}
+	c.hub.sendDirect(c, data)
+}
+
 // writePump pumps messages from the hub to the WebSocket connection.
 func (c *Client) writePump() {
-	ticker := time.NewTicker(pingPeriod)
+	ticker := time.NewTicker(c.hub.pingPeriod)
 	defer c.cleanupConnection(ticker)
 
 	for {
@@ -152,7 +254,7 @@ func (c *Client) cleanupConnection(ticker *time.Ticker) {
 // handleOutgoingMessage processes an outgoing message from the send channel.
 func (c *Client) handleOutgoingMessage(message []byte, ok bool) bool {
 	if c.conn != nil {
-		_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+		_ = c.conn.SetWriteDeadline(time.Now().Add(c.hub.writeWait))
 	}
 
 	if !ok {
@@ -204,29 +306,78 @@ func (c *Client) sendPing() bool {
 		return true
 	}
 
-	_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+	_ = c.conn.SetWriteDeadline(time.Now().Add(c.hub.writeWait))
 	return c.conn.WriteMessage(websocket.PingMessage, nil) == nil
 }
 
 // Hub maintains the set of active clients and broadcasts messages to them.
 type Hub struct {
 	clients    map[*Client]bool
-	channels   map[string]map[*Client]bool
+	channels   map[string]map[*Client]bool // subscriptions by channel name
+	patterns   map[string]map[*Client]bool // subscriptions by pattern, e.g. "orders.*"
 	broadcast  chan *Message
 	register   chan *Client
 	unregister chan *Client
+	upgrader   websocket.Upgrader
+	sendBuffer int
 	mu         sync.RWMutex
+
+	writeWait  time.Duration
+	pongWait   time.Duration
+	pingPeriod time.Duration
+
+	reloadDebounce time.Duration
+	reloads        reloadDebouncer
+
+	onConnect    func(*Client)
+	onDisconnect func(*Client)
+
+	messageHandler func(*Client, Message) error
+	rejectUnknown  bool
+	handlers       map[string]RequestHandler
+	maxChannels    int
+	authenticate   func(*http.Request) (userID string, ok bool)
+
+	sequenced bool
+	seqMu     sync.Mutex // held while a sequenced message is numbered and delivered
+	sequences map[string]uint64
 }
 
 // NewHub creates a new Hub instance.
-func NewHub() *Hub {
-	return &Hub{
+func NewHub(opts ...HubOption) *Hub {
+	h := &Hub{
 		broadcast:  make(chan *Message, 256),
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
 		clients:    make(map[*Client]bool),
 		channels:   make(map[string]map[*Client]bool),
+		patterns:   make(map[string]map[*Client]bool),
+		handlers:   make(map[string]RequestHandler),
+		sequences:  make(map[string]uint64),
+		upgrader:   defaultUpgrader,
+		sendBuffer: defaultSendBuffer,
+		writeWait:  defaultWriteWait,
+		pongWait:   defaultPongWait,
+
+		reloadDebounce: defaultReloadDebounce,
+		rejectUnknown:  true,
 	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	// Pings must be sent before the peer's pong deadline expires
+	if h.pingPeriod <= 0 || h.pingPeriod >= h.pongWait {
+		defaultPeriod := (h.pongWait * 9) / 10
+		if h.pingPeriod != 0 {
+			log.Printf("realtime: ping period %v must be positive and less than the pong wait %v; using %v",
+				h.pingPeriod, h.pongWait, defaultPeriod)
+		}
+		h.pingPeriod = defaultPeriod
+	}
+
+	return h
 }
 
 // Run starts the hub's message processing loop.
@@ -259,10 +410,14 @@ func (h *Hub) shutdown() {
 // handleRegister registers a new client and adds it to its subscribed channels.
 func (h *Hub) handleRegister(client *Client) {
 	h.mu.Lock()
-	defer h.mu.Unlock()
-
 	h.clients[client] = true
 	h.addClientToChannels(client)
+	h.mu.Unlock()
+
+	// Callbacks run without h.mu held so they may call hub methods
+	if h.onConnect != nil {
+		h.onConnect(client)
+	}
 }
 
 // addClientToChannels adds a client to all its subscribed channels.
@@ -271,34 +426,54 @@ func (h *Hub) addClientToChannels(client *Client) {
 	defer client.mu.RUnlock()
 
 	for channel := range client.channels {
-		if _, ok := h.channels[channel]; !ok {
-			h.channels[channel] = make(map[*Client]bool)
-		}
-		h.channels[channel][client] = true
+		h.addSubscription(channel, client)
+	}
+}
+
+// channelIndex returns the index holding subscriptions to channel. Patterns are
+// kept apart from channel names so broadcasts only scan them when some exist.
+func (h *Hub) channelIndex(channel string) map[string]map[*Client]bool {
+	if strings.Contains(channel, "*") {
+		return h.patterns
+	}
+	return h.channels
+}
+
+// addSubscription records that client is subscribed to channel. The caller must
+// hold h.mu.
+func (h *Hub) addSubscription(channel string, client *Client) {
+	index := h.channelIndex(channel)
+	if _, ok := index[channel]; !ok {
+		index[channel] = make(map[*Client]bool)
 	}
+	index[channel][client] = true
 }
 
 // handleUnregister removes a client and cleans up its channel subscriptions.
 func (h *Hub) handleUnregister(client *Client) {
 	h.mu.Lock()
-	defer h.mu.Unlock()
-
 	if _, ok := h.clients[client]; !ok {
+		h.mu.Unlock()
 		return
 	}
 
 	delete(h.clients, client)
 	close(client.send)
 	h.removeClientFromAllChannels(client)
+	h.mu.Unlock()
+
+	if h.onDisconnect != nil {
+		h.onDisconnect(client)
+	}
 }
 
 // removeClientFromAllChannels removes a client from all channels.
 func (h *Hub) removeClientFromAllChannels(client *Client) {
-	for channel := range h.channels {
-		if clients, ok := h.channels[channel]; ok {
+	for _, index := range []map[string]map[*Client]bool{h.channels, h.patterns} {
+		for channel, clients := range index {
 			delete(clients, client)
 			if len(clients) == 0 {
-				delete(h.channels, channel)
+				delete(index, channel)
 			}
 		}
 	}
@@ -309,6 +484,15 @@ func (h *Hub) handleBroadcast(message *Message) {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
+	if h.sequenced {
+		h.seqMu.Lock()
+		defer h.seqMu.Unlock()
+
+		numbered := *message
+		numbered.Seq = h.nextSeq(message.Channel)
+		message = &numbered
+	}
+
 	data, err := json.Marshal(message)
 	if err != nil {
 		return
@@ -321,6 +505,12 @@ func (h *Hub) handleBroadcast(message *Message) {
 	}
 }
 
+// nextSeq returns the next sequence number of channel. The caller must hold h.seqMu.
+func (h *Hub) nextSeq(channel string) uint64 {
+	h.sequences[channel]++
+	return h.sequences[channel]
+}
+
 // broadcastToAll sends a message to all connected clients.
 func (h *Hub) broadcastToAll(data []byte) {
 	for client := range h.clients {
@@ -328,16 +518,43 @@ func (h *Hub) broadcastToAll(data []byte) {
 	}
 }
 
-// broadcastToChannel sends a message to all clients in a specific channel.
+// broadcastToChannel sends a message to all clients subscribed to a channel,
+// directly or through a pattern such as "orders.*".
 func (h *Hub) broadcastToChannel(channel string, data []byte) {
-	clients, ok := h.channels[channel]
-	if !ok {
-		return
+	for client := range h.subscribers(channel) {
+		h.sendToClient(client, data)
 	}
+}
 
-	for client := range clients {
-		h.sendToClient(client, data)
+// subscribers returns the clients subscribed to channel, either by name or with a
+// pattern matching it. Without matching patterns it returns the hub's own set of
+// the channel's subscribers, which the caller must not modify. The caller must
+// hold h.mu.
+func (h *Hub) subscribers(channel string) map[*Client]bool {
+	exact := h.channels[channel]
+	if len(h.patterns) == 0 {
+		return exact
 	}
+
+	var subscribed map[*Client]bool
+	for pattern, clients := range h.patterns {
+		if !matchesPattern(pattern, channel) {
+			continue
+		}
+		if subscribed == nil {
+			subscribed = make(map[*Client]bool, len(exact)+len(clients))
+			for client := range exact {
+				subscribed[client] = true
+			}
+		}
+		for client := range clients {
+			subscribed[client] = true
+		}
+	}
+	if subscribed == nil {
+		return exact
+	}
+	return subscribed
 }
 
 // sendToClient sends data to a client, unregistering if the buffer is full.
@@ -352,11 +569,34 @@ func (h *Hub) sendToClient(client *Client, data []byte) {
 	}
 }
 
+// sendDirect queues data for a single registered client, dropping it if the
+// client is gone or its buffer is full.
+func (h *Hub) sendDirect(client *Client, data []byte) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	// Clients are only closed under h.mu, so membership guarantees send is open
+	if !h.clients[client] {
+		return
+	}
+	select {
+	case client.send <- data:
+	default:
+	}
+}
+
 // Broadcast sends a message to all clients subscribed to a channel.
 func (h *Hub) Broadcast(msg *Message) {
 	h.broadcast <- msg
 }
 
+// ClientCount returns the number of connected clients.
+func (h *Hub) ClientCount() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.clients)
+}
+
 // Publish is a helper method to broadcast a message.
 func (h *Hub) Publish(channel, msgType string, data interface{}) {
 	h.Broadcast(&Message{
@@ -366,19 +606,78 @@ func (h *Hub) Publish(channel, msgType string, data interface{}) {
 	})
 }
 
-// HandleWebSocket handles WebSocket connection upgrades.
+// PublishMulti publishes one message to several channels. The data is marshaled
+// once and delivered under a single lock; a client subscribed to more than one of
+// the channels receives the message once, tagged with the first matching channel.
+func (h *Hub) PublishMulti(channels []string, msgType string, data interface{}) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.sequenced {
+		h.seqMu.Lock()
+		defer h.seqMu.Unlock()
+	}
+
+	sent := make(map[*Client]bool)
+	for _, channel := range channels {
+		msg := &Message{Channel: channel, Type: msgType, Data: json.RawMessage(payload)}
+		if h.sequenced {
+			msg.Seq = h.nextSeq(channel)
+		}
+
+		clients := h.clients
+		if channel != "*" {
+			clients = h.subscribers(channel)
+		}
+		if len(clients) == 0 {
+			continue
+		}
+
+		frame, err := json.Marshal(msg)
+		if err != nil {
+			return
+		}
+
+		for client := range clients {
+			if sent[client] {
+				continue
+			}
+			sent[client] = true
+			h.sendToClient(client, frame)
+		}
+	}
+}
+
+// ErrUnauthorized is returned by HandleWebSocket when the hub's authenticator
+// rejects the request, see WithAuthenticator.
+var ErrUnauthorized = errors.New("unauthorized WebSocket connection")
+
+// HandleWebSocket handles WebSocket connection upgrades. Requests rejected by the
+// hub's authenticator are answered with 401 Unauthorized and not upgraded.
 func (h *Hub) HandleWebSocket(w http.ResponseWriter, r *http.Request) error {
-	conn, err := defaultUpgrader.Upgrade(w, r, nil)
+	var userID string
+	if h.authenticate != nil {
+		id, ok := h.authenticate(r)
+		if !ok {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return ErrUnauthorized
+		}
+		userID = id
+	}
+
+	conn, err := h.upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		return err
 	}
 
-	client := &Client{
-		hub:      h,
-		conn:     conn,
-		send:     make(chan []byte, 256),
-		channels: make(map[string]bool),
-	}
+	client := h.newClient(conn)
+	client.protocol = conn.Subprotocol()
+	client.userID = userID
 
 	h.register <- client
 
@@ -389,28 +688,33 @@ func (h *Hub) HandleWebSocket(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
 
+// newClient creates a client for the connection using the hub's send buffer size.
+func (h *Hub) newClient(conn *websocket.Conn) *Client {
+	return &Client{
+		hub:      h,
+		conn:     conn,
+		send:     make(chan []byte, h.sendBuffer),
+		channels: make(map[string]bool),
+	}
+}
+
 // UpdateChannelMembership updates a client's channel subscriptions.
 func (h *Hub) UpdateChannelMembership(client *Client) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
 	// Remove from old channels
-	for channel, clients := range h.channels {
-		if !client.IsSubscribed(channel) {
-			delete(clients, client)
-			if len(clients) == 0 {
-				delete(h.channels, channel)
+	for _, index := range []map[string]map[*Client]bool{h.channels, h.patterns} {
+		for channel, clients := range index {
+			if !client.IsSubscribed(channel) {
+				delete(clients, client)
+				if len(clients) == 0 {
+					delete(index, channel)
+				}
 			}
 		}
 	}
 
 	// Add to new channels
-	client.mu.RLock()
-	for channel := range client.channels {
-		if _, ok := h.channels[channel]; !ok {
-			h.channels[channel] = make(map[*Client]bool)
-		}
-		h.channels[channel][client] = true
-	}
-	client.mu.RUnlock()
+	h.addClientToChannels(client)
 }
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -46,6 +47,15 @@ func TestHubRunAndStop(t *testing.T) {
 	time.Sleep(10 * time.Millisecond)
 }
 
+func TestHubClientCount(t *testing.T) {
+	hub := NewHub()
+	assert.Equal(t, 0, hub.ClientCount())
+
+	newRegisteredClient(hub)
+	newRegisteredClient(hub)
+	assert.Equal(t, 2, hub.ClientCount())
+}
+
 func TestClientSubscription(t *testing.T) {
 	hub := NewHub()
 	ctx, cancel := context.WithCancel(context.Background())
@@ -88,6 +98,48 @@ func TestClientUnsubscribe(t *testing.T) {
 	assert.False(t, client.channels["test-channel"])
 }
 
+func TestClientUnsubscribeAll(t *testing.T) {
+	hub := NewHub()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.Run(ctx)
+
+	client := hub.newClient(nil)
+	client.Subscribe("chat")
+	client.Subscribe("orders.*")
+	client.Subscribe("notifications")
+	hub.register <- client
+	time.Sleep(10 * time.Millisecond)
+
+	client.UnsubscribeAll()
+
+	assert.False(t, client.IsSubscribed("chat"))
+	assert.False(t, client.IsSubscribed("orders.*"))
+	assert.False(t, client.IsSubscribed("notifications"))
+
+	hub.mu.RLock()
+	assert.Empty(t, hub.channels)
+	assert.Empty(t, hub.patterns)
+	hub.mu.RUnlock()
+
+	hub.Publish("chat", "message", "hello")
+	hub.Publish("orders.shipped", "update", 1)
+	hub.PublishMulti([]string{"notifications"}, "alert", "hi")
+
+	select {
+	case data := <-client.send:
+		t.Fatalf("unsubscribed client received a message: %s", data)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	hub.Publish("*", "announcement", "still connected")
+	select {
+	case <-client.send:
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("Expected client to still receive broadcasts to all clients")
+	}
+}
+
 func TestHubBroadcast(t *testing.T) {
 	hub := NewHub()
 	ctx, cancel := context.WithCancel(context.Background())
@@ -215,6 +267,132 @@ func TestHubPublishMethod(t *testing.T) {
 	}
 }
 
+func TestHubPublishMulti(t *testing.T) {
+	hub := NewHub()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.Run(ctx)
+
+	posts := hub.newClient(nil)
+	posts.Subscribe("posts")
+	comments := hub.newClient(nil)
+	comments.Subscribe("comments")
+	both := hub.newClient(nil)
+	both.Subscribe("posts")
+	both.Subscribe("comments")
+	other := hub.newClient(nil)
+	other.Subscribe("other")
+
+	for _, c := range []*Client{posts, comments, both, other} {
+		hub.register <- c
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	hub.PublishMulti([]string{"posts", "comments"}, "updated", map[string]int{"id": 7})
+
+	receive := func(c *Client) Message {
+		select {
+		case data := <-c.send:
+			var msg struct {
+				Channel string          `json:"channel"`
+				Type    string          `json:"type"`
+				Data    json.RawMessage `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(data, &msg))
+			return Message{Channel: msg.Channel, Type: msg.Type, Data: string(msg.Data)}
+		case <-time.After(100 * time.Millisecond):
+			t.Fatal("Expected to receive published message")
+			return Message{}
+		}
+	}
+
+	postsMsg := receive(posts)
+	commentsMsg := receive(comments)
+	assert.Equal(t, Message{Channel: "posts", Type: "updated", Data: `{"id":7}`}, postsMsg)
+	assert.Equal(t, Message{Channel: "comments", Type: "updated", Data: `{"id":7}`}, commentsMsg)
+
+	assert.Equal(t, postsMsg, receive(both))
+	select {
+	case data := <-both.send:
+		t.Fatalf("client in both channels received a duplicate: %s", data)
+	case data := <-other.send:
+		t.Fatalf("unsubscribed client received a message: %s", data)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestHubPatternSubscription(t *testing.T) {
+	hub := NewHub()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.Run(ctx)
+
+	orders := hub.newClient(nil)
+	orders.Subscribe("orders.*")
+	both := hub.newClient(nil)
+	both.Subscribe("orders.*")
+	both.Subscribe("orders.shipped")
+	users := hub.newClient(nil)
+	users.Subscribe("users.*")
+
+	for _, c := range []*Client{orders, both, users} {
+		hub.register <- c
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	hub.Publish("orders.shipped", "update", map[string]int{"id": 42})
+
+	for _, c := range []*Client{orders, both} {
+		select {
+		case data := <-c.send:
+			var msg Message
+			require.NoError(t, json.Unmarshal(data, &msg))
+			assert.Equal(t, "orders.shipped", msg.Channel)
+			assert.Equal(t, "update", msg.Type)
+		case <-time.After(100 * time.Millisecond):
+			t.Fatal("Expected pattern subscriber to receive the message")
+		}
+	}
+
+	select {
+	case data := <-both.send:
+		t.Fatalf("client matching by name and pattern received a duplicate: %s", data)
+	case data := <-users.send:
+		t.Fatalf("client with a non-matching pattern received a message: %s", data)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestHubSubscribersIndexes(t *testing.T) {
+	hub := NewHub()
+	named := hub.newClient(nil)
+	named.Subscribe("orders.shipped")
+	hub.UpdateChannelMembership(named)
+
+	assert.Contains(t, hub.channels, "orders.shipped")
+	assert.Empty(t, hub.patterns)
+
+	// Without pattern subscriptions a broadcast doesn't allocate a subscriber set
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = hub.subscribers("orders.shipped")
+	})
+	assert.Zero(t, allocs)
+
+	pattern := hub.newClient(nil)
+	pattern.Subscribe("orders.*")
+	hub.UpdateChannelMembership(pattern)
+
+	assert.Contains(t, hub.patterns, "orders.*")
+	assert.NotContains(t, hub.channels, "orders.*")
+	assert.Equal(t, map[*Client]bool{named: true, pattern: true}, hub.subscribers("orders.shipped"))
+	assert.Equal(t, map[*Client]bool{pattern: true}, hub.subscribers("orders.created"))
+	assert.Empty(t, hub.subscribers("users.created"))
+
+	pattern.Unsubscribe("orders.*")
+	hub.UpdateChannelMembership(pattern)
+	assert.Empty(t, hub.patterns)
+}
+
 func TestClientCleanup(t *testing.T) {
 	hub := NewHub()
 	ctx, cancel := context.WithCancel(context.Background())
@@ -332,6 +510,59 @@ func TestHubConcurrentBroadcast(t *testing.T) {
 	}
 }
 
+func TestHubSequenceNumbers(t *testing.T) {
+	const messages = 100
+
+	hub := NewHub(WithSequenceNumbers(true), WithClientSendBuffer(2*messages))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.Run(ctx)
+
+	client := newRegisteredClient(hub)
+	client.Subscribe("feed")
+	hub.UpdateChannelMembership(client)
+
+	var wg sync.WaitGroup
+	for i := 0; i < messages; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i%2 == 0 {
+				hub.Publish("feed", "update", i)
+			} else {
+				hub.PublishMulti([]string{"feed", "other"}, "update", i)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	var last uint64
+	for received := 0; received < messages; received++ {
+		select {
+		case data := <-client.send:
+			var msg Message
+			require.NoError(t, json.Unmarshal(data, &msg))
+			assert.Equal(t, last+1, msg.Seq, "sequence numbers must increase by one")
+			last = msg.Seq
+		case <-time.After(time.Second):
+			t.Fatalf("received %d of %d messages", received, messages)
+		}
+	}
+}
+
+func TestHubSequenceNumbersDisabled(t *testing.T) {
+	hub := NewHub()
+	client := newRegisteredClient(hub)
+	client.Subscribe("feed")
+	hub.UpdateChannelMembership(client)
+
+	hub.handleBroadcast(&Message{Channel: "feed", Type: "update"})
+
+	frame := nextFrame(t, client)
+	require.NotNil(t, frame)
+	assert.Zero(t, frame.Seq)
+}
+
 func TestHubFilteredBroadcast(t *testing.T) {
 	hub := NewHub()
 	ctx, cancel := context.WithCancel(context.Background())
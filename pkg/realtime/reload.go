@@ -0,0 +1,95 @@
+package realtime
+
+import (
+	"sync"
+	"time"
+)
+
+// MessageTypeInertiaReload is the message type asking clients to reload Inertia props.
+const MessageTypeInertiaReload = "inertia:reload"
+
+// defaultReloadDebounce is the default window in which reload pushes are collapsed.
+const defaultReloadDebounce = 100 * time.Millisecond
+
+// ReloadData is the payload of an inertia:reload message. A frontend listener
+// typically calls router.reload({ only }) when the current page matches Component.
+type ReloadData struct {
+	Component string   `json:"component"`
+	Only      []string `json:"only,omitempty"`
+}
+
+// reloadDebouncer collapses bursts of reload pushes per channel and component.
+type reloadDebouncer struct {
+	mu      sync.Mutex
+	pending map[string]*pendingReload
+}
+
+// pendingReload is a reload waiting for its debounce window to elapse.
+type pendingReload struct {
+	data ReloadData
+	full bool // a push without "only" requested a full reload
+}
+
+// PushInertiaReload tells clients on channel that the data of component is stale
+// and that the given props should be reloaded. An empty only list requests a reload
+// of all props.
+//
+// Rapid pushes for the same channel and component are debounced: a burst produces
+// one message whose "only" list is the union of the requested props.
+func (h *Hub) PushInertiaReload(channel, component string, only []string) {
+	if h.reloadDebounce <= 0 {
+		h.Publish(channel, MessageTypeInertiaReload, ReloadData{Component: component, Only: only})
+		return
+	}
+
+	key := channel + "\x00" + component
+
+	h.reloads.mu.Lock()
+	defer h.reloads.mu.Unlock()
+
+	if h.reloads.pending == nil {
+		h.reloads.pending = make(map[string]*pendingReload)
+	}
+
+	if pending, ok := h.reloads.pending[key]; ok {
+		pending.merge(only)
+		return
+	}
+
+	pending := &pendingReload{data: ReloadData{Component: component}}
+	pending.merge(only)
+	h.reloads.pending[key] = pending
+
+	time.AfterFunc(h.reloadDebounce, func() {
+		h.reloads.mu.Lock()
+		delete(h.reloads.pending, key)
+		data := pending.data
+		if pending.full {
+			data.Only = nil
+		}
+		h.reloads.mu.Unlock()
+
+		h.Publish(channel, MessageTypeInertiaReload, data)
+	})
+}
+
+// merge adds props to the pending reload, keeping the first occurrence of each key.
+func (p *pendingReload) merge(only []string) {
+	if len(only) == 0 {
+		p.full = true
+		return
+	}
+
+	for _, key := range only {
+		found := false
+		for _, existing := range p.data.Only {
+			if existing == key {
+				found = true
+				break
+			}
+		}
+		if !found {
+			p.data.Only = append(p.data.Only, key)
+		}
+	}
+}
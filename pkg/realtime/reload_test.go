@@ -0,0 +1,77 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHubPushInertiaReload(t *testing.T) {
+	hub := NewHub(WithReloadDebounce(0))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.Run(ctx)
+
+	client := hub.newClient(nil)
+	client.Subscribe("posts")
+	hub.register <- client
+
+	hub.PushInertiaReload("posts", "Posts/Show", []string{"post", "comments"})
+
+	select {
+	case data := <-client.send:
+		var msg struct {
+			Channel string     `json:"channel"`
+			Type    string     `json:"type"`
+			Data    ReloadData `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(data, &msg))
+
+		assert.Equal(t, "posts", msg.Channel)
+		assert.Equal(t, "inertia:reload", msg.Type)
+		assert.Equal(t, "Posts/Show", msg.Data.Component)
+		assert.Equal(t, []string{"post", "comments"}, msg.Data.Only)
+	case <-time.After(time.Second):
+		t.Fatal("expected reload message")
+	}
+}
+
+func TestHubPushInertiaReload_Debounce(t *testing.T) {
+	hub := NewHub(WithReloadDebounce(30 * time.Millisecond))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.Run(ctx)
+
+	client := hub.newClient(nil)
+	client.Subscribe("posts")
+	hub.register <- client
+
+	// A burst of changes
+	hub.PushInertiaReload("posts", "Posts/Index", []string{"posts"})
+	hub.PushInertiaReload("posts", "Posts/Index", []string{"stats"})
+	hub.PushInertiaReload("posts", "Posts/Index", []string{"posts"})
+
+	var messages [][]byte
+	timeout := time.After(200 * time.Millisecond)
+collect:
+	for {
+		select {
+		case data := <-client.send:
+			messages = append(messages, data)
+		case <-timeout:
+			break collect
+		}
+	}
+
+	require.Len(t, messages, 1, "burst should collapse into a single reload")
+
+	var msg Message
+	require.NoError(t, json.Unmarshal(messages[0], &msg))
+	data := msg.Data.(map[string]interface{})
+	assert.Equal(t, "Posts/Index", data["component"])
+	assert.Equal(t, []interface{}{"posts", "stats"}, data["only"])
+}
@@ -0,0 +1,60 @@
+package realtime
+
+import "encoding/json"
+
+// MessageTypeResponse is the type of frames answering a request sent by a client.
+const MessageTypeResponse = "response"
+
+// RequestHandler answers a request sent by a client. The result is sent back to
+// the client as the data of a response frame; an error is sent as an error frame.
+type RequestHandler func(client *Client, msg Message) (interface{}, error)
+
+// Handle registers a handler for inbound messages of the given type, giving
+// clients a request/response pattern over the socket. The reply is sent only to
+// the requesting client and carries the request's ID so the client can match it:
+//
+//	hub.Handle("comments.more", func(c *realtime.Client, msg realtime.Message) (interface{}, error) {
+//		return loadComments(msg.Data)
+//	})
+//
+// A client sends {"type":"comments.more","id":"7","data":{...}} and receives
+// {"type":"response","id":"7","data":[...]}. Handlers run on the client's read
+// goroutine, so requests from one client are answered in order. Registered types
+// take precedence over the handler set with WithMessageHandler.
+func (h *Hub) Handle(msgType string, handler RequestHandler) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.handlers[msgType] = handler
+}
+
+// requestHandler returns the handler registered for a message type, if any.
+func (h *Hub) requestHandler(msgType string) RequestHandler {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.handlers[msgType]
+}
+
+// handleRequest runs a request handler and replies to the client.
+func (c *Client) handleRequest(handler RequestHandler, msg Message) {
+	result, err := handler(c, msg)
+	if err != nil {
+		c.reply(msg, MessageTypeError, map[string]string{"message": err.Error()})
+		return
+	}
+	c.reply(msg, MessageTypeResponse, result)
+}
+
+// reply queues a frame answering msg for the client.
+func (c *Client) reply(msg Message, msgType string, data interface{}) {
+	frame, err := json.Marshal(&Message{
+		Channel: msg.Channel,
+		Type:    msgType,
+		ID:      msg.ID,
+		Data:    data,
+	})
+	if err != nil {
+		c.reply(msg, MessageTypeError, map[string]string{"message": "failed to encode response: " + err.Error()})
+		return
+	}
+	c.hub.sendDirect(c, frame)
+}
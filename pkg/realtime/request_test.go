@@ -0,0 +1,84 @@
+package realtime
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHubHandle(t *testing.T) {
+	hub := NewHub(WithMessageHandler(func(*Client, Message) error {
+		return errors.New("message handler should not be called")
+	}))
+	hub.Handle("comments.more", func(_ *Client, msg Message) (interface{}, error) {
+		params := msg.Data.(map[string]interface{})
+		if params["page"] == nil {
+			return nil, errors.New("page is required")
+		}
+		return map[string]interface{}{"page": params["page"], "comments": []string{"first", "second"}}, nil
+	})
+
+	t.Run("response carries the request ID", func(t *testing.T) {
+		client := newRegisteredClient(hub)
+		other := newRegisteredClient(hub)
+
+		client.handleInbound([]byte(`{"type":"comments.more","channel":"post.1","id":"req-1","data":{"page":2}}`))
+
+		frame := nextFrame(t, client)
+		require.NotNil(t, frame)
+		assert.Equal(t, MessageTypeResponse, frame.Type)
+		assert.Equal(t, "req-1", frame.ID)
+		assert.Equal(t, "post.1", frame.Channel)
+		assert.Equal(t, map[string]interface{}{
+			"page":     float64(2),
+			"comments": []interface{}{"first", "second"},
+		}, frame.Data)
+		assert.Nil(t, nextFrame(t, other), "only the requesting client receives the response")
+	})
+
+	t.Run("handler error", func(t *testing.T) {
+		client := newRegisteredClient(hub)
+		client.handleInbound([]byte(`{"type":"comments.more","id":"req-2","data":{}}`))
+
+		frame := nextFrame(t, client)
+		require.NotNil(t, frame)
+		assert.Equal(t, MessageTypeError, frame.Type)
+		assert.Equal(t, "req-2", frame.ID)
+		assert.Equal(t, "page is required", frame.Data.(map[string]interface{})["message"])
+	})
+
+	t.Run("unregistered types use the message handler", func(t *testing.T) {
+		client := newRegisteredClient(hub)
+		client.handleInbound([]byte(`{"type":"typing","id":"req-3"}`))
+
+		frame := nextFrame(t, client)
+		require.NotNil(t, frame)
+		assert.Equal(t, "message handler should not be called", frame.Data.(map[string]interface{})["message"])
+	})
+}
+
+func TestHubHandle_RoundTrip(t *testing.T) {
+	hub := NewHub()
+	hub.Handle("ping", func(_ *Client, msg Message) (interface{}, error) {
+		return map[string]interface{}{"pong": msg.Data}, nil
+	})
+
+	conn, resp, err := websocket.DefaultDialer.Dial(startHubServer(t, hub), nil)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	defer conn.Close()
+
+	require.NoError(t, conn.WriteJSON(Message{Type: "ping", ID: "42", Data: "hello"}))
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	var reply Message
+	require.NoError(t, conn.ReadJSON(&reply))
+
+	assert.Equal(t, MessageTypeResponse, reply.Type)
+	assert.Equal(t, "42", reply.ID)
+	assert.Equal(t, map[string]interface{}{"pong": "hello"}, reply.Data)
+}
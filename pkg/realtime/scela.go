@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"sync"
+	"sync/atomic"
 
 	"github.com/toutaio/toutago-scela-bus/pkg/scela"
 )
@@ -14,8 +15,24 @@ type ScelaAdapter struct {
 	hub          *Hub
 	subscription scela.Subscription
 	filter       MessageFilter
+	reloads      []reloadRoute
 	mu           sync.RWMutex
 	closed       bool
+
+	forwarded atomic.Uint64
+	dropped   atomic.Uint64
+	filtered  atomic.Uint64
+}
+
+// ScelaStats reports message counters of a ScelaAdapter.
+type ScelaStats struct {
+	// ForwardedCount is the number of deliveries queued to clients.
+	ForwardedCount uint64
+	// DroppedCount is the number of deliveries skipped because the client's send
+	// buffer was full, a sign of slow consumers.
+	DroppedCount uint64
+	// FilteredCount is the number of messages rejected by the filter.
+	FilteredCount uint64
 }
 
 // MessageFilter determines if a message should be forwarded to WebSocket.
@@ -31,6 +48,35 @@ func WithFilter(filter MessageFilter) ScelaOption {
 	}
 }
 
+// ReloadRoute describes the inertia:reload message sent when a bus topic fires.
+// Channel is the WebSocket channel to notify; when empty, the message topic is used.
+type ReloadRoute struct {
+	Channel   string
+	Component string
+	Only      []string
+}
+
+// reloadRoute binds a topic pattern to a reload route.
+type reloadRoute struct {
+	topic string
+	route ReloadRoute
+}
+
+// WithReloadRoute maps a bus topic to an inertia:reload message. Instead of being
+// forwarded as-is, messages on a matching topic tell clients viewing
+// route.Component to reload route.Only (all props when empty). The topic accepts
+// the same patterns as channel subscriptions, e.g. "post.*".
+//
+//	realtime.WithReloadRoute("post.updated", realtime.ReloadRoute{
+//		Component: "Posts/Show",
+//		Only:      []string{"post"},
+//	})
+func WithReloadRoute(topic string, route ReloadRoute) ScelaOption {
+	return func(a *ScelaAdapter) {
+		a.reloads = append(a.reloads, reloadRoute{topic: topic, route: route})
+	}
+}
+
 // NewScelaAdapter creates a new Scéla-to-WebSocket adapter.
 func NewScelaAdapter(bus scela.Bus, hub *Hub, opts ...ScelaOption) *ScelaAdapter {
 	adapter := &ScelaAdapter{
@@ -64,6 +110,11 @@ func (a *ScelaAdapter) handleMessage(_ context.Context, msg scela.Message) error
 
 	// Apply filter if set
 	if a.filter != nil && !a.filter(msg.Topic(), msg.Payload()) {
+		a.filtered.Add(1)
+		return nil
+	}
+
+	if a.pushReloads(msg.Topic()) {
 		return nil
 	}
 
@@ -95,8 +146,10 @@ func (a *ScelaAdapter) handleMessage(_ context.Context, msg scela.Message) error
 		if matched {
 			select {
 			case client.send <- data:
+				a.forwarded.Add(1)
 			default:
 				// Client buffer full, skip
+				a.dropped.Add(1)
 			}
 		}
 	}
@@ -104,6 +157,35 @@ func (a *ScelaAdapter) handleMessage(_ context.Context, msg scela.Message) error
 	return nil
 }
 
+// Stats returns a snapshot of the adapter's message counters. Deliveries are
+// counted per client, so one message sent to three clients forwards three times.
+func (a *ScelaAdapter) Stats() ScelaStats {
+	return ScelaStats{
+		ForwardedCount: a.forwarded.Load(),
+		DroppedCount:   a.dropped.Load(),
+		FilteredCount:  a.filtered.Load(),
+	}
+}
+
+// pushReloads sends an inertia:reload message for every reload route matching
+// topic and reports whether any matched.
+func (a *ScelaAdapter) pushReloads(topic string) bool {
+	matched := false
+	for _, r := range a.reloads {
+		if !matchesPattern(r.topic, topic) {
+			continue
+		}
+		matched = true
+
+		channel := r.route.Channel
+		if channel == "" {
+			channel = topic
+		}
+		a.hub.PushInertiaReload(channel, r.route.Component, r.route.Only)
+	}
+	return matched
+}
+
 // matchesPattern checks if a channel pattern matches a topic.
 func matchesPattern(pattern, topic string) bool {
 	// Exact match
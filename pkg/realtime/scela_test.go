@@ -306,3 +306,101 @@ func TestScelaAdapter_ContextCancellation(t *testing.T) {
 		t.Fatal("adapter should not be nil")
 	}
 }
+
+func TestScelaAdapter_ReloadRoute(t *testing.T) {
+	bus := scela.New()
+	defer bus.Close()
+
+	hub := NewHub(WithReloadDebounce(0))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.Run(ctx)
+
+	adapter := NewScelaAdapter(bus, hub, WithReloadRoute("post.updated", ReloadRoute{
+		Channel:   "posts",
+		Component: "Posts/Show",
+		Only:      []string{"post"},
+	}))
+	defer adapter.Close()
+
+	client := hub.newClient(nil)
+	client.Subscribe("posts")
+	hub.register <- client
+	time.Sleep(10 * time.Millisecond)
+
+	err := bus.PublishSync(context.Background(), "post.updated", map[string]interface{}{"id": 1})
+	if err != nil {
+		t.Fatalf("Failed to publish: %v", err)
+	}
+
+	select {
+	case data := <-client.send:
+		var msg struct {
+			Channel string     `json:"channel"`
+			Type    string     `json:"type"`
+			Data    ReloadData `json:"data"`
+		}
+		if err := json.Unmarshal(data, &msg); err != nil {
+			t.Fatalf("Failed to unmarshal: %v", err)
+		}
+		if msg.Type != MessageTypeInertiaReload {
+			t.Errorf("Expected type=%s, got %s", MessageTypeInertiaReload, msg.Type)
+		}
+		if msg.Channel != "posts" {
+			t.Errorf("Expected channel=posts, got %s", msg.Channel)
+		}
+		if msg.Data.Component != "Posts/Show" || len(msg.Data.Only) != 1 || msg.Data.Only[0] != "post" {
+			t.Errorf("Unexpected reload data: %+v", msg.Data)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for reload message")
+	}
+}
+
+func TestScelaAdapter_Stats(t *testing.T) {
+	bus := scela.New()
+	defer bus.Close()
+
+	hub := NewHub()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.Run(ctx)
+
+	adapter := NewScelaAdapter(bus, hub, WithFilter(func(topic string, _ interface{}) bool {
+		return topic != "ignored"
+	}))
+	defer adapter.Close()
+
+	// A tiny buffer that nothing drains overflows after two messages
+	client := &Client{
+		hub:      hub,
+		send:     make(chan []byte, 2),
+		channels: make(map[string]bool),
+	}
+	client.Subscribe("events")
+	hub.register <- client
+	time.Sleep(10 * time.Millisecond)
+
+	for n := 0; n < 5; n++ {
+		if err := bus.PublishSync(context.Background(), "events", map[string]int{"n": n}); err != nil {
+			t.Fatalf("Failed to publish: %v", err)
+		}
+	}
+	if err := bus.PublishSync(context.Background(), "ignored", "skip"); err != nil {
+		t.Fatalf("Failed to publish: %v", err)
+	}
+
+	stats := adapter.Stats()
+	if stats.ForwardedCount != 2 {
+		t.Errorf("Expected 2 forwarded, got %d", stats.ForwardedCount)
+	}
+	if stats.DroppedCount != 3 {
+		t.Errorf("Expected 3 dropped, got %d", stats.DroppedCount)
+	}
+	if stats.FilteredCount != 1 {
+		t.Errorf("Expected 1 filtered, got %d", stats.FilteredCount)
+	}
+	if len(client.send) != 2 {
+		t.Errorf("Expected 2 buffered messages, got %d", len(client.send))
+	}
+}
@@ -5,7 +5,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"rogchap.com/v8go"
@@ -14,15 +17,47 @@ import (
 type Config struct {
 	PoolSize int
 	Timeout  time.Duration
+
+	// IdleContextTimeout closes pooled contexts that have not been used for this
+	// long. Closed contexts are recreated lazily on demand. Zero disables reaping.
+	IdleContextTimeout time.Duration
+
+	// MinIdleContexts is the number of contexts per bundle kept alive by the reaper.
+	MinIdleContexts int
 }
 
 type Renderer struct {
-	config *Config
-	iso    *v8go.Isolate
-	bundle string
-	pool   chan *v8go.Context
-	mu     sync.RWMutex
-	closed bool
+	config  *Config
+	iso     *v8go.Isolate
+	bundles map[string]*bundle
+	stop    chan struct{}
+	mu      sync.RWMutex
+	closed  bool
+}
+
+// DefaultBundle is the name of the bundle used by LoadBundle and RenderToString.
+const DefaultBundle = ""
+
+// ServerPropsKey is the page data key holding props that only the bundle may see.
+// They are removed from the page and passed to global.render(page, server) and
+// global.renderStream(page, server) as the second argument, so a bundle that
+// serializes the page into its output does not expose them.
+const ServerPropsKey = "serverProps"
+
+// bundle is a loaded SSR entry point with its own pool of V8 contexts.
+type bundle struct {
+	source     string
+	generation uint64 // incremented whenever source is replaced
+	sourceMap  *sourceMap
+	pool       chan *pooledContext
+	executions atomic.Int64 // number of times the source was run in a context
+}
+
+// pooledContext is a V8 context together with the time it was last used.
+type pooledContext struct {
+	ctx        *v8go.Context
+	lastUsed   time.Time
+	generation uint64 // bundle generation executed in ctx, 0 when none
 }
 
 func NewRenderer(cfg ...*Config) (*Renderer, error) {
@@ -37,24 +72,133 @@ func NewRenderer(cfg ...*Config) (*Renderer, error) {
 		if cfg[0].Timeout > 0 {
 			config.Timeout = cfg[0].Timeout
 		}
+		config.IdleContextTimeout = cfg[0].IdleContextTimeout
+		config.MinIdleContexts = cfg[0].MinIdleContexts
 	}
 
 	iso := v8go.NewIsolate()
 	r := &Renderer{
-		config: config,
-		iso:    iso,
-		pool:   make(chan *v8go.Context, config.PoolSize),
+		config:  config,
+		iso:     iso,
+		bundles: make(map[string]*bundle),
+		stop:    make(chan struct{}),
 	}
+	r.bundles[DefaultBundle] = r.newBundle("")
 
-	for i := 0; i < config.PoolSize; i++ {
-		ctx := v8go.NewContext(iso)
-		r.pool <- ctx
+	if config.IdleContextTimeout > 0 {
+		go r.reapIdleContexts()
 	}
 
 	return r, nil
 }
 
+// newBundle creates a bundle with a full pool of fresh contexts.
+func (r *Renderer) newBundle(source string) *bundle {
+	b := &bundle{
+		source:     source,
+		generation: 1,
+		pool:       make(chan *pooledContext, r.config.PoolSize),
+	}
+	for i := 0; i < r.config.PoolSize; i++ {
+		b.pool <- &pooledContext{ctx: v8go.NewContext(r.iso), lastUsed: time.Now()}
+	}
+	return b
+}
+
+// acquire takes a context from the bundle's pool, creating one if the pool is empty.
+func (r *Renderer) acquire(b *bundle) *pooledContext {
+	select {
+	case pc := <-b.pool:
+		return pc
+	default:
+		return &pooledContext{ctx: v8go.NewContext(r.iso)}
+	}
+}
+
+// release returns a context to the bundle's pool, closing it if the pool is full.
+func (r *Renderer) release(b *bundle, pc *pooledContext) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.closed {
+		return
+	}
+
+	pc.lastUsed = time.Now()
+	select {
+	case b.pool <- pc:
+	default:
+		pc.ctx.Close()
+	}
+}
+
+// reapIdleContexts periodically closes contexts idle longer than IdleContextTimeout.
+func (r *Renderer) reapIdleContexts() {
+	interval := r.config.IdleContextTimeout / 2
+	if interval <= 0 {
+		interval = r.config.IdleContextTimeout
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			r.reap(time.Now().Add(-r.config.IdleContextTimeout))
+		}
+	}
+}
+
+// reap closes pooled contexts last used before cutoff, keeping MinIdleContexts per bundle.
+func (r *Renderer) reap(cutoff time.Time) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.closed {
+		return
+	}
+
+	for _, b := range r.bundles {
+		n := len(b.pool)
+		kept := make([]*pooledContext, 0, n)
+		for i := 0; i < n; i++ {
+			var pc *pooledContext
+			select {
+			case pc = <-b.pool:
+			default:
+			}
+			if pc == nil {
+				break
+			}
+
+			if pc.lastUsed.After(cutoff) || len(kept) < r.config.MinIdleContexts {
+				kept = append(kept, pc)
+				continue
+			}
+			pc.ctx.Close()
+		}
+
+		for _, pc := range kept {
+			select {
+			case b.pool <- pc:
+			default:
+				pc.ctx.Close()
+			}
+		}
+	}
+}
+
 func (r *Renderer) LoadBundle(bundle string) error {
+	return r.LoadBundleNamed(DefaultBundle, bundle)
+}
+
+// LoadBundleNamed loads an SSR bundle under the given name, so a single renderer
+// can serve several apps (e.g. "admin" and "public"). Each named bundle gets its
+// own pool of contexts. Loading a name again replaces its bundle.
+func (r *Renderer) LoadBundleNamed(name, source string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -69,99 +213,439 @@ func (r *Renderer) LoadBundle(bundle string) error {
 		return fmt.Errorf("failed to setup global: %w", err)
 	}
 
-	_, err := ctx.RunScript(bundle, "bundle.js")
+	_, err := ctx.RunScript(source, "bundle.js")
 	if err != nil {
 		return fmt.Errorf("failed to load bundle: %w", err)
 	}
 
-	r.bundle = bundle
+	// Fail fast on misconfigured bundles instead of on the first request
+	kind, err := ctx.RunScript("typeof global.render", "check.js")
+	if err != nil {
+		return fmt.Errorf("failed to inspect bundle: %w", err)
+	}
+	if kind.String() != "function" {
+		return fmt.Errorf("invalid bundle: global.render must be a function, got %s", kind.String())
+	}
+
+	if b, ok := r.bundles[name]; ok {
+		// Pooled contexts run the new source on their next use
+		b.source = source
+		b.generation++
+		return nil
+	}
+
+	r.bundles[name] = r.newBundle(source)
 	return nil
 }
 
-func (r *Renderer) RenderToString(ctx context.Context, pageData map[string]interface{}) (string, error) {
+// Warmup runs the loaded bundles in every pooled context, so the first renders
+// after startup don't pay for executing the bundle. It returns the first bundle
+// error. Contexts in use by concurrent renders are skipped.
+func (r *Renderer) Warmup() error {
 	r.mu.RLock()
 	if r.closed {
 		r.mu.RUnlock()
-		return "", errors.New("renderer is closed")
+		return errors.New("renderer is closed")
+	}
+	names := make([]string, 0, len(r.bundles))
+	bundles := make([]*bundle, 0, len(r.bundles))
+	for name, b := range r.bundles {
+		names = append(names, name)
+		bundles = append(bundles, b)
 	}
 	r.mu.RUnlock()
 
-	timeout := r.config.Timeout
-	if deadline, ok := ctx.Deadline(); ok {
-		timeout = time.Until(deadline)
+	for i, b := range bundles {
+		if err := r.warmup(b); err != nil {
+			return fmt.Errorf("failed to warm up bundle %q: %w", names[i], err)
+		}
 	}
+	return nil
+}
 
-	resultCh := make(chan string, 1)
-	errCh := make(chan error, 1)
+// warmup prepares every context currently in the bundle's pool.
+func (r *Renderer) warmup(b *bundle) error {
+	n := len(b.pool)
+	taken := make([]*pooledContext, 0, n)
+	defer func() {
+		for _, pc := range taken {
+			r.release(b, pc)
+		}
+	}()
 
+	for i := 0; i < n; i++ {
+		select {
+		case pc := <-b.pool:
+			taken = append(taken, pc)
+			if err := r.prepare(b, pc); err != nil {
+				return err
+			}
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// prepare runs the bundle's current source in the context unless it already ran.
+func (r *Renderer) prepare(b *bundle, pc *pooledContext) error {
+	r.mu.RLock()
+	source := b.source
+	generation := b.generation
+	r.mu.RUnlock()
+
+	if pc.generation == generation {
+		return nil
+	}
+
+	if _, err := pc.ctx.RunScript("var global = globalThis;", "setup.js"); err != nil {
+		return fmt.Errorf("failed to setup global: %w", err)
+	}
+
+	if source != "" {
+		b.executions.Add(1)
+		if _, err := pc.ctx.RunScript(source, "bundle.js"); err != nil {
+			return fmt.Errorf("failed to run bundle: %w", err)
+		}
+	}
+
+	pc.generation = generation
+	return nil
+}
+
+// Ping checks that the renderer can render: the default bundle is loaded, runs
+// in a pooled context and defines the global render function. It does not call
+// render, so pages are not affected.
+func (r *Renderer) Ping(ctx context.Context) error {
+	r.mu.RLock()
+	if r.closed {
+		r.mu.RUnlock()
+		return errors.New("renderer is closed")
+	}
+	b, ok := r.bundles[DefaultBundle]
+	r.mu.RUnlock()
+
+	if !ok {
+		return errors.New("no bundle is loaded")
+	}
+
+	errCh := make(chan error, 1)
 	go func() {
-		html, err := r.render(pageData)
+		pc := r.acquire(b)
+		defer r.release(b, pc)
+
+		if err := r.prepare(b, pc); err != nil {
+			errCh <- err
+			return
+		}
+		val, err := pc.ctx.RunScript("typeof global.render === 'function'", "ping.js")
 		if err != nil {
 			errCh <- err
 			return
 		}
-		resultCh <- html
+		if !val.Boolean() {
+			errCh <- errors.New("render function not found")
+			return
+		}
+		errCh <- nil
 	}()
 
 	select {
 	case <-ctx.Done():
-		return "", ctx.Err()
+		return ctx.Err()
 	case err := <-errCh:
+		return err
+	case <-time.After(r.config.Timeout):
+		return errors.New("ping timeout")
+	}
+}
+
+// LoadSourceMap attaches a version 3 source map to the bundle loaded under name,
+// so stack traces in RenderError point at original sources instead of the bundle.
+func (r *Renderer) LoadSourceMap(name string, data []byte) error {
+	sm, err := parseSourceMap(data)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b, ok := r.bundles[name]
+	if !ok {
+		return fmt.Errorf("bundle %q is not loaded", name)
+	}
+	b.sourceMap = sm
+	return nil
+}
+
+// Bundles returns the names of all loaded bundles, excluding the default bundle.
+func (r *Renderer) Bundles() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.bundles))
+	for name := range r.bundles {
+		if name != DefaultBundle {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (r *Renderer) RenderToString(ctx context.Context, pageData map[string]interface{}) (string, error) {
+	return r.RenderToStringNamed(ctx, DefaultBundle, pageData)
+}
+
+// RenderToStringNamed renders the page using the bundle loaded under name.
+func (r *Renderer) RenderToStringNamed(
+	ctx context.Context,
+	name string,
+	pageData map[string]interface{},
+) (string, error) {
+	b, err := r.lookup(name)
+	if err != nil {
 		return "", err
-	case html := <-resultCh:
-		return html, nil
-	case <-time.After(timeout):
-		return "", errors.New("render timeout")
 	}
+
+	return runWithTimeout(ctx, r.config.Timeout, func() (string, error) {
+		return r.render(b, pageData)
+	})
 }
 
-func (r *Renderer) render(pageData map[string]interface{}) (string, error) {
-	var v8ctx *v8go.Context
-	select {
-	case v8ctx = <-r.pool:
-		defer func() { r.pool <- v8ctx }()
-	default:
-		v8ctx = v8go.NewContext(r.iso)
-		defer v8ctx.Close()
+// RenderStream renders the page with the default bundle and writes the body HTML
+// to w, flushing after each chunk when w implements Flush (as
+// http.ResponseWriter usually does).
+//
+// Bundles may define global.renderStream(page), next to global.render, returning
+// an array of HTML chunks, e.g. the pieces of a renderToPipeableStream result;
+// otherwise the body returned by global.render is written as a single chunk. The bundle runs
+// to completion before the first chunk is written, so w receives nothing when
+// rendering fails. Head tags returned by render are dropped, since a streamed
+// response has already sent its <head>.
+func (r *Renderer) RenderStream(ctx context.Context, pageData map[string]interface{}, w io.Writer) error {
+	b, err := r.lookup(DefaultBundle)
+	if err != nil {
+		return err
 	}
 
-	if _, err := v8ctx.RunScript("var global = globalThis;", "setup.js"); err != nil {
-		return "", fmt.Errorf("failed to setup global: %w", err)
+	chunks, err := runWithTimeout(ctx, r.config.Timeout, func() ([]string, error) {
+		return r.renderChunks(b, pageData)
+	})
+	if err != nil {
+		return err
 	}
 
-	if r.bundle != "" {
-		if _, err := v8ctx.RunScript(r.bundle, "bundle.js"); err != nil {
-			return "", fmt.Errorf("failed to re-run bundle: %w", err)
+	flusher, _ := w.(interface{ Flush() })
+	for _, chunk := range chunks {
+		if _, err := io.WriteString(w, chunk); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
 		}
 	}
+	return nil
+}
 
-	pageJSON, err := json.Marshal(pageData)
+// lookup returns the bundle loaded under name.
+func (r *Renderer) lookup(name string) (*bundle, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.closed {
+		return nil, errors.New("renderer is closed")
+	}
+	b, ok := r.bundles[name]
+	if !ok {
+		return nil, fmt.Errorf("bundle %q is not loaded", name)
+	}
+	return b, nil
+}
+
+// runWithTimeout runs fn in its own goroutine and waits for its result until
+// ctx is done or the timeout, shortened to the ctx deadline if any, expires.
+func runWithTimeout[T any](ctx context.Context, timeout time.Duration, fn func() (T, error)) (T, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+
+	type result struct {
+		value T
+		err   error
+	}
+	resultCh := make(chan result, 1)
+
+	go func() {
+		value, err := fn()
+		resultCh <- result{value: value, err: err}
+	}()
+
+	var zero T
+	select {
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	case res := <-resultCh:
+		return res.value, res.err
+	case <-time.After(timeout):
+		return zero, errors.New("render timeout")
+	}
+}
+
+func (r *Renderer) render(b *bundle, pageData map[string]interface{}) (string, error) {
+	r.mu.RLock()
+	sm := b.sourceMap
+	r.mu.RUnlock()
+
+	pc := r.acquire(b)
+	defer r.release(b, pc)
+	v8ctx := pc.ctx
+
+	if err := r.prepare(b, pc); err != nil {
+		return "", err
+	}
+
+	pageJSON, serverJSON, err := marshalPageData(pageData)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal page data: %w", err)
+		return "", err
 	}
 
 	script := fmt.Sprintf(`
 		(function() {
 			var page = %s;
+			var server = %s;
 			if (typeof global.render !== 'function') {
 				throw new Error('render function not found');
 			}
-			var result = global.render(page);
+			var result = global.render(page, server);
 			if (typeof result === 'object' && result !== null) {
 				return JSON.stringify(result);
 			}
 			return result;
 		})();
-	`, string(pageJSON))
+	`, pageJSON, serverJSON)
 
 	val, err := v8ctx.RunScript(script, "render.js")
 	if err != nil {
-		return "", fmt.Errorf("render failed: %w", err)
+		return "", newRenderError(err, sm)
 	}
 
 	return val.String(), nil
 }
 
+// renderChunks renders the page into body HTML chunks, see RenderStream.
+func (r *Renderer) renderChunks(b *bundle, pageData map[string]interface{}) ([]string, error) {
+	r.mu.RLock()
+	sm := b.sourceMap
+	r.mu.RUnlock()
+
+	pc := r.acquire(b)
+	defer r.release(b, pc)
+
+	if err := r.prepare(b, pc); err != nil {
+		return nil, err
+	}
+
+	pageJSON, serverJSON, err := marshalPageData(pageData)
+	if err != nil {
+		return nil, err
+	}
+
+	script := fmt.Sprintf(`
+		(function() {
+			var page = %s;
+			var server = %s;
+			if (typeof global.renderStream === 'function') {
+				return JSON.stringify([].concat(global.renderStream(page, server)).map(String));
+			}
+			if (typeof global.render !== 'function') {
+				throw new Error('render function not found');
+			}
+			var result = global.render(page, server);
+			if (typeof result === 'object' && result !== null) {
+				result = result.body || result.html || '';
+			}
+			return JSON.stringify([String(result)]);
+		})();
+	`, pageJSON, serverJSON)
+
+	val, err := pc.ctx.RunScript(script, "render-stream.js")
+	if err != nil {
+		return nil, newRenderError(err, sm)
+	}
+
+	var chunks []string
+	if err := json.Unmarshal([]byte(val.String()), &chunks); err != nil {
+		return nil, fmt.Errorf("failed to decode rendered chunks: %w", err)
+	}
+	return chunks, nil
+}
+
+// marshalPageData encodes the page and, separately, the props under
+// ServerPropsKey, which default to an empty object.
+func marshalPageData(pageData map[string]interface{}) (pageJSON, serverJSON string, err error) {
+	server, hasServer := pageData[ServerPropsKey]
+	if hasServer {
+		page := make(map[string]interface{}, len(pageData)-1)
+		for key, value := range pageData {
+			if key != ServerPropsKey {
+				page[key] = value
+			}
+		}
+		pageData = page
+	} else {
+		server = map[string]interface{}{}
+	}
+
+	pageBytes, err := json.Marshal(pageData)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to marshal page data: %w", err)
+	}
+	serverBytes, err := json.Marshal(server)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to marshal server props: %w", err)
+	}
+	return string(pageBytes), string(serverBytes), nil
+}
+
+// RenderError is returned when the bundle's render function throws. Stack holds
+// the V8 stack trace, with bundle positions mapped to original sources when a
+// source map was loaded for the bundle.
+type RenderError struct {
+	Message string
+	Stack   string
+
+	err error // the error returned by V8, usually a *v8go.JSError
+}
+
+func (e *RenderError) Error() string {
+	if e.Stack == "" {
+		return "render failed: " + e.Message
+	}
+	return "render failed: " + e.Message + "\n" + e.Stack
+}
+
+// Unwrap returns the underlying V8 error.
+func (e *RenderError) Unwrap() error {
+	return e.err
+}
+
+// newRenderError converts a V8 exception into a RenderError, mapping its stack
+// through sm when one is loaded.
+func newRenderError(err error, sm *sourceMap) *RenderError {
+	var jsErr *v8go.JSError
+	if !errors.As(err, &jsErr) {
+		return &RenderError{Message: err.Error(), err: err}
+	}
+
+	stack := jsErr.StackTrace
+	if sm != nil {
+		stack = sm.mapStack(stack)
+	}
+	return &RenderError{Message: jsErr.Message, Stack: stack, err: err}
+}
+
 func (r *Renderer) Close() error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -171,10 +655,12 @@ func (r *Renderer) Close() error {
 	}
 
 	r.closed = true
-	close(r.pool)
-
-	for ctx := range r.pool {
-		ctx.Close()
+	close(r.stop)
+	for _, b := range r.bundles {
+		close(b.pool)
+		for pc := range b.pool {
+			pc.ctx.Close()
+		}
 	}
 
 	if r.iso != nil {
@@ -3,8 +3,12 @@ package ssr
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
 	"time"
+
+	"rogchap.com/v8go"
 )
 
 func TestNewRenderer(t *testing.T) {
@@ -66,6 +70,21 @@ func TestLoadBundle(t *testing.T) {
 			t.Error("expected error for invalid JavaScript, got nil")
 		}
 	})
+
+	t.Run("rejects bundle without render function", func(t *testing.T) {
+		for _, bundle := range []string{
+			`global.renderPage = function(page) { return ''; };`,
+			`global.render = '<div></div>';`,
+		} {
+			err := r.LoadBundleNamed("broken", bundle)
+			if err == nil || !contains(err.Error(), "global.render must be a function") {
+				t.Errorf("expected descriptive error for %q, got %v", bundle, err)
+			}
+		}
+		if len(r.Bundles()) != 0 {
+			t.Errorf("invalid bundle should not be registered, got %v", r.Bundles())
+		}
+	})
 }
 
 func TestRenderToString(t *testing.T) {
@@ -150,6 +169,44 @@ func TestRenderToString(t *testing.T) {
 	})
 }
 
+func TestRenderToString_ServerProps(t *testing.T) {
+	r, err := NewRenderer()
+	if err != nil {
+		t.Fatalf("failed to create renderer: %v", err)
+	}
+	defer r.Close()
+
+	bundle := `
+		global.render = function(page, server) {
+			return JSON.stringify(page) + '|' + server.csrf;
+		};
+	`
+	if err := r.LoadBundle(bundle); err != nil {
+		t.Fatalf("failed to load bundle: %v", err)
+	}
+
+	pageData := map[string]interface{}{
+		"component":    "Auth/Login",
+		"props":        map[string]interface{}{"title": "Login"},
+		ServerPropsKey: map[string]interface{}{"csrf": "s3cr3t"},
+	}
+	html, err := r.RenderToString(context.Background(), pageData)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	page, csrf, _ := strings.Cut(html, "|")
+	if csrf != "s3cr3t" {
+		t.Errorf("expected server props as second argument, got %q", csrf)
+	}
+	if strings.Contains(page, "s3cr3t") || strings.Contains(page, ServerPropsKey) {
+		t.Errorf("expected server props to be kept out of the page, got %s", page)
+	}
+	if _, ok := pageData[ServerPropsKey]; !ok {
+		t.Error("expected the caller's page data to be left untouched")
+	}
+}
+
 func TestContextPooling(t *testing.T) {
 	cfg := &Config{
 		PoolSize: 2,
@@ -190,6 +247,160 @@ func TestContextPooling(t *testing.T) {
 	})
 }
 
+func TestWarmup(t *testing.T) {
+	r, err := NewRenderer(&Config{PoolSize: 3})
+	if err != nil {
+		t.Fatalf("failed to create renderer: %v", err)
+	}
+	defer r.Close()
+
+	if err := r.LoadBundle(`global.render = function(page) { return '<div>v1:' + page.component + '</div>'; };`); err != nil {
+		t.Fatalf("failed to load bundle: %v", err)
+	}
+	b := r.bundles[DefaultBundle]
+
+	t.Run("runs the bundle in every pooled context", func(t *testing.T) {
+		if err := r.Warmup(); err != nil {
+			t.Fatalf("warmup failed: %v", err)
+		}
+		if n := b.executions.Load(); n != 3 {
+			t.Fatalf("expected bundle to run in 3 contexts, ran %d times", n)
+		}
+	})
+
+	t.Run("renders reuse warmed contexts", func(t *testing.T) {
+		for i := 0; i < 5; i++ {
+			html, err := r.RenderToString(context.Background(), map[string]interface{}{"component": "Home"})
+			if err != nil {
+				t.Fatalf("render failed: %v", err)
+			}
+			if html != "<div>v1:Home</div>" {
+				t.Errorf("unexpected HTML: %s", html)
+			}
+		}
+		if n := b.executions.Load(); n != 3 {
+			t.Errorf("expected no further bundle executions, got %d", n)
+		}
+	})
+
+	t.Run("reloaded bundles run again", func(t *testing.T) {
+		if err := r.LoadBundle(`global.render = function(page) { return '<div>v2:' + page.component + '</div>'; };`); err != nil {
+			t.Fatalf("failed to reload bundle: %v", err)
+		}
+
+		html, err := r.RenderToString(context.Background(), map[string]interface{}{"component": "Home"})
+		if err != nil {
+			t.Fatalf("render failed: %v", err)
+		}
+		if html != "<div>v2:Home</div>" {
+			t.Errorf("expected reloaded bundle to render, got %s", html)
+		}
+		if n := b.executions.Load(); n != 4 {
+			t.Errorf("expected reloaded bundle to run once, got %d executions", n)
+		}
+	})
+
+	t.Run("closed renderer", func(t *testing.T) {
+		closed, err := NewRenderer(&Config{PoolSize: 1})
+		if err != nil {
+			t.Fatalf("failed to create renderer: %v", err)
+		}
+		closed.Close()
+
+		if err := closed.Warmup(); err == nil {
+			t.Error("expected error warming up a closed renderer")
+		}
+	})
+}
+
+func TestPing(t *testing.T) {
+	r, err := NewRenderer(&Config{PoolSize: 1})
+	if err != nil {
+		t.Fatalf("failed to create renderer: %v", err)
+	}
+	defer r.Close()
+
+	if err := r.Ping(context.Background()); err == nil {
+		t.Error("expected error pinging without a bundle")
+	}
+
+	if err := r.LoadBundle(`global.render = function(page) { return '<div></div>'; };`); err != nil {
+		t.Fatalf("failed to load bundle: %v", err)
+	}
+	if err := r.Ping(context.Background()); err != nil {
+		t.Errorf("expected healthy renderer, got %v", err)
+	}
+
+	r.Close()
+	if err := r.Ping(context.Background()); err == nil {
+		t.Error("expected error pinging a closed renderer")
+	}
+}
+
+// flushRecorder records the data written before each Flush.
+type flushRecorder struct {
+	strings.Builder
+	flushed []string
+}
+
+func (f *flushRecorder) Flush() {
+	f.flushed = append(f.flushed, f.String())
+}
+
+func TestRenderStream(t *testing.T) {
+	t.Run("writes and flushes each chunk", func(t *testing.T) {
+		r, _ := NewRenderer(&Config{PoolSize: 1, Timeout: time.Second})
+		defer r.Close()
+		r.LoadBundle(`
+			global.render = function(page) { return '<main>' + page.component + '</main>'; };
+			global.renderStream = function(page) { return ['<main>', page.component, '</main>']; };
+		`)
+
+		var w flushRecorder
+		if err := r.RenderStream(context.Background(), map[string]interface{}{"component": "Home"}, &w); err != nil {
+			t.Fatalf("stream failed: %v", err)
+		}
+
+		want := []string{"<main>", "<main>Home", "<main>Home</main>"}
+		if len(w.flushed) != len(want) {
+			t.Fatalf("expected %d flushes, got %v", len(want), w.flushed)
+		}
+		for idx := range want {
+			if w.flushed[idx] != want[idx] {
+				t.Errorf("flush %d: expected %q, got %q", idx, want[idx], w.flushed[idx])
+			}
+		}
+	})
+
+	t.Run("falls back to the render body", func(t *testing.T) {
+		r, _ := NewRenderer(&Config{PoolSize: 1, Timeout: time.Second})
+		defer r.Close()
+		r.LoadBundle(`global.render = function(page) { return { head: ['<title>x</title>'], body: '<div>Body</div>' }; };`)
+
+		var w flushRecorder
+		if err := r.RenderStream(context.Background(), map[string]interface{}{}, &w); err != nil {
+			t.Fatalf("stream failed: %v", err)
+		}
+		if w.String() != "<div>Body</div>" {
+			t.Errorf("expected the body only, got %q", w.String())
+		}
+	})
+
+	t.Run("writes nothing when rendering fails", func(t *testing.T) {
+		r, _ := NewRenderer(&Config{PoolSize: 1, Timeout: time.Second})
+		defer r.Close()
+		r.LoadBundle(`global.render = function(page) { throw new Error('boom'); };`)
+
+		var w flushRecorder
+		if err := r.RenderStream(context.Background(), map[string]interface{}{}, &w); err == nil {
+			t.Fatal("expected error")
+		}
+		if w.Len() != 0 {
+			t.Errorf("expected no output, got %q", w.String())
+		}
+	})
+}
+
 func TestExtractHead(t *testing.T) {
 	r, _ := NewRenderer()
 	defer r.Close()
@@ -261,3 +472,166 @@ func stringContains(s, substr string) bool {
 	}
 	return false
 }
+
+func TestNamedBundles(t *testing.T) {
+	r, err := NewRenderer(&Config{PoolSize: 2})
+	if err != nil {
+		t.Fatalf("failed to create renderer: %v", err)
+	}
+	defer r.Close()
+
+	if err := r.LoadBundleNamed("admin", `global.render = function(page) { return '<div>admin:' + page.component + '</div>'; };`); err != nil {
+		t.Fatalf("failed to load admin bundle: %v", err)
+	}
+	if err := r.LoadBundleNamed("public", `global.render = function(page) { return '<div>public:' + page.component + '</div>'; };`); err != nil {
+		t.Fatalf("failed to load public bundle: %v", err)
+	}
+
+	t.Run("renders each component through its bundle", func(t *testing.T) {
+		html, err := r.RenderToStringNamed(context.Background(), "admin", map[string]interface{}{"component": "Admin/Users"})
+		if err != nil {
+			t.Fatalf("admin render failed: %v", err)
+		}
+		if html != "<div>admin:Admin/Users</div>" {
+			t.Errorf("unexpected admin HTML: %s", html)
+		}
+
+		html, err = r.RenderToStringNamed(context.Background(), "public", map[string]interface{}{"component": "Home"})
+		if err != nil {
+			t.Fatalf("public render failed: %v", err)
+		}
+		if html != "<div>public:Home</div>" {
+			t.Errorf("unexpected public HTML: %s", html)
+		}
+	})
+
+	t.Run("lists bundles", func(t *testing.T) {
+		names := r.Bundles()
+		if len(names) != 2 || names[0] != "admin" || names[1] != "public" {
+			t.Errorf("expected [admin public], got %v", names)
+		}
+	})
+
+	t.Run("unknown bundle errors", func(t *testing.T) {
+		_, err := r.RenderToStringNamed(context.Background(), "missing", map[string]interface{}{"component": "Home"})
+		if err == nil {
+			t.Error("expected error for unknown bundle, got nil")
+		}
+	})
+}
+
+func TestIdleContextReaping(t *testing.T) {
+	r, err := NewRenderer(&Config{
+		PoolSize:           4,
+		IdleContextTimeout: 50 * time.Millisecond,
+		MinIdleContexts:    1,
+	})
+	if err != nil {
+		t.Fatalf("failed to create renderer: %v", err)
+	}
+	defer r.Close()
+
+	if err := r.LoadBundle(`global.render = function(page) { return '<div>' + page.component + '</div>'; };`); err != nil {
+		t.Fatalf("failed to load bundle: %v", err)
+	}
+
+	pool := r.bundles[DefaultBundle].pool
+	if len(pool) != 4 {
+		t.Fatalf("expected 4 pooled contexts, got %d", len(pool))
+	}
+
+	t.Run("idle contexts shrink toward the minimum", func(t *testing.T) {
+		deadline := time.Now().Add(2 * time.Second)
+		for len(pool) > 1 && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		if len(pool) != 1 {
+			t.Fatalf("expected pool to shrink to 1 context, got %d", len(pool))
+		}
+	})
+
+	t.Run("renders recreate contexts on demand", func(t *testing.T) {
+		// Hold the remaining pooled context so the render must create a new one
+		held := <-pool
+
+		html, err := r.RenderToString(context.Background(), map[string]interface{}{"component": "Home"})
+		if err != nil {
+			t.Fatalf("render failed: %v", err)
+		}
+		if html != "<div>Home</div>" {
+			t.Errorf("unexpected HTML: %s", html)
+		}
+
+		if len(pool) != 1 {
+			t.Errorf("expected recreated context to be pooled, got %d contexts", len(pool))
+		}
+		pool <- held
+	})
+}
+
+func TestRenderError(t *testing.T) {
+	r, err := NewRenderer(&Config{PoolSize: 1})
+	if err != nil {
+		t.Fatalf("failed to create renderer: %v", err)
+	}
+	defer r.Close()
+
+	bundle := `global.render = function(page) { throw new Error('cannot render ' + page.component); };`
+	if err := r.LoadBundle(bundle); err != nil {
+		t.Fatalf("failed to load bundle: %v", err)
+	}
+
+	render := func() *RenderError {
+		t.Helper()
+		_, err := r.RenderToString(context.Background(), map[string]interface{}{"component": "Broken"})
+		var renderErr *RenderError
+		if !errors.As(err, &renderErr) {
+			t.Fatalf("expected *RenderError, got %v", err)
+		}
+		return renderErr
+	}
+
+	t.Run("includes message and stack", func(t *testing.T) {
+		renderErr := render()
+		if !contains(renderErr.Message, "cannot render Broken") {
+			t.Errorf("unexpected message: %q", renderErr.Message)
+		}
+		if !contains(renderErr.Stack, "bundle.js:1:") {
+			t.Errorf("expected stack to reference bundle.js, got %q", renderErr.Stack)
+		}
+		if !contains(renderErr.Error(), renderErr.Stack) {
+			t.Errorf("expected error string to include the stack, got %q", renderErr.Error())
+		}
+	})
+
+	t.Run("unwraps to the V8 error", func(t *testing.T) {
+		var jsErr *v8go.JSError
+		if !errors.As(render(), &jsErr) {
+			t.Fatal("expected RenderError to unwrap to *v8go.JSError")
+		}
+		if !contains(jsErr.Message, "cannot render Broken") {
+			t.Errorf("unexpected JS error message: %q", jsErr.Message)
+		}
+	})
+
+	t.Run("maps stack through source map", func(t *testing.T) {
+		sourceMap := `{"version":3,"sources":["src/Pages/Broken.vue"],"names":[],"mappings":"AAUA"}`
+		if err := r.LoadSourceMap(DefaultBundle, []byte(sourceMap)); err != nil {
+			t.Fatalf("failed to load source map: %v", err)
+		}
+
+		renderErr := render()
+		if !contains(renderErr.Stack, "src/Pages/Broken.vue:11:1") {
+			t.Errorf("expected mapped position in stack, got %q", renderErr.Stack)
+		}
+	})
+
+	t.Run("rejects invalid source maps", func(t *testing.T) {
+		if err := r.LoadSourceMap(DefaultBundle, []byte(`{"version":2}`)); err == nil {
+			t.Error("expected error for unsupported version")
+		}
+		if err := r.LoadSourceMap("missing", []byte(`{"version":3,"mappings":""}`)); err == nil {
+			t.Error("expected error for unknown bundle")
+		}
+	})
+}
@@ -0,0 +1,145 @@
+package ssr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// sourceMap is a decoded version 3 source map.
+type sourceMap struct {
+	sources []string
+	lines   [][]mapping // segments per generated line, ordered by column
+}
+
+// mapping maps a generated column to a position in an original source.
+type mapping struct {
+	genCol  int
+	source  int
+	srcLine int
+	srcCol  int
+}
+
+// parseSourceMap decodes a version 3 source map.
+func parseSourceMap(data []byte) (*sourceMap, error) {
+	var raw struct {
+		Version  int      `json:"version"`
+		Sources  []string `json:"sources"`
+		Mappings string   `json:"mappings"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid source map: %w", err)
+	}
+	if raw.Version != 3 {
+		return nil, fmt.Errorf("unsupported source map version %d", raw.Version)
+	}
+
+	sm := &sourceMap{sources: raw.Sources}
+	var source, srcLine, srcCol int
+
+	for _, line := range strings.Split(raw.Mappings, ";") {
+		var segments []mapping
+		genCol := 0
+
+		for _, segment := range strings.Split(line, ",") {
+			if segment == "" {
+				continue
+			}
+			fields, err := decodeVLQ(segment)
+			if err != nil {
+				return nil, err
+			}
+
+			genCol += fields[0]
+			if len(fields) < 4 {
+				continue
+			}
+			source += fields[1]
+			srcLine += fields[2]
+			srcCol += fields[3]
+
+			if source < 0 || source >= len(sm.sources) {
+				return nil, errors.New("invalid source map: source index out of range")
+			}
+			segments = append(segments, mapping{genCol: genCol, source: source, srcLine: srcLine, srcCol: srcCol})
+		}
+
+		sm.lines = append(sm.lines, segments)
+	}
+
+	return sm, nil
+}
+
+// lookup maps a 1-based generated line and column to an original position.
+func (sm *sourceMap) lookup(line, col int) (source string, srcLine, srcCol int, ok bool) {
+	if line < 1 || line > len(sm.lines) {
+		return "", 0, 0, false
+	}
+
+	var found *mapping
+	for i, m := range sm.lines[line-1] {
+		if m.genCol > col-1 {
+			break
+		}
+		found = &sm.lines[line-1][i]
+	}
+	if found == nil {
+		return "", 0, 0, false
+	}
+
+	return sm.sources[found.source], found.srcLine + 1, found.srcCol + 1, true
+}
+
+// bundlePosition matches positions in the bundle script within a V8 stack trace.
+var bundlePosition = regexp.MustCompile(`bundle\.js:(\d+):(\d+)`)
+
+// mapStack rewrites bundle positions in stack to their original sources.
+func (sm *sourceMap) mapStack(stack string) string {
+	return bundlePosition.ReplaceAllStringFunc(stack, func(pos string) string {
+		parts := bundlePosition.FindStringSubmatch(pos)
+		line, _ := strconv.Atoi(parts[1])
+		col, _ := strconv.Atoi(parts[2])
+
+		source, srcLine, srcCol, ok := sm.lookup(line, col)
+		if !ok {
+			return pos
+		}
+		return fmt.Sprintf("%s:%d:%d", source, srcLine, srcCol)
+	})
+}
+
+const base64Chars = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+
+// decodeVLQ decodes a base64 VLQ source map segment into its signed fields.
+func decodeVLQ(segment string) ([]int, error) {
+	var fields []int
+	value, shift := 0, 0
+
+	for i := 0; i < len(segment); i++ {
+		digit := strings.IndexByte(base64Chars, segment[i])
+		if digit < 0 {
+			return nil, fmt.Errorf("invalid source map: bad mapping character %q", segment[i])
+		}
+
+		value += (digit & 31) << shift
+		if digit&32 != 0 {
+			shift += 5
+			continue
+		}
+
+		if value&1 != 0 {
+			fields = append(fields, -(value >> 1))
+		} else {
+			fields = append(fields, value>>1)
+		}
+		value, shift = 0, 0
+	}
+
+	if shift != 0 {
+		return nil, errors.New("invalid source map: truncated mapping")
+	}
+	return fields, nil
+}
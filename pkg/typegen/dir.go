@@ -0,0 +1,202 @@
+package typegen
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// fileHeader is written at the top of every generated file.
+const fileHeader = "// Auto-generated TypeScript types from Go structs\n// Do not edit manually\n\n"
+
+// GenerateDir generates one TypeScript file per type (e.g. User.ts, Post.ts) in dir,
+// plus an index.ts barrel that re-exports all of them.
+//
+// Struct types referenced by registered types are emitted as well, and each file
+// imports the types it depends on. Unions registered with RegisterUnion get their
+// own file next to their variants.
+func (g *Generator) GenerateDir(dir string) error {
+	types, err := g.collectTypes()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	var index strings.Builder
+	index.WriteString(fileHeader)
+
+	for _, t := range types {
+		iface, err := g.generateInterface(t)
+		if err != nil {
+			return fmt.Errorf("failed to generate interface for %s: %w", t.Name(), err)
+		}
+
+		var sb strings.Builder
+		sb.WriteString(fileHeader)
+		deps := g.dependencies(t)
+		imports := make([]string, len(deps))
+		for idx, dep := range deps {
+			imports[idx] = g.declName(dep)
+		}
+		if err := writeTypeFile(dir, t.Name(), imports, iface); err != nil {
+			return err
+		}
+		index.WriteString(fmt.Sprintf("export * from './%s';\n", t.Name()))
+	}
+
+	for _, name := range g.unionNames() {
+		u := g.unions[name]
+		imports := make([]string, len(u.variants))
+		for idx, variant := range u.variants {
+			imports[idx] = variant.Name()
+		}
+		if err := writeTypeFile(dir, name, imports, u.declaration()); err != nil {
+			return err
+		}
+		index.WriteString(fmt.Sprintf("export * from './%s';\n", name))
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "index.ts"), []byte(index.String()), 0600); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return nil
+}
+
+// writeTypeFile writes the declaration of name to its own file in dir,
+// importing the named types from their sibling files.
+func writeTypeFile(dir, name string, imports []string, declaration string) error {
+	var sb strings.Builder
+	sb.WriteString(fileHeader)
+	for _, dep := range imports {
+		sb.WriteString(fmt.Sprintf("import type { %s } from './%s';\n", dep, dep))
+	}
+	if len(imports) > 0 {
+		sb.WriteString("\n")
+	}
+	sb.WriteString(declaration)
+	sb.WriteString("\n")
+
+	if err := os.WriteFile(filepath.Join(dir, name+".ts"), []byte(sb.String()), 0600); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
+
+// collectTypes returns the registered struct types, the variants of registered
+// unions and all struct types they reference, sorted by name.
+func (g *Generator) collectTypes() ([]reflect.Type, error) {
+	seen := make(map[reflect.Type]bool)
+	var queue []reflect.Type
+
+	for name, v := range g.types {
+		t := reflect.TypeOf(v)
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("failed to generate interface for %s: expected struct, got %s", name, t.Kind())
+		}
+		if !seen[t] {
+			seen[t] = true
+			queue = append(queue, t)
+		}
+	}
+
+	for _, name := range g.unionNames() {
+		for _, variant := range g.unions[name].variants {
+			if !seen[variant] {
+				seen[variant] = true
+				queue = append(queue, variant)
+			}
+		}
+	}
+
+	var types []reflect.Type
+	for len(queue) > 0 {
+		t := queue[0]
+		queue = queue[1:]
+		types = append(types, t)
+
+		for _, dep := range g.dependencies(t) {
+			// Union variants are queued above
+			if dep.Kind() == reflect.Interface {
+				continue
+			}
+			if !seen[dep] {
+				seen[dep] = true
+				queue = append(queue, dep)
+			}
+		}
+	}
+
+	sort.Slice(types, func(i, j int) bool { return types[i].Name() < types[j].Name() })
+	return types, nil
+}
+
+// dependencies returns the named struct types and union interfaces referenced by
+// the fields of t, including fields of anonymous structs, excluding t itself and
+// types with a TypeScript mapping, sorted by declared name.
+func (g *Generator) dependencies(t reflect.Type) []reflect.Type {
+	visited := map[reflect.Type]bool{t: true}
+	var deps []reflect.Type
+	g.collectDependencies(t, visited, &deps)
+
+	sort.Slice(deps, func(i, j int) bool { return g.declName(deps[i]) < g.declName(deps[j]) })
+	return deps
+}
+
+// collectDependencies appends the struct types referenced by the fields of t to deps.
+// visited guards against revisiting types, so recursive structs terminate.
+func (g *Generator) collectDependencies(t reflect.Type, visited map[reflect.Type]bool, deps *[]reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || field.Tag.Get("json") == "-" {
+			continue
+		}
+
+		dep := g.referencedStruct(field.Type)
+		if dep == nil || visited[dep] {
+			continue
+		}
+		visited[dep] = true
+
+		if dep.Kind() == reflect.Struct && dep.Name() == "" {
+			// Anonymous structs are generated inline, so walk their fields
+			g.collectDependencies(dep, visited, deps)
+			continue
+		}
+		*deps = append(*deps, dep)
+	}
+}
+
+// referencedStruct unwraps pointers, slices, arrays and maps and returns the
+// struct type or registered union interface at the core of ft, or nil if there
+// is none.
+func (g *Generator) referencedStruct(ft reflect.Type) reflect.Type {
+	for {
+		if _, ok := g.typeMappings[typeKey(ft)]; ok {
+			return nil
+		}
+
+		switch ft.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+			ft = ft.Elem()
+		case reflect.Struct:
+			return ft
+		case reflect.Interface:
+			if _, ok := g.unionFor(ft); ok {
+				return ft
+			}
+			return nil
+		default:
+			return nil
+		}
+	}
+}
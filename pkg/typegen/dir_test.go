@@ -0,0 +1,105 @@
+package typegen
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateDir(t *testing.T) {
+	gen := New()
+	gen.Register("PageProps", PageProps{})
+	gen.Register("User", User{})
+
+	dir := filepath.Join(t.TempDir(), "types")
+	if err := gen.GenerateDir(dir); err != nil {
+		t.Fatalf("GenerateDir() error = %v", err)
+	}
+
+	read := func(name string) string {
+		t.Helper()
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", name, err)
+		}
+		return string(content)
+	}
+
+	t.Run("writes one file per type including dependencies", func(t *testing.T) {
+		for _, name := range []string{"PageProps.ts", "Post.ts", "User.ts", "index.ts"} {
+			if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+				t.Errorf("expected %s to exist: %v", name, err)
+			}
+		}
+	})
+
+	t.Run("imports referenced types", func(t *testing.T) {
+		pageProps := read("PageProps.ts")
+		if !strings.Contains(pageProps, "import type { Post } from './Post';\nimport type { User } from './User';") {
+			t.Errorf("PageProps.ts missing imports:\n%s", pageProps)
+		}
+		if !strings.Contains(pageProps, "export interface PageProps {") {
+			t.Errorf("PageProps.ts missing interface:\n%s", pageProps)
+		}
+
+		post := read("Post.ts")
+		if !strings.Contains(post, "import type { User } from './User';") {
+			t.Errorf("Post.ts missing User import:\n%s", post)
+		}
+
+		user := read("User.ts")
+		if strings.Contains(user, "import") {
+			t.Errorf("User.ts should not import anything:\n%s", user)
+		}
+	})
+
+	t.Run("barrel re-exports every type", func(t *testing.T) {
+		index := read("index.ts")
+		for _, name := range []string{"PageProps", "Post", "User"} {
+			if !strings.Contains(index, "export * from './"+name+"';") {
+				t.Errorf("index.ts missing export for %s:\n%s", name, index)
+			}
+		}
+	})
+}
+
+func TestGenerateDir_Unions(t *testing.T) {
+	gen := New()
+	gen.Register("ActivityFeed", ActivityFeed{})
+	gen.RegisterUnion("Event", (*Event)(nil), UserCreated{Type: "user.created"}, UserDeleted{Type: "user.deleted"})
+
+	dir := t.TempDir()
+	if err := gen.GenerateDir(dir); err != nil {
+		t.Fatalf("GenerateDir() error = %v", err)
+	}
+
+	read := func(name string) string {
+		t.Helper()
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", name, err)
+		}
+		return string(content)
+	}
+
+	event := read("Event.ts")
+	for _, want := range []string{
+		"import type { UserCreated } from './UserCreated';\nimport type { UserDeleted } from './UserDeleted';",
+		"export type Event = UserCreated | UserDeleted;",
+	} {
+		if !strings.Contains(event, want) {
+			t.Errorf("Event.ts missing %q:\n%s", want, event)
+		}
+	}
+
+	if feed := read("ActivityFeed.ts"); !strings.Contains(feed, "import type { Event } from './Event';") {
+		t.Errorf("ActivityFeed.ts missing Event import:\n%s", feed)
+	}
+	if created := read("UserCreated.ts"); !strings.Contains(created, `type: "user.created";`) {
+		t.Errorf("UserCreated.ts missing discriminator:\n%s", created)
+	}
+	if index := read("index.ts"); !strings.Contains(index, "export * from './Event';") {
+		t.Errorf("index.ts missing Event export:\n%s", index)
+	}
+}
@@ -0,0 +1,61 @@
+package typegen
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/toutaio/toutago-inertia/pkg/inertia"
+)
+
+// pageTypeName is the name of the generated Inertia page type.
+const pageTypeName = "InertiaPage<P = Record<string, unknown>>"
+
+// GeneratePageType generates the TypeScript type of the Inertia page object
+// (inertia.Page) using the default options, generic over its props:
+//
+//	export interface InertiaPage<P = Record<string, unknown>> {
+//	  component: string;
+//	  props: P;
+//	  ...
+//	}
+func GeneratePageType() string {
+	return New().GeneratePageType()
+}
+
+// GeneratePageType generates the TypeScript type of the Inertia page object,
+// see the GeneratePageType function.
+func (g *Generator) GeneratePageType() string {
+	t := reflect.TypeOf(inertia.Page{})
+
+	var sb strings.Builder
+	if g.typeAlias {
+		sb.WriteString("export type " + pageTypeName + " = {\n")
+	} else {
+		sb.WriteString("export interface " + pageTypeName + " {\n")
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		info, ok := jsonFieldInfo(t.Field(i))
+		if !ok {
+			continue
+		}
+
+		var tsType string
+		switch info.name {
+		case "props":
+			tsType = "P"
+		case "resetScroll":
+			tsType = "boolean | string[]"
+		default:
+			tsType = g.tsType(t.Field(i).Type, nil)
+		}
+		sb.WriteString("  " + g.signature(info, tsType) + ";\n")
+	}
+
+	if g.typeAlias {
+		sb.WriteString("};")
+	} else {
+		sb.WriteString("}")
+	}
+	return sb.String()
+}
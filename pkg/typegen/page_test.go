@@ -0,0 +1,44 @@
+package typegen
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/toutaio/toutago-inertia/pkg/inertia"
+)
+
+func TestGeneratePageType(t *testing.T) {
+	got := GeneratePageType()
+
+	expected := `export interface InertiaPage<P = Record<string, unknown>> {
+  component: string;
+  props: P;
+  url: string;
+  version: string;
+  resetScroll?: boolean | string[];
+}`
+	if got != expected {
+		t.Errorf("GeneratePageType() =\n%v\n\nwant:\n%v", got, expected)
+	}
+
+	// Every serialized Page field must be part of the generated type
+	pageType := reflect.TypeOf(inertia.Page{})
+	for i := 0; i < pageType.NumField(); i++ {
+		info, ok := jsonFieldInfo(pageType.Field(i))
+		if ok && !strings.Contains(got, "  "+info.name) {
+			t.Errorf("expected field %s in:\n%s", info.name, got)
+		}
+	}
+}
+
+func TestGeneratePageType_Options(t *testing.T) {
+	got := New(WithTypeAlias(true), WithReadonly(true)).GeneratePageType()
+
+	if !strings.HasPrefix(got, "export type InertiaPage<P = Record<string, unknown>> = {\n") {
+		t.Errorf("expected a type alias, got:\n%s", got)
+	}
+	if !strings.Contains(got, "  readonly props: P;\n") || !strings.HasSuffix(got, "};") {
+		t.Errorf("expected readonly fields, got:\n%s", got)
+	}
+}
@@ -0,0 +1,263 @@
+package typegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// jsonSchemaDialect is the JSON Schema version of generated documents.
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// schema is a JSON Schema object.
+type schema map[string]interface{}
+
+// GenerateJSONSchema generates a JSON Schema document for a Go struct. Named structs
+// it references are emitted under "$defs" and referenced with "$ref", so recursive
+// types terminate. Type mappings, json tags and optional fields follow the same
+// rules as the TypeScript output; pointer fields are nullable.
+func (g *Generator) GenerateJSONSchema(name string, v interface{}) (string, error) {
+	t := reflect.TypeOf(v)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return "", fmt.Errorf("expected struct, got %s", t.Kind())
+	}
+
+	b := newSchemaBuilder(g)
+	b.names[t] = name
+	b.refs[t] = "#"
+
+	doc := b.objectSchema(t, nil)
+	doc["$schema"] = jsonSchemaDialect
+	doc["title"] = name
+	if defs := b.build(); len(defs) > 0 {
+		doc["$defs"] = defs
+	}
+
+	return marshalSchema(doc)
+}
+
+// GenerateJSONSchemaFile writes a JSON Schema document with all registered types,
+// and the struct types they reference, under "$defs".
+func (g *Generator) GenerateJSONSchemaFile(path string) error {
+	b := newSchemaBuilder(g)
+
+	names := make([]string, 0, len(g.types))
+	for name := range g.types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		t := reflect.TypeOf(g.types[name])
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return fmt.Errorf("failed to generate schema for %s: expected struct, got %s", name, t.Kind())
+		}
+		b.names[t] = name
+		b.ref(t)
+	}
+
+	content, err := marshalSchema(schema{
+		"$schema": jsonSchemaDialect,
+		"$defs":   b.build(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return nil
+}
+
+// schemaBuilder collects the definitions of named structs referenced by a schema.
+type schemaBuilder struct {
+	g       *Generator
+	names   map[reflect.Type]string // definition names, defaulting to the type name
+	refs    map[reflect.Type]string // $ref of every type seen so far
+	pending []reflect.Type          // referenced types whose definitions are not built yet
+}
+
+func newSchemaBuilder(g *Generator) *schemaBuilder {
+	return &schemaBuilder{
+		g:     g,
+		names: make(map[reflect.Type]string),
+		refs:  make(map[reflect.Type]string),
+	}
+}
+
+// ref returns a reference to the definition of the named struct t, queueing the
+// definition to be built.
+func (b *schemaBuilder) ref(t reflect.Type) schema {
+	if ref, ok := b.refs[t]; ok {
+		return schema{"$ref": ref}
+	}
+
+	name, ok := b.names[t]
+	if !ok {
+		name = t.Name()
+		b.names[t] = name
+	}
+	ref := "#/$defs/" + name
+	b.refs[t] = ref
+	b.pending = append(b.pending, t)
+
+	return schema{"$ref": ref}
+}
+
+// build builds the definitions of all referenced types.
+func (b *schemaBuilder) build() schema {
+	defs := schema{}
+	for len(b.pending) > 0 {
+		t := b.pending[0]
+		b.pending = b.pending[1:]
+		defs[b.names[t]] = b.objectSchema(t, nil)
+	}
+	return defs
+}
+
+// objectSchema returns the schema of a struct's serialized fields. visiting tracks
+// anonymous structs being generated to break cycles.
+func (b *schemaBuilder) objectSchema(t reflect.Type, visiting map[reflect.Type]bool) schema {
+	if visiting[t] {
+		return schema{}
+	}
+	if visiting == nil {
+		visiting = make(map[reflect.Type]bool)
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	properties := schema{}
+	required := []string{}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		info, ok := jsonFieldInfo(field)
+		if !ok {
+			continue
+		}
+
+		prop := b.typeSchema(field.Type, visiting)
+		if info.asString {
+			prop = schema{"type": "string"}
+			if field.Type.Kind() == reflect.Ptr {
+				prop = nullable(prop)
+			}
+		}
+		properties[info.name] = prop
+
+		if !info.optional {
+			required = append(required, info.name)
+		}
+	}
+
+	return schema{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+}
+
+// typeSchema converts a Go type to JSON Schema.
+func (b *schemaBuilder) typeSchema(t reflect.Type, visiting map[reflect.Type]bool) schema {
+	// Pointers serialize as null when nil
+	if t.Kind() == reflect.Ptr {
+		return nullable(b.typeSchema(t.Elem(), visiting))
+	}
+
+	// Handle well-known named types
+	if tsType, ok := b.g.typeMappings[typeKey(t)]; ok {
+		s := tsTypeSchema(tsType)
+		if typeKey(t) == "time.Time" && tsType == tsTypeString {
+			s["format"] = "date-time"
+		}
+		return s
+	}
+
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// Byte slices are encoded as base64 strings
+			return schema{"type": "string", "contentEncoding": "base64"}
+		}
+		return schema{"type": "array", "items": b.typeSchema(t.Elem(), visiting)}
+	case reflect.Map:
+		return schema{"type": "object", "additionalProperties": b.typeSchema(t.Elem(), visiting)}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.objectSchema(t, visiting)
+		}
+		return b.ref(t)
+	case reflect.String:
+		return schema{"type": "string"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return schema{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return schema{"type": "number"}
+	case reflect.Bool:
+		return schema{"type": "boolean"}
+	default:
+		return schema{}
+	}
+}
+
+// tsTypeSchema converts a TypeScript type from a type mapping to JSON Schema.
+// Unions of primitive types are supported; anything else accepts any value.
+func tsTypeSchema(tsType string) schema {
+	var types []string
+	for _, part := range strings.Split(tsType, "|") {
+		switch part = strings.TrimSpace(part); part {
+		case "string", "number", "boolean", "null":
+			types = append(types, part)
+		default:
+			return schema{}
+		}
+	}
+
+	if len(types) == 1 {
+		return schema{"type": types[0]}
+	}
+	return schema{"type": types}
+}
+
+// nullable allows null in addition to the values accepted by s.
+func nullable(s schema) schema {
+	switch typ := s["type"].(type) {
+	case string:
+		s["type"] = []string{typ, "null"}
+		return s
+	case []string:
+		s["type"] = append(typ, "null")
+		return s
+	}
+
+	if len(s) == 0 {
+		return s
+	}
+	return schema{"anyOf": []interface{}{s, schema{"type": "null"}}}
+}
+
+// marshalSchema encodes a schema document as indented JSON.
+func marshalSchema(doc schema) (string, error) {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode schema: %w", err)
+	}
+	return string(data) + "\n", nil
+}
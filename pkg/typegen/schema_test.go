@@ -0,0 +1,165 @@
+package typegen
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// decodeSchema generates a schema for v and decodes it for inspection.
+func decodeSchema(t *testing.T, name string, v interface{}) map[string]interface{} {
+	t.Helper()
+
+	out, err := New().GenerateJSONSchema(name, v)
+	if err != nil {
+		t.Fatalf("GenerateJSONSchema() error = %v", err)
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("invalid JSON schema: %v\n%s", err, out)
+	}
+	return doc
+}
+
+// lookup walks a decoded schema along the given keys.
+func lookup(doc map[string]interface{}, keys ...string) interface{} {
+	var cur interface{} = doc
+	for _, key := range keys {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		cur = m[key]
+	}
+	return cur
+}
+
+func TestGenerateJSONSchema(t *testing.T) {
+	t.Run("required fields and property types", func(t *testing.T) {
+		doc := decodeSchema(t, "User", User{})
+
+		tests := map[string]interface{}{
+			"$schema":  jsonSchemaDialect,
+			"title":    "User",
+			"type":     "object",
+			"required": []interface{}{"id", "name", "email", "active", "created_at"},
+		}
+		for key, want := range tests {
+			if got := doc[key]; !reflect.DeepEqual(got, want) {
+				t.Errorf("%s = %v, want %v", key, got, want)
+			}
+		}
+
+		props := map[string]interface{}{
+			"id":         map[string]interface{}{"type": "integer"},
+			"name":       map[string]interface{}{"type": "string"},
+			"active":     map[string]interface{}{"type": "boolean"},
+			"created_at": map[string]interface{}{"type": "string", "format": "date-time"},
+		}
+		for name, want := range props {
+			if got := lookup(doc, "properties", name); !reflect.DeepEqual(got, want) {
+				t.Errorf("properties.%s = %v, want %v", name, got, want)
+			}
+		}
+	})
+
+	t.Run("nested structs use refs", func(t *testing.T) {
+		doc := decodeSchema(t, "Post", Post{})
+
+		wantRequired := []interface{}{"id", "title", "content", "author_id"}
+		if got := doc["required"]; !reflect.DeepEqual(got, wantRequired) {
+			t.Errorf("required = %v, want %v", got, wantRequired)
+		}
+
+		wantAuthor := map[string]interface{}{
+			"anyOf": []interface{}{
+				map[string]interface{}{"$ref": "#/$defs/User"},
+				map[string]interface{}{"type": "null"},
+			},
+		}
+		if got := lookup(doc, "properties", "author"); !reflect.DeepEqual(got, wantAuthor) {
+			t.Errorf("properties.author = %v, want %v", got, wantAuthor)
+		}
+
+		if lookup(doc, "$defs", "User", "properties", "email") == nil {
+			t.Errorf("missing User definition: %v", doc["$defs"])
+		}
+	})
+
+	t.Run("array item types", func(t *testing.T) {
+		doc := decodeSchema(t, "PageProps", PageProps{})
+
+		wantPosts := map[string]interface{}{
+			"type":  "array",
+			"items": map[string]interface{}{"$ref": "#/$defs/Post"},
+		}
+		if got := lookup(doc, "properties", "posts"); !reflect.DeepEqual(got, wantPosts) {
+			t.Errorf("properties.posts = %v, want %v", got, wantPosts)
+		}
+		if got := lookup(doc, "properties", "user", "$ref"); got != "#/$defs/User" {
+			t.Errorf("properties.user.$ref = %v", got)
+		}
+		if lookup(doc, "$defs", "Post") == nil || lookup(doc, "$defs", "User") == nil {
+			t.Errorf("missing definitions: %v", doc["$defs"])
+		}
+	})
+
+	t.Run("recursive types reference the root", func(t *testing.T) {
+		doc := decodeSchema(t, "TreeNode", TreeNode{})
+
+		if got := lookup(doc, "properties", "children", "items", "$ref"); got != "#" {
+			t.Errorf("properties.children.items.$ref = %v, want #", got)
+		}
+		if got := lookup(doc, "properties", "meta", "properties", "owner", "anyOf"); got == nil {
+			t.Errorf("expected nullable owner in inline struct: %v", lookup(doc, "properties", "meta"))
+		}
+	})
+
+	t.Run("type mappings", func(t *testing.T) {
+		doc := decodeSchema(t, "WellKnown", WellKnown{})
+
+		want := map[string]interface{}{"type": []interface{}{"number", "string"}}
+		if got := lookup(doc, "properties", "amount"); !reflect.DeepEqual(got, want) {
+			t.Errorf("properties.amount = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("rejects non-structs", func(t *testing.T) {
+		if _, err := New().GenerateJSONSchema("Count", 1); err == nil {
+			t.Error("expected error for non-struct")
+		}
+	})
+}
+
+func TestGenerateJSONSchemaFile(t *testing.T) {
+	gen := New()
+	gen.Register("Post", Post{})
+	gen.Register("Tree", TreeNode{})
+
+	path := filepath.Join(t.TempDir(), "schemas", "types.schema.json")
+	if err := gen.GenerateJSONSchemaFile(path); err != nil {
+		t.Fatalf("GenerateJSONSchemaFile() error = %v", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read schema: %v", err)
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(content, &doc); err != nil {
+		t.Fatalf("invalid JSON schema: %v", err)
+	}
+
+	for _, name := range []string{"Post", "Tree", "User"} {
+		if lookup(doc, "$defs", name) == nil {
+			t.Errorf("missing definition %s", name)
+		}
+	}
+	if got := lookup(doc, "$defs", "Tree", "properties", "children", "items", "$ref"); got != "#/$defs/Tree" {
+		t.Errorf("registered name not used for references: %v", got)
+	}
+}
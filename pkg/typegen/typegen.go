@@ -1,12 +1,13 @@
 package typegen
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"reflect"
+	"sort"
 	"strings"
-	"time"
 )
 
 const (
@@ -16,14 +17,107 @@ const (
 
 // Generator manages TypeScript type generation.
 type Generator struct {
-	types map[string]interface{}
+	types         map[string]interface{}
+	typeMappings  map[string]string
+	readonly      bool
+	typeAlias     bool
+	nullability   nullability
+	unions        map[string]*union
+	unionTypes    map[reflect.Type]*union // unions by the Go interface they represent
+	variantTags   map[reflect.Type]string // discriminator values of union variants
+	discriminator string
+}
+
+// Option configures a Generator.
+type Option func(*Generator)
+
+// WithTypeMapping maps a Go type, identified by its import path and name
+// (e.g. "time.Duration" or "github.com/google/uuid.UUID"), to a TypeScript type.
+// Mappings override the built-in defaults.
+func WithTypeMapping(goType, tsType string) Option {
+	return func(g *Generator) {
+		g.typeMappings[goType] = tsType
+	}
+}
+
+// WithReadonly marks every generated field readonly, including fields of inline
+// structs, since page props should not be mutated on the client.
+func WithReadonly(enabled bool) Option {
+	return func(g *Generator) {
+		g.readonly = enabled
+	}
+}
+
+// WithTypeAlias generates `export type Name = { ... };` aliases instead of
+// interfaces, e.g. for composing generated types into unions or mapped types.
+func WithTypeAlias(enabled bool) Option {
+	return func(g *Generator) {
+		g.typeAlias = enabled
+	}
+}
+
+// nullability selects how pointer fields are generated.
+type nullability int
+
+const (
+	nullableNone     nullability = iota // pointers are optional: field?: T
+	nullableStrict                      // pointers may be null: field: T | null
+	nullableOptional                    // pointers may be absent or null: field?: T | null
+)
+
+// WithNullable generates pointer fields as `field: T | null`, since encoding/json
+// writes nil pointers as null, while omitempty fields stay `field?: T`. Pointer
+// fields with omitempty are omitted when nil, so they are `field?: T`.
+func WithNullable(enabled bool) Option {
+	return func(g *Generator) {
+		g.nullability = nullableNone
+		if enabled {
+			g.nullability = nullableStrict
+		}
+	}
+}
+
+// WithNullableOptional generates all pointer fields as `field?: T | null`, for
+// clients that treat absent and null values alike. Omitempty fields that are not
+// pointers stay `field?: T`.
+func WithNullableOptional(enabled bool) Option {
+	return func(g *Generator) {
+		g.nullability = nullableNone
+		if enabled {
+			g.nullability = nullableOptional
+		}
+	}
+}
+
+// defaultTypeMappings returns the built-in mappings for well-known types.
+func defaultTypeMappings() map[string]string {
+	return map[string]string{
+		"time.Time":                   tsTypeString,
+		"time.Duration":               "number",
+		"encoding/json.Number":        "number | string",
+		"encoding/json.RawMessage":    tsTypeAny,
+		"net.IP":                      tsTypeString,
+		"net/url.URL":                 tsTypeString,
+		"github.com/google/uuid.UUID": tsTypeString,
+	}
 }
 
 // New creates a new Generator instance.
-func New() *Generator {
-	return &Generator{
-		types: make(map[string]interface{}),
+func New(opts ...Option) *Generator {
+	g := &Generator{
+		types:         make(map[string]interface{}),
+		typeMappings:  defaultTypeMappings(),
+		unions:        make(map[string]*union),
+		unionTypes:    make(map[reflect.Type]*union),
+		variantTags:   make(map[reflect.Type]string),
+		discriminator: defaultDiscriminator,
 	}
+
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	return g
 }
 
 // Register adds a type to be generated.
@@ -33,7 +127,7 @@ func (g *Generator) Register(name string, v interface{}) {
 
 // GenerateFile generates a TypeScript file with all registered types.
 func (g *Generator) GenerateFile(path string) error {
-	content, err := GenerateTypeScriptFile(g.types)
+	content, err := g.generateFile(g.types)
 	if err != nil {
 		return err
 	}
@@ -52,94 +146,266 @@ func (g *Generator) GenerateFile(path string) error {
 	return nil
 }
 
-// GenerateTypeScriptInterface generates a TypeScript interface from a Go struct.
+// ErrOutdated is returned by Check and VerifyFile when a generated file does not
+// match what the generator would produce.
+var ErrOutdated = errors.New("generated types are out of date")
+
+// Check generates the TypeScript for all registered types in memory and compares
+// it with the file at path, returning an error wrapping ErrOutdated if they differ
+// or the file does not exist. CI pipelines use it to fail on stale types.
+func (g *Generator) Check(path string) error {
+	content, err := g.generateFile(g.types)
+	if err != nil {
+		return err
+	}
+	return VerifyFile(path, content)
+}
+
+// VerifyFile compares the file at path with the expected generated content.
+func VerifyFile(path, content string) error {
+	existing, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: %s does not exist", ErrOutdated, path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	if string(existing) != content {
+		return fmt.Errorf("%w: %s differs from the generated output", ErrOutdated, path)
+	}
+	return nil
+}
+
+// GenerateTypeScriptInterface generates a TypeScript interface from a Go struct
+// using the default options.
 func GenerateTypeScriptInterface(v interface{}) (string, error) {
+	return New().GenerateInterface(v)
+}
+
+// GenerateInterface generates a TypeScript interface from a Go struct, or a type
+// alias with WithTypeAlias.
+func (g *Generator) GenerateInterface(v interface{}) (string, error) {
 	t := reflect.TypeOf(v)
 	if t.Kind() == reflect.Ptr {
 		t = t.Elem()
 	}
 
+	return g.generateInterface(t)
+}
+
+// generateInterface generates a TypeScript interface for a struct type.
+func (g *Generator) generateInterface(t reflect.Type) (string, error) {
 	if t.Kind() != reflect.Struct {
 		return "", fmt.Errorf("expected struct, got %s", t.Kind())
 	}
 
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("export interface %s {\n", t.Name()))
+	if g.typeAlias {
+		sb.WriteString(fmt.Sprintf("export type %s = {\n", t.Name()))
+	} else {
+		sb.WriteString(fmt.Sprintf("export interface %s {\n", t.Name()))
+	}
 
 	for i := 0; i < t.NumField(); i++ {
-		field := t.Field(i)
-
-		// Skip unexported fields
-		if !field.IsExported() {
+		field, ok := g.fieldSignature(t.Field(i), nil)
+		if !ok {
 			continue
 		}
-
-		jsonTag := field.Tag.Get("json")
-		if jsonTag == "-" {
-			continue
+		if info, _ := jsonFieldInfo(t.Field(i)); info.name != "" {
+			if literal, ok := g.discriminatorLiteral(t, info.name); ok {
+				field = g.signature(info, literal)
+			}
 		}
+		sb.WriteString(fmt.Sprintf("  %s;\n", field))
+	}
 
-		fieldName, omitempty := parseJSONTag(jsonTag)
-		if fieldName == "" {
-			fieldName = toSnakeCase(field.Name)
-		}
+	if g.typeAlias {
+		sb.WriteString("};")
+	} else {
+		sb.WriteString("}")
+	}
+	return sb.String(), nil
+}
 
-		tsType := goTypeToTypeScript(field.Type)
+// fieldSignature returns the TypeScript property signature (e.g. "name?: string") for a
+// struct field, or false if the field is not serialized.
+func (g *Generator) fieldSignature(field reflect.StructField, visiting map[reflect.Type]bool) (string, bool) {
+	info, ok := jsonFieldInfo(field)
+	if !ok {
+		return "", false
+	}
 
-		optional := ""
-		if omitempty || field.Type.Kind() == reflect.Ptr {
-			optional = "?"
-		}
+	tsType := g.tsType(field.Type, visiting)
+	if info.asString {
+		tsType = tsTypeString
+	}
+
+	return g.signature(info, tsType), true
+}
 
-		sb.WriteString(fmt.Sprintf("  %s%s: %s;\n", fieldName, optional, tsType))
+// signature returns the TypeScript property signature of a field with tsType.
+func (g *Generator) signature(info fieldInfo, tsType string) string {
+	optional := ""
+	switch {
+	case g.nullability == nullableStrict && info.pointer && !info.omitempty:
+		tsType += " | null"
+	case g.nullability == nullableOptional && info.pointer:
+		optional = "?"
+		tsType += " | null"
+	case info.optional:
+		optional = "?"
 	}
 
-	sb.WriteString("}")
-	return sb.String(), nil
+	modifier := ""
+	if g.readonly {
+		modifier = "readonly "
+	}
+
+	return fmt.Sprintf("%s%s%s: %s", modifier, info.name, optional, tsType)
+}
+
+// fieldInfo describes how a struct field is serialized by encoding/json.
+type fieldInfo struct {
+	name      string
+	optional  bool // omitempty or pointer fields may be absent
+	omitempty bool
+	pointer   bool
+	asString  bool // the ",string" option encodes the scalar as a JSON string
 }
 
-// GenerateTypeScriptFile generates a complete TypeScript file with multiple interfaces.
+// jsonFieldInfo returns the serialization details of a struct field, or false if
+// the field is not serialized.
+func jsonFieldInfo(field reflect.StructField) (fieldInfo, bool) {
+	// Skip unexported fields
+	if !field.IsExported() {
+		return fieldInfo{}, false
+	}
+
+	jsonTag := field.Tag.Get("json")
+	if jsonTag == "-" {
+		return fieldInfo{}, false
+	}
+
+	name, omitempty, asString := parseJSONTag(jsonTag)
+	if name == "" {
+		name = toSnakeCase(field.Name)
+	}
+
+	return fieldInfo{
+		name:      name,
+		optional:  omitempty || field.Type.Kind() == reflect.Ptr,
+		omitempty: omitempty,
+		pointer:   field.Type.Kind() == reflect.Ptr,
+		asString:  asString && isStringEncodable(field.Type),
+	}, true
+}
+
+// generateInlineStruct generates an inline object type for an anonymous struct.
+// Types already being generated are emitted as any to break cycles.
+func (g *Generator) generateInlineStruct(t reflect.Type, visiting map[reflect.Type]bool) string {
+	if visiting[t] {
+		return tsTypeAny
+	}
+	if visiting == nil {
+		visiting = make(map[reflect.Type]bool)
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	var fields []string
+	for i := 0; i < t.NumField(); i++ {
+		if field, ok := g.fieldSignature(t.Field(i), visiting); ok {
+			fields = append(fields, field)
+		}
+	}
+
+	if len(fields) == 0 {
+		return "{}"
+	}
+	return "{ " + strings.Join(fields, "; ") + " }"
+}
+
+// GenerateTypeScriptFile generates a complete TypeScript file with multiple interfaces
+// using the default options.
 func GenerateTypeScriptFile(types map[string]interface{}) (string, error) {
+	return New().generateFile(types)
+}
+
+// generateFile generates a complete TypeScript file with multiple interfaces.
+func (g *Generator) generateFile(types map[string]interface{}) (string, error) {
 	var sb strings.Builder
 
-	sb.WriteString("// Auto-generated TypeScript types from Go structs\n")
-	sb.WriteString("// Do not edit manually\n\n")
+	sb.WriteString(fileHeader)
 
-	for name, v := range types {
-		iface, err := GenerateTypeScriptInterface(v)
+	// Emit interfaces in name order so output is stable across runs
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	emitted := make(map[reflect.Type]bool, len(names))
+	for _, name := range names {
+		iface, err := g.GenerateInterface(types[name])
 		if err != nil {
 			return "", fmt.Errorf("failed to generate interface for %s: %w", name, err)
 		}
 		sb.WriteString(iface)
 		sb.WriteString("\n\n")
+		if t := reflect.TypeOf(types[name]); t.Kind() == reflect.Ptr {
+			emitted[t.Elem()] = true
+		} else {
+			emitted[t] = true
+		}
+	}
+
+	unions, err := g.generateUnions(emitted)
+	if err != nil {
+		return "", err
+	}
+	for _, block := range unions {
+		sb.WriteString(block)
+		sb.WriteString("\n\n")
 	}
 
 	return strings.TrimSpace(sb.String()), nil
 }
 
-func goTypeToTypeScript(t reflect.Type) string {
+func (g *Generator) goTypeToTypeScript(t reflect.Type) string {
+	return g.tsType(t, nil)
+}
+
+// tsType converts a Go type to TypeScript. Named structs are emitted by reference,
+// so recursive types terminate; visiting tracks inline structs being generated.
+func (g *Generator) tsType(t reflect.Type, visiting map[reflect.Type]bool) string {
 	// Handle pointers
 	if t.Kind() == reflect.Ptr {
-		return goTypeToTypeScript(t.Elem())
+		return g.tsType(t.Elem(), visiting)
+	}
+
+	// Handle well-known named types
+	if tsType, ok := g.typeMappings[typeKey(t)]; ok {
+		return tsType
 	}
 
 	// Handle slices
 	if t.Kind() == reflect.Slice {
-		elemType := goTypeToTypeScript(t.Elem())
+		elemType := g.tsType(t.Elem(), visiting)
 		return elemType + "[]"
 	}
 
 	// Handle maps
 	if t.Kind() == reflect.Map {
-		keyType := goTypeToTypeScript(t.Key())
-		valueType := goTypeToTypeScript(t.Elem())
+		keyType := g.tsType(t.Key(), visiting)
+		valueType := g.tsType(t.Elem(), visiting)
 		return fmt.Sprintf("Record<%s, %s>", keyType, valueType)
 	}
 
 	// Handle structs
 	if t.Kind() == reflect.Struct {
-		if t == reflect.TypeOf(time.Time{}) {
-			return tsTypeString
+		if t.Name() == "" {
+			return g.generateInlineStruct(t, visiting)
 		}
 		return t.Name()
 	}
@@ -155,27 +421,58 @@ func goTypeToTypeScript(t reflect.Type) string {
 	case reflect.Bool:
 		return "boolean"
 	case reflect.Interface:
+		if u, ok := g.unionFor(t); ok {
+			return u.name
+		}
 		return tsTypeAny
 	default:
 		return tsTypeAny
 	}
 }
 
-func parseJSONTag(tag string) (name string, omitempty bool) {
+// typeKey returns the import path qualified name of a named type.
+func typeKey(t reflect.Type) string {
+	if t.Name() == "" || t.PkgPath() == "" {
+		return ""
+	}
+	return t.PkgPath() + "." + t.Name()
+}
+
+func parseJSONTag(tag string) (name string, omitempty, asString bool) {
 	if tag == "" {
-		return "", false
+		return "", false, false
 	}
 
 	parts := strings.Split(tag, ",")
 	name = parts[0]
 
 	for _, part := range parts[1:] {
-		if part == "omitempty" {
+		switch part {
+		case "omitempty":
 			omitempty = true
+		case "string":
+			asString = true
 		}
 	}
 
-	return name, omitempty
+	return name, omitempty, asString
+}
+
+// isStringEncodable reports whether the json ",string" option applies to t.
+func isStringEncodable(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
 }
 
 func toSnakeCase(s string) string {
@@ -1,7 +1,13 @@
 package typegen
 
 import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -81,6 +87,88 @@ func TestGenerateTypeScriptInterface(t *testing.T) {
 	}
 }
 
+func TestGenerateTypeScriptInterface_StringOption(t *testing.T) {
+	type Invoice struct {
+		ID       int64    `json:"id,string"`
+		Amount   float64  `json:"amount,string"`
+		Paid     bool     `json:"paid,string"`
+		Discount *float64 `json:"discount,omitempty,string"`
+		Items    []int64  `json:"items,string"`
+		Count    int      `json:"count"`
+	}
+
+	expected := `export interface Invoice {
+  id: string;
+  amount: string;
+  paid: string;
+  discount?: string;
+  items: number[];
+  count: number;
+}`
+
+	result, err := GenerateTypeScriptInterface(Invoice{})
+	if err != nil {
+		t.Fatalf("GenerateTypeScriptInterface() error = %v", err)
+	}
+	if result != expected {
+		t.Errorf("GenerateTypeScriptInterface() =\n%v\n\nwant:\n%v", result, expected)
+	}
+}
+
+// Money is a named type used to test custom type mappings.
+type Money int64
+
+type WellKnown struct {
+	Timeout  time.Duration `json:"timeout"`
+	Amount   json.Number   `json:"amount"`
+	Address  net.IP        `json:"address"`
+	Homepage *url.URL      `json:"homepage"`
+	Price    Money         `json:"price"`
+}
+
+func TestWellKnownTypes(t *testing.T) {
+	t.Run("default mappings", func(t *testing.T) {
+		expected := `export interface WellKnown {
+  timeout: number;
+  amount: number | string;
+  address: string;
+  homepage?: string;
+  price: number;
+}`
+
+		result, err := New().GenerateInterface(WellKnown{})
+		if err != nil {
+			t.Fatalf("GenerateInterface() error = %v", err)
+		}
+		if result != expected {
+			t.Errorf("GenerateInterface() =\n%v\n\nwant:\n%v", result, expected)
+		}
+	})
+
+	t.Run("overridden mappings", func(t *testing.T) {
+		gen := New(
+			WithTypeMapping("time.Duration", "string"),
+			WithTypeMapping("github.com/toutaio/toutago-inertia/pkg/typegen.Money", "string"),
+		)
+
+		expected := `export interface WellKnown {
+  timeout: string;
+  amount: number | string;
+  address: string;
+  homepage?: string;
+  price: string;
+}`
+
+		result, err := gen.GenerateInterface(WellKnown{})
+		if err != nil {
+			t.Fatalf("GenerateInterface() error = %v", err)
+		}
+		if result != expected {
+			t.Errorf("GenerateInterface() =\n%v\n\nwant:\n%v", result, expected)
+		}
+	})
+}
+
 func TestGenerateTypeScriptFile(t *testing.T) {
 	types := map[string]interface{}{
 		"User":      User{},
@@ -241,3 +329,273 @@ func TestToSnakeCase(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateTypeScriptFile_DeterministicOrder(t *testing.T) {
+	types := map[string]interface{}{
+		"User":      User{},
+		"WellKnown": WellKnown{},
+		"Post":      Post{},
+		"PageProps": PageProps{},
+	}
+
+	first, err := GenerateTypeScriptFile(types)
+	if err != nil {
+		t.Fatalf("GenerateTypeScriptFile() error = %v", err)
+	}
+
+	for i := 0; i < 20; i++ {
+		got, err := GenerateTypeScriptFile(types)
+		if err != nil {
+			t.Fatalf("GenerateTypeScriptFile() error = %v", err)
+		}
+		if got != first {
+			t.Fatalf("output changed between runs:\n%s\n---\n%s", first, got)
+		}
+	}
+
+	pageProps := strings.Index(first, "export interface PageProps")
+	post := strings.Index(first, "export interface Post")
+	user := strings.Index(first, "export interface User")
+	if pageProps >= post || post >= user {
+		t.Errorf("interfaces not sorted by name:\n%s", first)
+	}
+}
+
+type TreeNode struct {
+	Name     string     `json:"name"`
+	Children []TreeNode `json:"children"`
+	Parent   *TreeNode  `json:"parent"`
+	Meta     struct {
+		Owner *User     `json:"owner"`
+		Root  *TreeNode `json:"root"`
+	} `json:"meta"`
+}
+
+type Left struct {
+	Right *Right `json:"right"`
+}
+
+type Right struct {
+	Lefts []Left `json:"lefts"`
+}
+
+func TestRecursiveTypes(t *testing.T) {
+	t.Run("self-referential struct emits named references", func(t *testing.T) {
+		got, err := GenerateTypeScriptInterface(TreeNode{})
+		if err != nil {
+			t.Fatalf("GenerateTypeScriptInterface() error = %v", err)
+		}
+
+		for _, want := range []string{
+			"  children: TreeNode[];",
+			"  parent?: TreeNode;",
+			"  meta: { owner?: User; root?: TreeNode };",
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("missing %q in:\n%s", want, got)
+			}
+		}
+	})
+
+	t.Run("mutually recursive types terminate in GenerateDir", func(t *testing.T) {
+		gen := New()
+		gen.Register("Left", Left{})
+		gen.Register("TreeNode", TreeNode{})
+
+		dir := t.TempDir()
+		if err := gen.GenerateDir(dir); err != nil {
+			t.Fatalf("GenerateDir() error = %v", err)
+		}
+
+		tests := map[string]string{
+			"Left.ts":     "import type { Right } from './Right';",
+			"Right.ts":    "import type { Left } from './Left';",
+			"TreeNode.ts": "import type { User } from './User';",
+		}
+		for file, want := range tests {
+			content, err := os.ReadFile(filepath.Join(dir, file))
+			if err != nil {
+				t.Fatalf("failed to read %s: %v", file, err)
+			}
+			if !strings.Contains(string(content), want) {
+				t.Errorf("%s missing %q:\n%s", file, want, content)
+			}
+			if file == "TreeNode.ts" && strings.Contains(string(content), "from './TreeNode'") {
+				t.Errorf("TreeNode.ts should not import itself:\n%s", content)
+			}
+		}
+	})
+}
+
+func TestWithReadonly(t *testing.T) {
+	type Settings struct {
+		ID    int `json:"id"`
+		Theme struct {
+			Dark bool `json:"dark"`
+		} `json:"theme"`
+		Owner *User `json:"owner"`
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+		got, err := New(WithReadonly(true)).GenerateInterface(Settings{})
+		if err != nil {
+			t.Fatalf("GenerateInterface() error = %v", err)
+		}
+
+		expected := `export interface Settings {
+  readonly id: number;
+  readonly theme: { readonly dark: boolean };
+  readonly owner?: User;
+}`
+		if got != expected {
+			t.Errorf("GenerateInterface() =\n%v\n\nwant:\n%v", got, expected)
+		}
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		got, err := New().GenerateInterface(Settings{})
+		if err != nil {
+			t.Fatalf("GenerateInterface() error = %v", err)
+		}
+		if strings.Contains(got, "readonly") {
+			t.Errorf("unexpected readonly modifier:\n%s", got)
+		}
+	})
+}
+
+func TestWithTypeAlias(t *testing.T) {
+	type Settings struct {
+		ID    int `json:"id"`
+		Theme struct {
+			Dark bool `json:"dark"`
+		} `json:"theme"`
+		Owner *User `json:"owner"`
+	}
+
+	t.Run("generates aliases", func(t *testing.T) {
+		got, err := New(WithTypeAlias(true)).GenerateInterface(Settings{})
+		if err != nil {
+			t.Fatalf("GenerateInterface() error = %v", err)
+		}
+
+		expected := `export type Settings = {
+  id: number;
+  theme: { dark: boolean };
+  owner?: User;
+};`
+		if got != expected {
+			t.Errorf("GenerateInterface() =\n%v\n\nwant:\n%v", got, expected)
+		}
+	})
+
+	t.Run("applies to referenced types", func(t *testing.T) {
+		gen := New(WithTypeAlias(true))
+		gen.Register("PageProps", PageProps{})
+		dir := t.TempDir()
+		if err := gen.GenerateDir(dir); err != nil {
+			t.Fatalf("GenerateDir() error = %v", err)
+		}
+
+		for _, name := range []string{"PageProps", "Post", "User"} {
+			content, err := os.ReadFile(filepath.Join(dir, name+".ts"))
+			if err != nil {
+				t.Fatalf("failed to read %s.ts: %v", name, err)
+			}
+			got := string(content)
+			if !strings.Contains(got, "export type "+name+" = {\n") || !strings.HasSuffix(got, "};\n") {
+				t.Errorf("expected %s.ts to declare an alias, got:\n%s", name, got)
+			}
+			if strings.Contains(got, "interface") {
+				t.Errorf("unexpected interface in %s.ts:\n%s", name, got)
+			}
+			if strings.Count(got, "{") != strings.Count(got, "}") {
+				t.Errorf("unbalanced braces in %s.ts:\n%s", name, got)
+			}
+		}
+	})
+}
+
+func TestWithNullable(t *testing.T) {
+	type Profile struct {
+		Bio      string  `json:"bio,omitempty"`
+		Avatar   *string `json:"avatar"`
+		Nickname *string `json:"nickname,omitempty"`
+		Name     string  `json:"name"`
+	}
+
+	tests := []struct {
+		name     string
+		opts     []Option
+		expected string
+	}{
+		{
+			name: "default",
+			expected: `export interface Profile {
+  bio?: string;
+  avatar?: string;
+  nickname?: string;
+  name: string;
+}`,
+		},
+		{
+			name: "nullable",
+			opts: []Option{WithNullable(true)},
+			expected: `export interface Profile {
+  bio?: string;
+  avatar: string | null;
+  nickname?: string;
+  name: string;
+}`,
+		},
+		{
+			name: "nullable optional",
+			opts: []Option{WithNullableOptional(true)},
+			expected: `export interface Profile {
+  bio?: string;
+  avatar?: string | null;
+  nickname?: string | null;
+  name: string;
+}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(tt.opts...).GenerateInterface(Profile{})
+			if err != nil {
+				t.Fatalf("GenerateInterface() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("GenerateInterface() =\n%v\n\nwant:\n%v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	gen := New()
+	gen.Register("User", User{})
+
+	outputPath := filepath.Join(t.TempDir(), "types.ts")
+
+	if err := gen.Check(outputPath); !errors.Is(err, ErrOutdated) {
+		t.Errorf("Check() on missing file = %v, want ErrOutdated", err)
+	}
+
+	if err := gen.GenerateFile(outputPath); err != nil {
+		t.Fatalf("GenerateFile() error = %v", err)
+	}
+
+	t.Run("up to date", func(t *testing.T) {
+		if err := gen.Check(outputPath); err != nil {
+			t.Errorf("Check() error = %v", err)
+		}
+	})
+
+	t.Run("stale", func(t *testing.T) {
+		gen.Register("Post", Post{})
+		if err := gen.Check(outputPath); !errors.Is(err, ErrOutdated) {
+			t.Errorf("Check() = %v, want ErrOutdated", err)
+		}
+	})
+}
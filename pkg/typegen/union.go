@@ -0,0 +1,162 @@
+package typegen
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// defaultDiscriminator is the JSON field that tells union variants apart.
+const defaultDiscriminator = "type"
+
+// union is a TypeScript union of struct types, see RegisterUnion.
+type union struct {
+	name     string
+	iface    reflect.Type // Go interface mapped to the union, nil if none
+	variants []reflect.Type
+}
+
+// WithDiscriminator sets the JSON field whose value tells the variants of
+// registered unions apart (default "type").
+func WithDiscriminator(field string) Option {
+	return func(g *Generator) {
+		g.discriminator = field
+	}
+}
+
+// RegisterUnion generates a union of the variant structs, e.g.
+// `export type Event = UserCreated | UserDeleted;`, along with their interfaces.
+// A nil pointer to a Go interface among the variants, e.g. (*Event)(nil), maps
+// fields of that interface type to the union instead of any. Other interfaces,
+// even with the same name, are not affected.
+//
+// The variants are discriminated by the field set with WithDiscriminator: when a
+// variant value sets it, the generated field has that literal type, so
+//
+//	g.RegisterUnion("Event", (*Event)(nil), UserCreated{Type: "user.created"}, UserDeleted{Type: "user.deleted"})
+//
+// generates `type: "user.created";` in the UserCreated interface. Unions are
+// emitted by GenerateFile, GenerateDir and Check.
+func (g *Generator) RegisterUnion(name string, variants ...interface{}) {
+	u := &union{name: name}
+	for _, variant := range variants {
+		if t := reflect.TypeOf(variant); t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Interface {
+			u.iface = t.Elem()
+			continue
+		}
+
+		v := reflect.ValueOf(variant)
+		if v.Kind() == reflect.Ptr {
+			v = v.Elem()
+		}
+		u.variants = append(u.variants, v.Type())
+
+		if tag := discriminatorValue(v, g.discriminator); tag != "" {
+			g.variantTags[v.Type()] = tag
+		}
+	}
+	g.unions[name] = u
+	if u.iface != nil {
+		g.unionTypes[u.iface] = u
+	}
+}
+
+// discriminatorValue returns the string value of the struct field serialized
+// as field, or "" if v has no such field or it is empty.
+func discriminatorValue(v reflect.Value, field string) string {
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+	for i := 0; i < v.NumField(); i++ {
+		info, ok := jsonFieldInfo(v.Type().Field(i))
+		if ok && info.name == field && v.Field(i).Kind() == reflect.String {
+			return v.Field(i).String()
+		}
+	}
+	return ""
+}
+
+// unionFor returns the union registered for an interface type.
+func (g *Generator) unionFor(t reflect.Type) (*union, bool) {
+	if t.Kind() != reflect.Interface {
+		return nil, false
+	}
+	u, ok := g.unionTypes[t]
+	return u, ok
+}
+
+// generateUnions returns the interfaces of union variants not in emitted,
+// followed by the union types, in name order.
+func (g *Generator) generateUnions(emitted map[reflect.Type]bool) ([]string, error) {
+	var variants []reflect.Type
+	for _, u := range g.unions {
+		for _, t := range u.variants {
+			if !emitted[t] {
+				emitted[t] = true
+				variants = append(variants, t)
+			}
+		}
+	}
+	sort.Slice(variants, func(i, j int) bool { return variants[i].Name() < variants[j].Name() })
+
+	blocks := make([]string, 0, len(variants)+len(g.unions))
+	for _, t := range variants {
+		iface, err := g.generateInterface(t)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate interface for %s: %w", t.Name(), err)
+		}
+		blocks = append(blocks, iface)
+	}
+
+	for _, name := range g.unionNames() {
+		blocks = append(blocks, g.unions[name].declaration())
+	}
+
+	return blocks, nil
+}
+
+// unionNames returns the names of the registered unions, sorted.
+func (g *Generator) unionNames() []string {
+	names := make([]string, 0, len(g.unions))
+	for name := range g.unions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// declaration returns the TypeScript declaration of the union.
+func (u *union) declaration() string {
+	members := make([]string, len(u.variants))
+	for idx, t := range u.variants {
+		members[idx] = t.Name()
+	}
+	if len(members) == 0 {
+		members = []string{"never"}
+	}
+	return fmt.Sprintf("export type %s = %s;", u.name, strings.Join(members, " | "))
+}
+
+// declName returns the TypeScript name declared for t: the union name for an
+// interface registered with RegisterUnion, otherwise the Go type name.
+func (g *Generator) declName(t reflect.Type) string {
+	if u, ok := g.unionFor(t); ok {
+		return u.name
+	}
+	return t.Name()
+}
+
+// discriminatorLiteral returns the literal TypeScript type of the discriminator
+// field of a union variant.
+func (g *Generator) discriminatorLiteral(t reflect.Type, fieldName string) (string, bool) {
+	if fieldName != g.discriminator {
+		return "", false
+	}
+	tag, ok := g.variantTags[t]
+	if !ok {
+		return "", false
+	}
+	return strconv.Quote(tag), true
+}
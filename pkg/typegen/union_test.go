@@ -0,0 +1,123 @@
+package typegen
+
+import (
+	"strings"
+	"testing"
+)
+
+type Event interface {
+	EventName() string
+}
+
+type UserCreated struct {
+	Type   string `json:"type"`
+	UserID int    `json:"user_id"`
+}
+
+func (UserCreated) EventName() string { return "user.created" }
+
+type UserDeleted struct {
+	Type   string `json:"type"`
+	UserID int    `json:"user_id"`
+	Reason string `json:"reason,omitempty"`
+}
+
+func (UserDeleted) EventName() string { return "user.deleted" }
+
+type ActivityFeed struct {
+	Events []Event `json:"events"`
+	Latest Event   `json:"latest"`
+	Meta   any     `json:"meta"`
+}
+
+func TestRegisterUnion(t *testing.T) {
+	g := New()
+	g.Register("ActivityFeed", ActivityFeed{})
+	g.RegisterUnion("Event", (*Event)(nil), UserCreated{Type: "user.created"}, &UserDeleted{Type: "user.deleted"})
+
+	got, err := g.generateFile(g.types)
+	if err != nil {
+		t.Fatalf("generateFile() error = %v", err)
+	}
+
+	want := fileHeader + `export interface ActivityFeed {
+  events: Event[];
+  latest: Event;
+  meta: any;
+}
+
+export interface UserCreated {
+  type: "user.created";
+  user_id: number;
+}
+
+export interface UserDeleted {
+  type: "user.deleted";
+  user_id: number;
+  reason?: string;
+}
+
+export type Event = UserCreated | UserDeleted;`
+	if got != want {
+		t.Errorf("generateFile() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRegisterUnion_Discriminator(t *testing.T) {
+	type Circle struct {
+		Kind   string  `json:"kind"`
+		Radius float64 `json:"radius"`
+	}
+	type Square struct {
+		Kind string  `json:"kind"`
+		Side float64 `json:"side"`
+	}
+
+	g := New(WithDiscriminator("kind"))
+	g.Register("Circle", Circle{})
+	g.RegisterUnion("Shape", Circle{Kind: "circle"}, Square{})
+
+	got, err := g.generateFile(g.types)
+	if err != nil {
+		t.Fatalf("generateFile() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"export interface Circle {\n  kind: \"circle\";\n  radius: number;\n}",
+		"export interface Square {\n  kind: string;\n  side: number;\n}",
+		"export type Shape = Circle | Square;",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, got)
+		}
+	}
+	if strings.Count(got, "export interface Circle") != 1 {
+		t.Errorf("expected Circle to be generated once, got:\n%s", got)
+	}
+}
+
+func TestRegisterUnion_SameNameInterface(t *testing.T) {
+	// A different interface that is also called Event
+	type Event interface{ Local() }
+	type Feed struct {
+		Local Event `json:"local"`
+	}
+
+	g := New()
+	g.Register("Feed", Feed{})
+	g.RegisterUnion("Event", (*typegenEvent)(nil), UserCreated{})
+
+	got, err := g.generateFile(g.types)
+	if err != nil {
+		t.Fatalf("generateFile() error = %v", err)
+	}
+	if !strings.Contains(got, "local: any;") {
+		t.Errorf("expected unregistered interface to map to any, got:\n%s", got)
+	}
+	if !strings.Contains(got, "export type Event = UserCreated;") {
+		t.Errorf("expected Event union, got:\n%s", got)
+	}
+}
+
+// typegenEvent is the package-level Event, reachable from tests that shadow it.
+type typegenEvent = Event
@@ -1,9 +1,12 @@
 package typegen
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
@@ -14,6 +17,9 @@ import (
 type Watcher struct {
 	watcher      *fsnotify.Watcher
 	files        map[string]bool
+	dirs         map[string]bool
+	include      []string
+	ignore       []string
 	outputPath   string
 	generator    func() error
 	errorHandler func(error)
@@ -27,6 +33,8 @@ type Watcher struct {
 func NewWatcher() *Watcher {
 	return &Watcher{
 		files:    make(map[string]bool),
+		dirs:     make(map[string]bool),
+		ignore:   []string{"*_test.go"},
 		debounce: 300 * time.Millisecond,
 		stopCh:   make(chan struct{}),
 	}
@@ -63,8 +71,8 @@ func (w *Watcher) AddDirectory(dir string) error {
 
 	w.mu.Lock()
 	for _, entry := range entries {
-		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".go" {
-			path := filepath.Join(dir, entry.Name())
+		path := filepath.Join(dir, entry.Name())
+		if !entry.IsDir() && w.matches(path) {
 			w.files[path] = true
 		}
 	}
@@ -73,6 +81,114 @@ func (w *Watcher) AddDirectory(dir string) error {
 	return nil
 }
 
+// AddDirectoryRecursive adds all Go files in dir and its subdirectories to watch.
+// Each directory is watched as well, so Go files and directories created later
+// also trigger regeneration. Directories ignored by the go tool (testdata and
+// names starting with "." or "_") and files excluded by the include and ignore
+// patterns are skipped.
+func (w *Watcher) AddDirectoryRecursive(dir string) error {
+	if info, err := os.Stat(dir); err != nil {
+		return fmt.Errorf("directory does not exist: %w", err)
+	} else if !info.IsDir() {
+		return fmt.Errorf("path is not a directory: %s", dir)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			if path != dir && skipDir(entry.Name()) {
+				return filepath.SkipDir
+			}
+			w.dirs[path] = true
+			return nil
+		}
+
+		if w.matches(path) {
+			w.files[path] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read directory: %w", err)
+	}
+
+	return nil
+}
+
+// skipDir reports whether a directory is ignored by the go tool.
+func skipDir(name string) bool {
+	return name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
+
+// SetIncludePatterns restricts the Go files picked up from directories to those
+// matching at least one glob (e.g. "*_props.go"). Patterns without a path
+// separator match the file name, others the full path. With no patterns, every
+// Go file is included. Set patterns before adding directories.
+func (w *Watcher) SetIncludePatterns(globs ...string) {
+	w.mu.Lock()
+	w.include = globs
+	w.mu.Unlock()
+}
+
+// SetIgnorePatterns sets globs for Go files that are not watched, replacing the
+// default of "*_test.go". Patterns match like SetIncludePatterns. Files added
+// explicitly with AddFile are always watched.
+func (w *Watcher) SetIgnorePatterns(globs ...string) {
+	w.mu.Lock()
+	w.ignore = globs
+	w.mu.Unlock()
+}
+
+// matches reports whether a Go file passes the include and ignore patterns.
+// The caller must hold w.mu.
+func (w *Watcher) matches(path string) bool {
+	if filepath.Ext(path) != ".go" {
+		return false
+	}
+
+	for _, glob := range w.ignore {
+		if matchGlob(glob, path) {
+			return false
+		}
+	}
+
+	if len(w.include) == 0 {
+		return true
+	}
+	for _, glob := range w.include {
+		if matchGlob(glob, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob matches a glob against the file name, or against the full path when
+// the glob contains a path separator.
+func matchGlob(glob, path string) bool {
+	name := filepath.Base(path)
+	if strings.ContainsRune(filepath.ToSlash(glob), '/') {
+		name = filepath.ToSlash(path)
+		glob = filepath.ToSlash(glob)
+	}
+	ok, err := filepath.Match(glob, name)
+	return err == nil && ok
+}
+
+// shouldRegenerate reports whether a change to path should trigger generation.
+func (w *Watcher) shouldRegenerate(path string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.files[path] || w.matches(path)
+}
+
 // SetOutput sets the output path for generated TypeScript files.
 func (w *Watcher) SetOutput(path string) {
 	w.mu.Lock()
@@ -110,7 +226,7 @@ func (w *Watcher) Watch() error {
 	}
 	defer w.watcher.Close()
 
-	// Add all files to watcher
+	// Add all files and directories to watcher
 	w.mu.Lock()
 	for file := range w.files {
 		if err := w.watcher.Add(file); err != nil {
@@ -118,6 +234,12 @@ func (w *Watcher) Watch() error {
 			return fmt.Errorf("failed to watch file %s: %w", file, err)
 		}
 	}
+	for dir := range w.dirs {
+		if err := w.watcher.Add(dir); err != nil {
+			w.mu.Unlock()
+			return fmt.Errorf("failed to watch directory %s: %w", dir, err)
+		}
+	}
 	w.mu.Unlock()
 
 	// Initial generation
@@ -134,11 +256,13 @@ func (w *Watcher) Watch() error {
 				return nil
 			}
 
-			// Only care about write and create events for Go files
-			if event.Op&(fsnotify.Write|fsnotify.Create) != 0 {
-				if filepath.Ext(event.Name) == ".go" {
-					w.debounceGenerate()
-				}
+			if event.Op&fsnotify.Create != 0 && w.watchNewDirectory(event.Name) {
+				continue
+			}
+
+			// Only care about write and create events for watched Go files
+			if event.Op&(fsnotify.Write|fsnotify.Create) != 0 && w.shouldRegenerate(event.Name) {
+				w.debounceGenerate()
 			}
 
 		case err, ok := <-w.watcher.Errors:
@@ -150,6 +274,75 @@ func (w *Watcher) Watch() error {
 	}
 }
 
+// watchNewDirectory starts watching a directory created inside a recursively
+// watched tree, along with its subdirectories, and reports whether path was a
+// directory. Directories are skipped as in AddDirectoryRecursive. Go files moved
+// in together with the directory trigger regeneration.
+func (w *Watcher) watchNewDirectory(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || !info.IsDir() {
+		return false
+	}
+
+	w.mu.Lock()
+	watched := w.dirs[filepath.Dir(path)] && !skipDir(info.Name())
+	w.mu.Unlock()
+
+	if !watched {
+		return true
+	}
+
+	var dirs, files []string
+	err = filepath.WalkDir(path, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() {
+			files = append(files, p)
+			return nil
+		}
+		if p != path && skipDir(entry.Name()) {
+			return filepath.SkipDir
+		}
+		dirs = append(dirs, p)
+		return nil
+	})
+	if err != nil {
+		w.handleError(fmt.Errorf("failed to read directory %s: %w", path, err))
+	}
+
+	for _, dir := range dirs {
+		if err := w.watcher.Add(dir); err != nil {
+			w.handleError(fmt.Errorf("failed to watch directory %s: %w", dir, err))
+			continue
+		}
+		w.mu.Lock()
+		w.dirs[dir] = true
+		w.mu.Unlock()
+	}
+
+	for _, file := range files {
+		if w.shouldRegenerate(file) {
+			w.debounceGenerate()
+			break
+		}
+	}
+	return true
+}
+
+// GenerateOnce runs the generator a single time and returns its error, without
+// watching for changes. It is intended for CI and build scripts.
+func (w *Watcher) GenerateOnce() error {
+	w.mu.Lock()
+	gen := w.generator
+	w.mu.Unlock()
+
+	if gen == nil {
+		return errors.New("no generator set")
+	}
+	return gen()
+}
+
 // Stop stops the watcher.
 func (w *Watcher) Stop() {
 	close(w.stopCh)
@@ -1,6 +1,7 @@
 package typegen
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"sync/atomic"
@@ -334,6 +335,179 @@ func TestWatcher_AddDirectory(t *testing.T) {
 	}
 }
 
+func TestWatcher_Patterns(t *testing.T) {
+	t.Run("test files are ignored by default", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		source := filepath.Join(tmpDir, "user.go")
+		testFile := filepath.Join(tmpDir, "user_test.go")
+		for _, file := range []string{source, testFile} {
+			if err := os.WriteFile(file, []byte("package test"), 0600); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		watcher := NewWatcher()
+		watcher.SetDebounce(50 * time.Millisecond)
+		if err := watcher.AddDirectory(tmpDir); err != nil {
+			t.Fatalf("AddDirectory failed: %v", err)
+		}
+		if watcher.files[testFile] {
+			t.Error("test file should not be watched")
+		}
+
+		var generated atomic.Int32
+		watcher.SetGenerator(func() error {
+			generated.Add(1)
+			return nil
+		})
+
+		go watcher.Watch()
+		defer watcher.Stop()
+
+		time.Sleep(200 * time.Millisecond)
+		initialGen := generated.Load()
+
+		if err := os.WriteFile(testFile, []byte("package test\n// Modified"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(300 * time.Millisecond)
+		if generated.Load() != initialGen {
+			t.Error("test file change triggered regeneration")
+		}
+
+		if err := os.WriteFile(source, []byte("package test\n// Modified"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(300 * time.Millisecond)
+		if generated.Load() <= initialGen {
+			t.Error("expected regeneration after source change")
+		}
+	})
+
+	t.Run("include and ignore patterns", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		for _, name := range []string{"user_props.go", "post_props.go", "handlers.go", "legacy_props.go"} {
+			if err := os.WriteFile(filepath.Join(tmpDir, name), []byte("package test"), 0600); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		watcher := NewWatcher()
+		watcher.SetIncludePatterns("*_props.go")
+		watcher.SetIgnorePatterns("legacy_*")
+		if err := watcher.AddDirectory(tmpDir); err != nil {
+			t.Fatalf("AddDirectory failed: %v", err)
+		}
+
+		want := map[string]bool{
+			filepath.Join(tmpDir, "user_props.go"): true,
+			filepath.Join(tmpDir, "post_props.go"): true,
+		}
+		if len(watcher.files) != len(want) {
+			t.Errorf("watched files = %v, want %v", watcher.files, want)
+		}
+		for file := range want {
+			if !watcher.files[file] {
+				t.Errorf("expected %s to be watched", file)
+			}
+		}
+	})
+}
+
+func TestWatcher_AddDirectoryRecursive(t *testing.T) {
+	root := t.TempDir()
+	deep := filepath.Join(root, "billing", "invoices")
+	for _, dir := range []string{deep, filepath.Join(root, "testdata"), filepath.Join(root, ".cache")} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files := map[string]bool{
+		filepath.Join(root, "user.go"):                true,
+		filepath.Join(deep, "invoice.go"):             true,
+		filepath.Join(deep, "invoice_test.go"):        false,
+		filepath.Join(root, "testdata", "fixture.go"): false,
+		filepath.Join(root, ".cache", "cached.go"):    false,
+	}
+	for file := range files {
+		if err := os.WriteFile(file, []byte("package test"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	watcher := NewWatcher()
+	watcher.SetDebounce(50 * time.Millisecond)
+	if err := watcher.AddDirectoryRecursive(root); err != nil {
+		t.Fatalf("AddDirectoryRecursive failed: %v", err)
+	}
+	for file, want := range files {
+		if watcher.files[file] != want {
+			t.Errorf("watching %s = %v, want %v", file, watcher.files[file], want)
+		}
+	}
+
+	var generated atomic.Int32
+	watcher.SetGenerator(func() error {
+		generated.Add(1)
+		return nil
+	})
+
+	go watcher.Watch()
+	defer watcher.Stop()
+	time.Sleep(200 * time.Millisecond)
+
+	// waitForRegeneration fails unless a generation happens after change runs.
+	waitForRegeneration := func(name string, change func() error) {
+		t.Helper()
+		before := generated.Load()
+		if err := change(); err != nil {
+			t.Fatal(err)
+		}
+
+		deadline := time.Now().Add(2 * time.Second)
+		for time.Now().Before(deadline) {
+			if generated.Load() > before {
+				return
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		t.Errorf("%s did not trigger regeneration", name)
+	}
+
+	waitForRegeneration("deep change", func() error {
+		return os.WriteFile(filepath.Join(deep, "invoice.go"), []byte("package test\n// Modified"), 0600)
+	})
+	waitForRegeneration("new file in nested directory", func() error {
+		return os.WriteFile(filepath.Join(deep, "payment.go"), []byte("package test"), 0600)
+	})
+	waitForRegeneration("file in new directory", func() error {
+		dir := filepath.Join(root, "billing", "refunds")
+		if err := os.Mkdir(dir, 0755); err != nil {
+			return err
+		}
+		time.Sleep(100 * time.Millisecond)
+		return os.WriteFile(filepath.Join(dir, "refund.go"), []byte("package test"), 0600)
+	})
+	waitForRegeneration("file in new nested directory", func() error {
+		dir := filepath.Join(root, "billing", "credits", "notes")
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Join(root, "billing", "credits", "testdata"), 0755); err != nil {
+			return err
+		}
+		time.Sleep(100 * time.Millisecond)
+		return os.WriteFile(filepath.Join(dir, "note.go"), []byte("package test"), 0600)
+	})
+
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+	if watcher.dirs[filepath.Join(root, "billing", "credits", "testdata")] {
+		t.Error("testdata in a new directory should not be watched")
+	}
+}
+
 func TestWatcher_Stop(t *testing.T) {
 	tmpDir := t.TempDir()
 	goFile := filepath.Join(tmpDir, "test.go")
@@ -431,3 +605,28 @@ func TestWatcher_InvalidPath(t *testing.T) {
 		t.Error("Expected error for non-existent directory")
 	}
 }
+
+func TestWatcher_GenerateOnce(t *testing.T) {
+	watcher := NewWatcher()
+	if err := watcher.GenerateOnce(); err == nil {
+		t.Error("expected error without a generator")
+	}
+
+	var generated atomic.Int32
+	watcher.SetGenerator(func() error {
+		if generated.Add(1) > 1 {
+			return errors.New("generation failed")
+		}
+		return nil
+	})
+
+	if err := watcher.GenerateOnce(); err != nil {
+		t.Errorf("GenerateOnce() error = %v", err)
+	}
+	if err := watcher.GenerateOnce(); err == nil {
+		t.Error("expected generator error to be returned")
+	}
+	if generated.Load() != 2 {
+		t.Errorf("expected 2 generations, got %d", generated.Load())
+	}
+}